@@ -0,0 +1,119 @@
+// Command command runs a bot command from the command line and prints its reply, e.g.
+//
+//	go run ./cmd/command -tenant default -user 123456789 /volume 123456
+//
+// the invite links of the telegram groups are issued by the running bot only, /join is not available
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/command"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"ohmycontrolcenter.tech/omcc/pkg/exception"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func main() {
+	configPath := flag.String("config", "configs", "directory of the config files")
+	tenantId := flag.String("tenant", common.DefaultTenantID, "id of the tenant running the command")
+	platform := flag.String("platform", common.Telegram.Name(), "platform the command is sent from, TELEGRAM or LINE")
+	userId := flag.String("user", "", "id of the sender on the platform, required")
+	username := flag.String("username", "", "username of the sender")
+	language := flag.String("lang", "", "language of the reply, zh-TW, zh-CN or en")
+	timeout := flag.Duration("timeout", time.Minute, "timeout of the command")
+	flag.Parse()
+
+	if err := run(*configPath, *tenantId, *platform, *userId, *username, *language, *timeout, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(configPath string, tenantId string, platformName string, userId string, username string, language string,
+	timeout time.Duration, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: command [flags] /<command> [args]")
+	}
+	platform, ok := common.GetSocialPlatformFromName(strings.ToUpper(platformName))
+	if !ok {
+		return fmt.Errorf("unknown platform %q", platformName)
+	}
+	if userId == "" {
+		return errors.New("-user is required")
+	}
+	if _, err := strconv.ParseInt(userId, 10, 64); platform == common.Telegram && err != nil {
+		return fmt.Errorf("invalid telegram user id %q", userId)
+	}
+
+	log := logger.NewLogger()
+	defer func() { _ = log.Sync() }()
+
+	cfg, err := config.NewConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	tenantCfg, err := findTenant(cfg, tenantId)
+	if err != nil {
+		return err
+	}
+
+	defaultLang, ok := i18n.ParseLang(tenantCfg.Telegram.DefaultLanguage)
+	if !ok {
+		defaultLang = i18n.Default
+	}
+	lang, _ := i18n.ParseLang(language)
+	db, err := database.NewMySqlClient(&tenantCfg.Database, log)
+	if err != nil {
+		return err
+	}
+	dispatcher := command.NewDispatcher(log, i18n.NewLocalizer(defaultLang), command.NewServices(tenantCfg, db, log))
+
+	ctx, cancel := context.WithTimeout(database.WithTenant(context.Background(), tenantId), timeout)
+	defer cancel()
+	name, cmdArgs := command.ParseText(strings.Join(args, " "))
+	reply, err := dispatcher.Execute(ctx, &command.Request{
+		Platform: platform,
+		User:     command.User{Id: userId, Username: username},
+		Lang:     lang,
+		Name:     name,
+		Args:     cmdArgs,
+		Progress: func(_ context.Context, msg command.Message) error {
+			fmt.Println(msg.Text)
+			return nil
+		},
+	})
+	if err != nil {
+		var cmdErr *exception.CommandError
+		if errors.As(err, &cmdErr) {
+			return fmt.Errorf("%s", dispatcher.Localize(lang, cmdErr))
+		}
+		return err
+	}
+	for _, text := range reply.Texts() {
+		fmt.Println(text)
+	}
+	return nil
+}
+
+func findTenant(cfg *config.Config, tenantId string) (*config.Config, error) {
+	tenants, err := cfg.TenantConfigs()
+	if err != nil {
+		return nil, err
+	}
+	for _, tenant := range tenants {
+		if tenant.Tenant.ID == tenantId {
+			return tenant, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown tenant %q", tenantId)
+}
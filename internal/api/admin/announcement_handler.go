@@ -0,0 +1,162 @@
+package admin
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/announcement"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+)
+
+type AnnouncementHandler struct {
+	announcementService announcement.AnnouncementServiceInterface
+	log                 logger.Logger
+}
+
+func NewAnnouncementHandler(announcementService announcement.AnnouncementServiceInterface, log logger.Logger) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		announcementService: announcementService,
+		log:                 log,
+	}
+}
+
+func (h *AnnouncementHandler) GetAnnouncements(c *gin.Context) {
+	groupId, err := optionalInt64(c, "group_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_id must be a number"})
+		return
+	}
+
+	announcements, err := h.announcementService.GetAnnouncements(c.Request.Context(), groupId)
+	if err != nil {
+		h.log.Error("failed to get announcements",
+			logger.Int64("group_id", groupId),
+			logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, announcements)
+}
+
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	var req model.AnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	created, err := h.announcementService.CreateAnnouncement(c.Request.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to create announcement",
+			logger.Int64("group_id", req.GroupId),
+			logger.Error(err))
+		c.JSON(announcementErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, created)
+}
+
+func (h *AnnouncementHandler) UpdateAnnouncement(c *gin.Context) {
+	var req model.AnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Id == 0 {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	updated, err := h.announcementService.UpdateAnnouncement(c.Request.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to update announcement",
+			logger.Int64("id", req.Id),
+			logger.Error(err))
+		c.JSON(announcementErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *AnnouncementHandler) DeleteAnnouncement(c *gin.Context) {
+	var req model.DeleteAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	if err := h.announcementService.DeleteAnnouncement(c.Request.Context(), req.Id); err != nil {
+		h.log.Error("failed to delete announcement",
+			logger.Int64("id", req.Id),
+			logger.Error(err))
+		c.JSON(announcementErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "Success",
+		"message": "Announcement deleted successfully",
+	})
+}
+
+func (h *AnnouncementHandler) GetRuns(c *gin.Context) {
+	announcementId, err := optionalInt64(c, "announcement_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "announcement_id must be a number"})
+		return
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page must be a number"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+		return
+	}
+
+	runs, err := h.announcementService.GetRuns(c.Request.Context(), announcementId, page, limit)
+	if err != nil {
+		h.log.Error("failed to get announcement runs",
+			logger.Int64("announcement_id", announcementId),
+			logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+func announcementErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, announcement.ErrInvalidAnnouncement):
+		return http.StatusBadRequest
+	case errors.Is(err, announcement.ErrAnnouncementNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,133 @@
+package admin
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/message"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+)
+
+type BroadcastHandler struct {
+	broadcastService message.BroadcastServiceInterface
+	log              logger.Logger
+}
+
+func NewBroadcastHandler(broadcastService message.BroadcastServiceInterface, log logger.Logger) *BroadcastHandler {
+	return &BroadcastHandler{
+		broadcastService: broadcastService,
+		log:              log,
+	}
+}
+
+func (h *BroadcastHandler) CreateBroadcast(c *gin.Context) {
+	var req model.BroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	job, err := h.broadcastService.CreateBroadcast(c.Request.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to create broadcast",
+			logger.String("audience", req.Audience),
+			logger.Error(err))
+		c.JSON(broadcastErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *BroadcastHandler) GetBroadcasts(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page must be a number"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+		return
+	}
+
+	jobs, err := h.broadcastService.GetBroadcasts(c.Request.Context(), page, limit)
+	if err != nil {
+		h.log.Error("failed to get broadcasts",
+			logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+func (h *BroadcastHandler) GetProgress(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Query("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id must be a number"})
+		return
+	}
+
+	progress, err := h.broadcastService.GetProgress(c.Request.Context(), id)
+	if err != nil {
+		h.log.Error("failed to get broadcast progress",
+			logger.Int64("id", id),
+			logger.Error(err))
+		c.JSON(broadcastErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+func (h *BroadcastHandler) CancelBroadcast(c *gin.Context) {
+	var req model.CancelBroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	if err := h.broadcastService.CancelBroadcast(c.Request.Context(), req.Id); err != nil {
+		h.log.Error("failed to cancel broadcast",
+			logger.Int64("id", req.Id),
+			logger.Error(err))
+		c.JSON(broadcastErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "Success",
+		"message": "Broadcast cancelled successfully",
+	})
+}
+
+func broadcastErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, message.ErrInvalidBroadcast), errors.Is(err, message.ErrEmptyAudience):
+		return http.StatusBadRequest
+	case errors.Is(err, message.ErrBroadcastNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, message.ErrBroadcastFinished):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,91 @@
+package admin
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/command"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"ohmycontrolcenter.tech/omcc/pkg/exception"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+	"strings"
+)
+
+// CommandHandler runs the bot commands through the admin api, e.g. to answer a user on a support channel
+type CommandHandler struct {
+	// dispatchers commands of the bot of every tenant
+	dispatchers map[string]*command.Dispatcher
+	log         logger.Logger
+}
+
+func NewCommandHandler(dispatchers map[string]*command.Dispatcher, log logger.Logger) *CommandHandler {
+	return &CommandHandler{
+		dispatchers: dispatchers,
+		log:         log,
+	}
+}
+
+// RunCommand returns the reply of the command, a command failing returns 422 with its localized error
+func (h *CommandHandler) RunCommand(c *gin.Context) {
+	var req model.CommandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	tenantId, _ := database.TenantFromContext(c.Request.Context())
+	dispatcher, ok := h.dispatchers[tenantId]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no bot for the tenant"})
+		return
+	}
+
+	platform := common.Telegram
+	if req.Platform != "" {
+		if platform, ok = common.GetSocialPlatformFromName(strings.ToUpper(req.Platform)); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown platform " + req.Platform})
+			return
+		}
+	}
+	// the telegram user ids are numbers, a user id the bot cannot message would be bound by /verify
+	if _, err := strconv.ParseInt(req.UserId, 10, 64); platform == common.Telegram && err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid telegram user id " + req.UserId})
+		return
+	}
+	lang, _ := i18n.ParseLang(req.Language)
+	name, args := command.ParseText(req.Text)
+	reply, err := dispatcher.Execute(c.Request.Context(), &command.Request{
+		Platform: platform,
+		User: command.User{
+			Id:        req.UserId,
+			Username:  req.Username,
+			Firstname: req.Firstname,
+			Lastname:  req.Lastname,
+		},
+		Lang: lang,
+		Name: name,
+		Args: args,
+	})
+	if errors.Is(err, command.ErrUnknownCommand) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown command " + name})
+		return
+	}
+	var cmdErr *exception.CommandError
+	if errors.As(err, &cmdErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": dispatcher.Localize(lang, cmdErr),
+			"key":   cmdErr.Key,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, reply)
+}
@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/command"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"testing"
+)
+
+func TestCommandHandler_RunCommand(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewLogger()
+	dispatcher := command.NewDispatcher(log, i18n.NewLocalizer(i18n.ZhTW), command.Services{})
+	handler := NewCommandHandler(map[string]*command.Dispatcher{"": dispatcher}, log)
+	r := gin.New()
+	r.POST("/v1/admin/command", handler.RunCommand)
+
+	tests := []struct {
+		name         string
+		body         map[string]string
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:         "unknown platform",
+			body:         map[string]string{"platform": "TELEGARM", "user_id": "42", "text": "/verify 123"},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "unknown platform TELEGARM",
+		},
+		{
+			name:         "non-numeric telegram user id",
+			body:         map[string]string{"user_id": "U42", "text": "/verify 123"},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "invalid telegram user id U42",
+		},
+		{
+			name:         "unknown command",
+			body:         map[string]string{"platform": "line", "user_id": "U42", "text": "/hello"},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "unknown command /hello",
+		},
+		{
+			name:         "missing text",
+			body:         map[string]string{"user_id": "42"},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "Invalid request parameters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/v1/admin/command", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedErr, response["error"])
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
+	"ohmycontrolcenter.tech/omcc/internal/common"
 	"ohmycontrolcenter.tech/omcc/internal/domain/model"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
 	"testing"
@@ -52,6 +53,30 @@ func (m *MockCustomerService) GetAllCustomers(ctx context.Context, page, limit i
 	return args.Get(0).(*model.PaginatedResponse[*model.CustomerInfoResponse]), args.Error(1)
 }
 
+func (m *MockCustomerService) GetCustomerInfoBySocialUsername(ctx context.Context, username string) (*model.CustomerInfoResponse, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CustomerInfoResponse), args.Error(1)
+}
+
+func (m *MockCustomerService) UpdateCustomerStatusByUid(ctx context.Context, uid string, status common.Status, memberStatus common.MemberStatus) (*model.CustomerInfoResponse, error) {
+	args := m.Called(ctx, uid, status, memberStatus)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CustomerInfoResponse), args.Error(1)
+}
+
+func (m *MockCustomerService) GetCustomerStats(ctx context.Context) (*model.CustomerStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CustomerStats), args.Error(1)
+}
+
 func setupTestRouter(mockService *MockCustomerService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
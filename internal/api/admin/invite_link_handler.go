@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/invite"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+type InviteLinkHandler struct {
+	inviteLinkService invite.InviteLinkServiceInterface
+	log               logger.Logger
+}
+
+func NewInviteLinkHandler(inviteLinkService invite.InviteLinkServiceInterface, log logger.Logger) *InviteLinkHandler {
+	return &InviteLinkHandler{
+		inviteLinkService: inviteLinkService,
+		log:               log,
+	}
+}
+
+func (h *InviteLinkHandler) GetOutstandingLinks(c *gin.Context) {
+	customerId := c.Query("customer_id")
+	if customerId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "customer_id is required"})
+		return
+	}
+
+	links, err := h.inviteLinkService.GetOutstandingLinks(c.Request.Context(), customerId)
+	if err != nil {
+		h.log.Error("failed to get outstanding invite links",
+			logger.String("customer_id", customerId),
+			logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
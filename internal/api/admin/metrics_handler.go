@@ -0,0 +1,30 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/outbound"
+)
+
+type MetricsHandler struct {
+	// sendQueues send queue of the bot of every tenant
+	sendQueues map[string]*outbound.Queue
+}
+
+func NewMetricsHandler(sendQueues map[string]*outbound.Queue) *MetricsHandler {
+	return &MetricsHandler{
+		sendQueues: sendQueues,
+	}
+}
+
+// GetSendQueue returns the messages waiting in the send queue of the tenant per priority and its counters since start
+func (h *MetricsHandler) GetSendQueue(c *gin.Context) {
+	tenantId, _ := database.TenantFromContext(c.Request.Context())
+	sendQueue, ok := h.sendQueues[tenantId]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no send queue for the tenant"})
+		return
+	}
+	c.JSON(http.StatusOK, sendQueue.Stats())
+}
@@ -0,0 +1,429 @@
+package admin
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/moderation"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+	"time"
+)
+
+type ModerationHandler struct {
+	moderationService moderation.ModerationServiceInterface
+	log               logger.Logger
+}
+
+func NewModerationHandler(moderationService moderation.ModerationServiceInterface, log logger.Logger) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+		log:               log,
+	}
+}
+
+func (h *ModerationHandler) GetViolations(c *gin.Context) {
+	groupId, err := optionalInt64(c, "group_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_id must be a number"})
+		return
+	}
+	userId, err := optionalInt64(c, "user_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id must be a number"})
+		return
+	}
+
+	violations, err := h.moderationService.GetViolations(c.Request.Context(), groupId, userId)
+	if err != nil {
+		h.log.Error("failed to get user violations",
+			logger.Int64("group_id", groupId),
+			logger.Int64("user_id", userId),
+			logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, violations)
+}
+
+func (h *ModerationHandler) ClearViolations(c *gin.Context) {
+	var req model.ClearViolationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	if err := h.moderationService.ClearViolations(c.Request.Context(), &req); err != nil {
+		h.log.Error("failed to clear user violations",
+			logger.Int64("group_id", req.GroupId),
+			logger.Int64("user_id", req.UserId),
+			logger.Error(err))
+		status := http.StatusInternalServerError
+		if errors.Is(err, moderation.ErrViolationNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "Success",
+		"message": "User strikes cleared successfully",
+	})
+}
+
+func (h *ModerationHandler) GetRules(c *gin.Context) {
+	groupId, err := optionalInt64(c, "group_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_id must be a number"})
+		return
+	}
+
+	rules, err := h.moderationService.GetRules(c.Request.Context(), groupId)
+	if err != nil {
+		h.log.Error("failed to get moderation rules",
+			logger.Int64("group_id", groupId),
+			logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func (h *ModerationHandler) CreateRule(c *gin.Context) {
+	var req model.ModerationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	rule, err := h.moderationService.CreateRule(c.Request.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to create moderation rule",
+			logger.Int64("group_id", req.GroupId),
+			logger.Error(err))
+		c.JSON(ruleErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *ModerationHandler) UpdateRule(c *gin.Context) {
+	var req model.ModerationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Id == 0 {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	rule, err := h.moderationService.UpdateRule(c.Request.Context(), &req)
+	if err != nil {
+		h.log.Error("failed to update moderation rule",
+			logger.Int64("id", req.Id),
+			logger.Error(err))
+		c.JSON(ruleErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *ModerationHandler) DeleteRule(c *gin.Context) {
+	var req model.DeleteModerationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	if err := h.moderationService.DeleteRule(c.Request.Context(), req.Id); err != nil {
+		h.log.Error("failed to delete moderation rule",
+			logger.Int64("id", req.Id),
+			logger.Error(err))
+		c.JSON(ruleErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "Success",
+		"message": "Moderation rule deleted successfully",
+	})
+}
+
+func (h *ModerationHandler) GetTrustedUsers(c *gin.Context) {
+	groupId, err := optionalInt64(c, "group_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_id must be a number"})
+		return
+	}
+
+	users, err := h.moderationService.GetTrustedUsers(c.Request.Context(), groupId)
+	if err != nil {
+		h.log.Error("failed to get trusted users",
+			logger.Int64("group_id", groupId),
+			logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+func (h *ModerationHandler) AddTrustedUser(c *gin.Context) {
+	var req model.TrustedUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	if err := h.moderationService.AddTrustedUser(c.Request.Context(), &req); err != nil {
+		h.log.Error("failed to add trusted user",
+			logger.Int64("group_id", req.GroupId),
+			logger.Int64("user_id", req.UserId),
+			logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "Success",
+		"message": "Trusted user added successfully",
+	})
+}
+
+func (h *ModerationHandler) RemoveTrustedUser(c *gin.Context) {
+	var req model.TrustedUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	if err := h.moderationService.RemoveTrustedUser(c.Request.Context(), &req); err != nil {
+		h.log.Error("failed to remove trusted user",
+			logger.Int64("group_id", req.GroupId),
+			logger.Int64("user_id", req.UserId),
+			logger.Error(err))
+		status := http.StatusInternalServerError
+		if errors.Is(err, moderation.ErrTrustedUserNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "Success",
+		"message": "Trusted user removed successfully",
+	})
+}
+
+func (h *ModerationHandler) GetLogs(c *gin.Context) {
+	filter, err := moderationLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page must be a number"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+		return
+	}
+
+	logs, err := h.moderationService.GetLogs(c.Request.Context(), filter, page, limit)
+	if err != nil {
+		h.log.Error("failed to get moderation logs",
+			logger.Any("filter", filter),
+			logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
+func (h *ModerationHandler) MarkFalsePositive(c *gin.Context) {
+	var req model.MarkFalsePositiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	if err := h.moderationService.MarkFalsePositive(c.Request.Context(), req.Id); err != nil {
+		h.log.Error("failed to mark moderation log as false positive",
+			logger.Int64("id", req.Id),
+			logger.Error(err))
+		status := http.StatusInternalServerError
+		if errors.Is(err, moderation.ErrLogNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "Success",
+		"message": "Moderation log marked as false positive",
+	})
+}
+
+func (h *ModerationHandler) GetShadowReport(c *gin.Context) {
+	groupId, err := optionalInt64(c, "group_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_id must be a number"})
+		return
+	}
+	from, err := optionalTime(c, "from")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be an RFC 3339 time"})
+		return
+	}
+	to, err := optionalTime(c, "to")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be an RFC 3339 time"})
+		return
+	}
+	samples, err := strconv.Atoi(c.DefaultQuery("samples", "3"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "samples must be a number"})
+		return
+	}
+
+	report, err := h.moderationService.GetShadowReport(c.Request.Context(), groupId, from, to, samples)
+	if err != nil {
+		h.log.Error("failed to get shadow report",
+			logger.Int64("group_id", groupId),
+			logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// moderationLogFilter parses the optional filters of the moderation log, times are RFC 3339
+func moderationLogFilter(c *gin.Context) (*model.ModerationLogFilter, error) {
+	filter := &model.ModerationLogFilter{Action: c.Query("action")}
+	var err error
+	if filter.GroupId, err = optionalInt64(c, "group_id"); err != nil {
+		return nil, errors.New("group_id must be a number")
+	}
+	topicId, err := optionalInt64(c, "topic_id")
+	if err != nil {
+		return nil, errors.New("topic_id must be a number")
+	}
+	filter.TopicId = int(topicId)
+	if filter.UserId, err = optionalInt64(c, "user_id"); err != nil {
+		return nil, errors.New("user_id must be a number")
+	}
+	if filter.RuleId, err = optionalInt64(c, "rule_id"); err != nil {
+		return nil, errors.New("rule_id must be a number")
+	}
+	if value := c.Query("shadow"); value != "" {
+		shadow, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("shadow must be a boolean")
+		}
+		filter.Shadow = &shadow
+	}
+	if value := c.Query("false_positive"); value != "" {
+		falsePositive, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("false_positive must be a boolean")
+		}
+		filter.FalsePositive = &falsePositive
+	}
+	if filter.From, err = optionalTime(c, "from"); err != nil {
+		return nil, errors.New("from must be an RFC 3339 time")
+	}
+	if filter.To, err = optionalTime(c, "to"); err != nil {
+		return nil, errors.New("to must be an RFC 3339 time")
+	}
+	return filter, nil
+}
+
+func ruleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, moderation.ErrInvalidRule):
+		return http.StatusBadRequest
+	case errors.Is(err, moderation.ErrRuleNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// optionalInt64 parses the query parameter, 0 is returned when it is missing
+func optionalInt64(c *gin.Context, key string) (int64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// optionalTime parses the RFC 3339 query parameter, the zero time is returned when it is missing
+func optionalTime(c *gin.Context, key string) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,111 @@
+package admin
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/template"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+type TemplateHandler struct {
+	templateService template.TemplateServiceInterface
+	log             logger.Logger
+}
+
+func NewTemplateHandler(templateService template.TemplateServiceInterface, log logger.Logger) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+		log:             log,
+	}
+}
+
+func (h *TemplateHandler) GetAllTemplates(c *gin.Context) {
+	templates, err := h.templateService.GetAllTemplates(c.Request.Context())
+	if err != nil {
+		h.log.Error("failed to get message templates", logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+func (h *TemplateHandler) GetTemplateVersions(c *gin.Context) {
+	key := c.Query("key")
+	language := c.Query("language")
+	if key == "" || language == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "key and language are required"})
+		return
+	}
+
+	versions, err := h.templateService.GetTemplateVersions(c.Request.Context(), key, language)
+	if err != nil {
+		h.handleError(c, err, "failed to get message template versions", key)
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+func (h *TemplateHandler) PreviewTemplate(c *gin.Context) {
+	var req model.PreviewTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	preview, err := h.templateService.PreviewTemplate(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to preview message template", req.Key)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	var req model.UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("invalid request parameters",
+			logger.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request parameters",
+		})
+		return
+	}
+
+	updated, err := h.templateService.UpdateTemplate(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to update message template", req.Key)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":     "Success",
+		"message":  "Message template updated successfully",
+		"template": updated,
+	})
+}
+
+func (h *TemplateHandler) handleError(c *gin.Context, err error, message string, key string) {
+	h.log.Error(message,
+		logger.String("key", key),
+		logger.Error(err))
+	if errors.Is(err, template.ErrInvalidTemplate) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -4,60 +4,143 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"gorm.io/gorm"
 	"net/http"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
 	"ohmycontrolcenter.tech/omcc/internal/domain/bot"
+	"ohmycontrolcenter.tech/omcc/internal/domain/bot/line"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/template"
 	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
 	"ohmycontrolcenter.tech/omcc/internal/middleware"
 	"ohmycontrolcenter.tech/omcc/internal/server"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
 )
 
+const shutdownTimeout = 10 * time.Second
+
 type App struct {
 	cfg        *config.Config
 	log        logger.Logger
-	bot        *bot.TelegramBot
+	tenants    []*tenant
 	httpServer *server.HTTPServer
 	ctx        context.Context
 	cancel     context.CancelFunc
-	db         *gorm.DB
 }
 
-func NewApp(ctx context.Context, cfg *config.Config, log logger.Logger) (*App, error) {
-	ctx, cancel := context.WithCancel(ctx)
-
-	// init middleware
-	middlewareManager := middleware.NewManager(log)
+// tenant the bot of a tenant and the services it shares with the admin api
+type tenant struct {
+	cfg *config.Config
+	log logger.Logger
+	bot *bot.TelegramBot
+	// lineBot nil unless the LINE channel is enabled
+	lineBot         *line.LineBot
+	templateService *template.TemplateService
+}
 
-	// init telebot
-	b, err := bot.NewTelegramBot(cfg, log, middlewareManager)
+func NewApp(ctx context.Context, cfg *config.Config, log logger.Logger) (*App, error) {
+	tenantConfigs, err := cfg.TenantConfigs()
 	if err != nil {
-		cancel()
 		return nil, err
 	}
 
-	httpServer := server.NewHTTPServer(cfg, log)
+	ctx, cancel := context.WithCancel(ctx)
+
+	var tenants []*tenant
+	var serverTenants []*server.Tenant
+	for _, tenantCfg := range tenantConfigs {
+		t, err := newTenant(tenantCfg, log.With(logger.String("tenant", tenantCfg.Tenant.ID)))
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to init tenant %s: %w", tenantCfg.Tenant.ID, err)
+		}
+		tenants = append(tenants, t)
+		serverTenant := &server.Tenant{
+			ID:              tenantCfg.Tenant.ID,
+			AdminToken:      tenantCfg.Tenant.AdminToken,
+			TemplateService: t.templateService,
+			Webhook:         t.bot.WebhookHandler(),
+			WebhookPath:     tenantCfg.Telegram.WebhookPath(),
+			SendQueue:       t.bot.SendQueue(),
+			Commands:        t.bot.Dispatcher(),
+			Penalties:       &tenantCfg.Telegram.Penalties,
+		}
+		if t.lineBot != nil {
+			serverTenant.LineWebhook = t.lineBot
+			serverTenant.LineWebhookPath = tenantCfg.Line.WebhookPath
+		}
+		serverTenants = append(serverTenants, serverTenant)
+	}
+
+	httpServer := server.NewHTTPServer(cfg, log, serverTenants)
 
 	return &App{
 		cfg:        cfg,
 		log:        log,
-		bot:        b,
+		tenants:    tenants,
 		httpServer: httpServer,
 		ctx:        ctx,
 		cancel:     cancel,
 	}, nil
 }
 
+func newTenant(cfg *config.Config, log logger.Logger) (*tenant, error) {
+	db, err := database.NewMySqlClient(&cfg.Database, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// init localization
+	defaultLang, ok := i18n.ParseLang(cfg.Telegram.DefaultLanguage)
+	if !ok {
+		defaultLang = i18n.Default
+	}
+	localizer := i18n.NewLocalizer(defaultLang)
+	languageService := service.NewLanguageService(db, log)
+	templateService := template.NewTemplateService(db, localizer, log)
+
+	// init middleware
+	middlewareManager := middleware.NewManager(log, localizer, languageService, cfg.Telegram.AdminIds, cfg.Telegram.RateLimit)
+
+	// init telebot
+	b, err := bot.NewTelegramBot(cfg, log, db, middlewareManager, localizer, languageService)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &tenant{
+		cfg:             cfg,
+		log:             log,
+		bot:             b,
+		templateService: templateService,
+	}
+
+	// init the LINE bot, running the commands of the telegram bot offered on LINE
+	if cfg.Line.Enabled {
+		if cfg.Line.ChannelSecret == "" || cfg.Line.WebhookPath == "" {
+			return nil, errors.New("line channel secret and webhook path are required when line is enabled")
+		}
+		t.lineBot = line.NewLineBot(&cfg.Line, log.With(logger.String("platform", "line")), b.Dispatcher(), languageService)
+	}
+	return t, nil
+}
+
 func (a *App) Start() error {
 	a.log.Info("starting application with telebot and httpserver")
 
-	// start bot in goroutine
-	go func() {
-		err := a.bot.Start(a.ctx)
-		if err != nil {
-			a.log.Info("starting application failed")
-		}
-	}()
+	for _, t := range a.tenants {
+		// hot reload message templates edited through the admin api
+		go t.templateService.Watch(a.ctx, t.templateReloadInterval())
+
+		// start bot in goroutine
+		go func(t *tenant) {
+			err := t.bot.Start(a.ctx)
+			if err != nil {
+				t.log.Info("starting application failed")
+			}
+		}(t)
+	}
 
 	go func() {
 		a.log.Info(fmt.Sprintf("Started admin server listening on port=%s", a.cfg.Server.Port))
@@ -81,14 +164,30 @@ func (a *App) Stop() error {
 	// cancel ctx
 	a.cancel()
 
-	// stop bot
-	a.bot.Stop()
-	if err := a.httpServer.Stop(a.ctx); err != nil {
+	// stop the http server first, so the webhook updates it accepted reach the bot before it stops
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := a.httpServer.Stop(ctx); err != nil {
 		a.log.Error("failed to stop http server",
 			logger.Error(err),
 		)
 	}
 
+	// stop bots
+	for _, t := range a.tenants {
+		t.bot.Stop()
+		if t.lineBot != nil {
+			t.lineBot.Stop()
+		}
+	}
+
 	a.log.Info("application stopped successfully")
 	return nil
 }
+
+func (t *tenant) templateReloadInterval() time.Duration {
+	if t.cfg.Telegram.TemplateReloadInterval <= 0 {
+		return time.Minute
+	}
+	return t.cfg.Telegram.TemplateReloadInterval
+}
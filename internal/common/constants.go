@@ -6,6 +6,16 @@ type SocialPlatformType int
 type TradingPlatformType int
 type Status string
 type MemberStatus tele.MemberStatus
+type InviteLinkStatus string
+type CaptchaStatus string
+type PenaltyAction string
+type RuleMatchType string
+type RuleAction string
+type DelayedActionType string
+type DelayedActionStatus string
+type BroadcastAudience string
+type BroadcastStatus string
+type BroadcastRecipientStatus string
 
 // General ENV constants
 const (
@@ -56,6 +66,97 @@ const (
 	Unknown                    = "unknown"
 )
 
+const (
+	InviteLinkUnused  InviteLinkStatus = "unused"
+	InviteLinkUsed    InviteLinkStatus = "used"
+	InviteLinkRevoked InviteLinkStatus = "revoked"
+)
+
+const (
+	CaptchaPending CaptchaStatus = "pending"
+	CaptchaPassed  CaptchaStatus = "passed"
+	CaptchaFailed  CaptchaStatus = "failed"
+)
+
+const (
+	PenaltyWarn PenaltyAction = "warn"
+	PenaltyMute PenaltyAction = "mute"
+	PenaltyBan  PenaltyAction = "ban"
+)
+
+// MinMuteSeconds telegram treats a restriction shorter than 30 seconds as forever
+const MinMuteSeconds = 30
+
+const (
+	RuleMatchRegex           RuleMatchType = "regex"
+	RuleMatchKeyword         RuleMatchType = "keyword"
+	RuleMatchDomainAllowlist RuleMatchType = "domain_allowlist"
+	RuleMatchChannelForward  RuleMatchType = "channel_forward"
+)
+
+const (
+	RuleActionDelete RuleAction = "delete"
+	RuleActionWarn   RuleAction = "warn"
+	RuleActionMute   RuleAction = "mute"
+	RuleActionIgnore RuleAction = "ignore"
+)
+
+const (
+	DelayedDeleteMessage    DelayedActionType = "delete_message"
+	DelayedUnmute           DelayedActionType = "unmute"
+	DelayedRevokeInviteLink DelayedActionType = "revoke_invite_link"
+)
+
+const (
+	DelayedActionPending DelayedActionStatus = "pending"
+	DelayedActionDone    DelayedActionStatus = "done"
+	DelayedActionFailed  DelayedActionStatus = "failed"
+)
+
+const (
+	BroadcastAllActive   BroadcastAudience = "all_active"
+	BroadcastDeactivated BroadcastAudience = "deactivated"
+	BroadcastWhitelisted BroadcastAudience = "whitelisted"
+	BroadcastTier        BroadcastAudience = "tier"
+	BroadcastCustom      BroadcastAudience = "custom"
+)
+
+const (
+	BroadcastPending   BroadcastStatus = "pending"
+	BroadcastRunning   BroadcastStatus = "running"
+	BroadcastCompleted BroadcastStatus = "completed"
+	BroadcastCancelled BroadcastStatus = "cancelled"
+)
+
+const (
+	RecipientPending BroadcastRecipientStatus = "pending"
+	RecipientSent    BroadcastRecipientStatus = "sent"
+	RecipientFailed  BroadcastRecipientStatus = "failed"
+	RecipientSkipped BroadcastRecipientStatus = "skipped"
+)
+
+const (
+	BotModeWebhook string = "webhook"
+	BotModePolling        = "polling"
+)
+
+const (
+	WebhookListenerServer     string = "server"
+	WebhookListenerStandalone        = "standalone"
+)
+
+const (
+	CaptchaModeButton string = "button"
+	CaptchaModeMath          = "math"
+)
+
+const (
+	// DefaultTenantID tenant of the base config when no tenant is configured, the rows created before the tenants
+	DefaultTenantID string = "default"
+	// TenantTokenHeader header carrying the admin token of the tenant in the admin api requests
+	TenantTokenHeader = "X-Tenant-Token"
+)
+
 var statusMap = map[string]MemberStatus{
 	"creator":       Creator,
 	"administrator": Administrator,
@@ -119,7 +220,17 @@ func GetMemberStatusFromString(value string) MemberStatus {
 }
 
 func (p SocialPlatformType) Name() string {
-	return [...]string{"TELEGRAM", "LINE"}[p]
+	return [...]string{"", "TELEGRAM", "LINE"}[p]
+}
+
+// GetSocialPlatformFromName returns the platform named e.g. TELEGRAM
+func GetSocialPlatformFromName(name string) (SocialPlatformType, bool) {
+	for _, p := range []SocialPlatformType{Telegram, Line} {
+		if p.Name() == name {
+			return p, true
+		}
+	}
+	return 0, false
 }
 
 func (p SocialPlatformType) Value() int {
@@ -127,7 +238,7 @@ func (p SocialPlatformType) Value() int {
 }
 
 func (t TradingPlatformType) Name() string {
-	return [...]string{"BITGET", "BINGX"}[t]
+	return [...]string{"", "BITGET", "BINGX"}[t]
 }
 
 func (t TradingPlatformType) Value() int {
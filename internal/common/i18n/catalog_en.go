@@ -0,0 +1,159 @@
+package i18n
+
+import tele "gopkg.in/telebot.v3"
+
+var en = Catalog{
+	MsgWelcome: {Other: `🦀≡≡≡≡≡≡≡≡▷►◈◄◁≡≡≡≡≡≡≡≡🦀
+
+🔝Welcome to the 🪣Bikini Bottom Sea Lord VIP🔇
+My strategy runs high leverage with a high risk/reward ratio📈
+Some of the calls are hard to accept and get flamed in the main group
+so there is a small entry threshold to keep out blind followers who get liquidated
+and to keep the upcoming reward campaigns fair for active traders
+The minimum is only 10000u trading volume per month (leverage included)
+Volumes are checked on the 1st of every month, members below the requirement are removed from the VIP and chat groups
+until their volume reaches 10000u again, or after one month they can /rejoin
+If you are not sure whether you qualify, the bot can check your trading volume
+
+Benefits:
+🅰️Up to 20% futures and 20%+20% spot fee rebates✨
+🅱️Dedicated team bitget copy trading
+🆎Trades led by Mr. Krabs🦀 himself
+⚡️High risk/reward strategy sharing in the VIP group
+🧽Access to the chat group
+How to join:
+1️⃣Register an account with the link⭐️
+https://partner.bitget.fit/bg/MrKrabs
+2️⃣Send your UID to the bot for verification♥️
+https://t.me/wedjatbtcVIP_bot
+⚠️Invite links can be used only once⚠️
+⚠️After verifying, tap join below before leaving⚠️
+⚠️Otherwise the links cannot be used again⚠️
+
+☢️Member commands for joining and checking volume☢️
+
+/start    	  - Start using the bot
+/help     	  - Show the description of all commands
+/verify <uid>   	  - Verify your numeric UID
+/volume <uid>   - Check your total trading volume
+/account <uid>  - Change the bound telegram account
+/join <uid>     - Get new group invite links
+/lang <language>  - Change the bot language
+
+For any questions please DM me, thank you🕳
+https://t.me/wedjatbtc
+
+🦀≡≡≡≡≡≡≡≡▷►◈◄◁≡≡≡≡≡≡≡≡🦀`},
+	MsgHelp:                {Other: "```\n%s\n```", Mode: tele.ModeMarkdownV2},
+	MsgProcessing:          {Other: "Verifying your UID, please wait..."},
+	MsgServerError:         {Other: "The verification service is temporarily unavailable, please try again later❌"},
+	MsgInternalServerError: {Other: "Something went wrong on the server, please try again later"},
+	MsgUnknownError:        {Other: "Unknown Error"},
+	MsgOnText:              {Other: "I'm not a chat bot, go talk to a real human. Send me a command and I'll get to work"},
+
+	MsgInvalidCommandFormat: {Other: "❌Please use the correct format: %s <UID>\nExample: %s 123456"},
+	MsgInvalidUIDFormat:     {Other: "❌Invalid UID format\nExample: %s 123456"},
+
+	MsgVerifySuccessLine:       {Other: "🦀You have been verified successfully! Thanks for joining!✅"},
+	MsgVerifySuccess:           {Other: "🦀You have been verified successfully! Thanks for joining!✅\nHere are the links to the chat group and the VIP group"},
+	MsgVerifyInvalidUID:        {Other: "🦀The UID you entered does not exist, verification failed❌ Please check it and try again"},
+	MsgVerifyExistsUID:         {Other: "🦀This UID has already been verified, no need to verify again! Happy trading!✅"},
+	MsgVerifyExistsSocialUser:  {Other: "🦀You have already bound a telegram account, please use /account to change the bound account❌"},
+	MsgVerifyInactiveUID:       {Other: "🦀The social account bound to this UID is inactive, please use /volume %s to check whether your trading volume qualifies or contact the group owner❌"},
+	MsgAccountDuplicatedUser:   {Other: "🦀The social account bound to this UID does not need to change"},
+	MsgAccountMemberInfoUpdate: {Other: "🦀Your social account information has been updated✅"},
+
+	MsgVolumeSuccess:      {Other: "🔎Query succeeded, your trading volume from the 1st of this month to today is: USDT$%.2f"},
+	MsgVolumeFailure:      {Other: "❌Query failed, please try again"},
+	MsgStatusMemberStatus: {Other: "⚠️ The group status of the telegram user bound to uid: %s is: %s"},
+	MsgStatusGroupsHeader: {Other: "👥Groups you are currently in:"},
+	MsgStatusGroupLine:    {Other: "• %s: %s"},
+	MsgStatusGroupsEmpty:  {Other: "👥You are currently not in any group"},
+
+	MsgStatusBoundToSender:     {Other: "🔗UID: %s is bound to your current account✅"},
+	MsgStatusBoundToOther:      {Other: "🔗UID: %s is not bound to your current account❌ please use /account %s to change the binding"},
+	MsgStatusAccount:           {Other: "👤Account: %s"},
+	MsgStatusList:              {Other: "📝List status: %s"},
+	MsgStatusVolume:            {Other: "📈Trading volume this month: USDT$%.2f"},
+	MsgStatusVolumeMet:         {Other: "📈Trading volume this month: USDT$%.2f, reaches the threshold of USDT$%.2f✅"},
+	MsgStatusVolumeBelow:       {Other: "📈Trading volume this month: USDT$%.2f, below the threshold of USDT$%.2f❌"},
+	MsgStatusVolumeUnavailable: {Other: "📈The trading volume of this month is unavailable right now, please use /volume %s later"},
+
+	MsgGroupUserWarning: {Other: "⚠️ @%s please do not send commands, telegram links, web links, UIDs or other sensitive messages in the group, thank you"},
+	MsgGroupUserMuted:   {Other: "🔇 @%s has been muted for %d minutes after %d violations"},
+	MsgGroupUserBanned:  {Other: "⛔ @%s has been removed and banned after %d violations"},
+
+	MsgJoinRequestDeclined:  {Other: "🦀Your request to join %s was declined❌\nPlease DM the bot and verify your UID with /verify <uid>, then request to join again with the invite link\nIf you have already verified, check your account with /status <uid> or contact the group owner"},
+	MsgInviteLinkIssued:     {Other: "🦀Your group invite links have been re-issued✅ The previous links no longer work"},
+	MsgInviteLinkFailed:     {Other: "❌Failed to create the invite links, please use /join %s later to get them again"},
+	MsgInviteLinkNotOwner:   {Other: "❌This UID is not bound to your current telegram account, please use /account %s to change the binding"},
+	MsgInviteLinkNotAllowed: {Other: "🦀This UID cannot get invite links at the moment, please check your account with /status %s or contact the group owner❌"},
+
+	MsgLinkSharingIntruder:        {Other: "🚫You joined %s with an invite link issued to another member and have been removed\nPlease DM the bot and verify your own UID with /verify <uid> to get your own invite link"},
+	MsgLinkSharingRequestDeclined: {Other: "🚫You asked to join %s with an invite link issued to another member, your request was declined\nPlease DM the bot and verify your own UID with /verify <uid> to get your own invite link"},
+	MsgLinkSharingAlert:           {Other: "⚠️Invite link sharing detected\nGroup: %s\nLink owner: customer %s (telegram id: %s)\nJoined by: %s (@%s)\nResult: %s"},
+	MsgLinkSharingRemoved:         {Other: "removed from the group, link revoked"},
+	MsgLinkSharingNotRemoved:      {Other: "removal failed, please handle it manually"},
+	MsgLinkSharingDeclined:        {Other: "join request declined, link revoked"},
+
+	MsgRateLimited:      {Other: "⏳You are sending commands too fast, please try again in %d seconds"},
+	MsgRateLimitBlocked: {Other: "⛔Too many requests, you are blocked for %d minutes"},
+
+	MsgCaptchaRules:        {Other: "👋Welcome %s to %s\nPlease press the button below within %d seconds to accept the group rules, otherwise you will be removed"},
+	MsgCaptchaMath:         {Other: "👋Welcome %s to %s\nPlease answer within %d seconds: %d + %d = ?, otherwise you will be removed"},
+	MsgCaptchaAcceptButton: {Other: "✅I accept the rules"},
+	MsgCaptchaNotForYou:    {Other: "This challenge is not for you"},
+	MsgCaptchaPassed:       {Other: "✅Verified, welcome to the group"},
+	MsgCaptchaFailed:       {Other: "❌Wrong answer, you have been removed from the group"},
+	MsgCaptchaExpired:      {Other: "This challenge is no longer valid"},
+
+	MsgLangCurrent:     {Other: "🌐Current language: %s\nAvailable languages: %s\nUse /lang <language> to switch"},
+	MsgLangUpdated:     {Other: "🌐Language switched to: %s✅"},
+	MsgLangUnsupported: {Other: "❌Unsupported language: %s\nAvailable languages: %s"},
+	MsgLangName:        {Other: "English"},
+
+	MsgAdminUnauthorized:    {Other: "⛔You are not allowed to use this command"},
+	MsgAdminUsage:           {Other: "❌Please use the correct format: %s <UID|@username>\nExample: %s 123456"},
+	MsgAdminNotFound:        {Other: "❌User not found: %s"},
+	MsgAdminLookupResult:    {Other: "🔎Lookup result\nUID: %s\nCustomer ID: %s\nTelegram ID: %s\nUsername: @%s\nName: %s %s\nAccount: %s\nList status: %s\nGroup status: %s\nBound at: %s"},
+	MsgAdminStatusUpdated:   {Other: "✅UID: %s list status updated to: %s"},
+	MsgAdminUnbanned:        {Other: "✅UID: %s has been unbanned from the groups"},
+	MsgAdminKicked:          {Other: "✅UID: %s has been removed from the groups"},
+	MsgAdminActionFailed:    {Other: "❌UID: %s the action failed, please try again later"},
+	MsgAdminStats:           {Other: "📊Member stats\nTotal: %d\nActive: %d\nInactive: %d\nRegular: %d\nWhitelisted: %d\nBlacklisted: %d"},
+	MsgAdminActive:          {Other: "Active"},
+	MsgAdminInactive:        {Other: "Inactive"},
+	MsgAdminButtonLookup:    {Other: "🔎Lookup"},
+	MsgAdminButtonBlacklist: {Other: "⛔Blacklist"},
+	MsgAdminButtonWhitelist: {Other: "✅Whitelist"},
+	MsgAdminButtonUnban:     {Other: "🔓Unban"},
+	MsgAdminButtonKick:      {Other: "👢Kick"},
+
+	MsgCommandStart:     {Other: "Start using the bot"},
+	MsgCommandHelp:      {Other: "Show the description of all commands"},
+	MsgCommandStatus:    {Other: "Check the status of your telegram account"},
+	MsgCommandVerify:    {Other: "Verify your numeric UID"},
+	MsgCommandVolume:    {Other: "Check your trading volume this month"},
+	MsgCommandAccount:   {Other: "Change the bound telegram account"},
+	MsgCommandJoin:      {Other: "Get new group invite links"},
+	MsgCommandLang:      {Other: "Change the bot language"},
+	MsgCommandLookup:    {Other: "Look up a member"},
+	MsgCommandBlacklist: {Other: "Blacklist a member"},
+	MsgCommandWhitelist: {Other: "Whitelist a member"},
+	MsgCommandUnban:     {Other: "Lift the group ban of a member"},
+	MsgCommandKick:      {Other: "Remove a member from the groups"},
+	MsgCommandStats:     {Other: "Member statistics"},
+
+	MsgMemberStatusCreator:       {Other: "Owner"},
+	MsgMemberStatusAdministrator: {Other: "Administrator"},
+	MsgMemberStatusMember:        {Other: "Member"},
+	MsgMemberStatusRestricted:    {Other: "Restricted"},
+	MsgMemberStatusLeft:          {Other: "Left"},
+	MsgMemberStatusKicked:        {Other: "Banned"},
+	MsgMemberStatusUnknown:       {Other: "Unknown"},
+
+	MsgStatusNormal:      {Other: "Regular member"},
+	MsgStatusWhitelisted: {Other: "Whitelisted"},
+	MsgStatusBlacklisted: {Other: "Blacklisted"},
+	MsgStatusUnknown:     {Other: "Unknown"},
+}
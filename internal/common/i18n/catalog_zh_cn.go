@@ -0,0 +1,159 @@
+package i18n
+
+import tele "gopkg.in/telebot.v3"
+
+var zhCN = Catalog{
+	MsgWelcome: {Other: `🦀≡≡≡≡≡≡≡≡▷►◈◄◁≡≡≡≡≡≡≡≡🦀
+
+🔝欢迎各位加入🪣比奇堡海之霸VIP🔇
+因我个人的策略关系，(杠杆高，盈亏比高)📈
+有些订单接受度较低，在大群发布容易被喷
+因此简单设立一个门槛，避免路人粉瞎操作爆仓
+加上之后会有奖励活动避免注册后白嫖
+每个月最低10000u交易量(含杠杆)非常低的标准
+每月1号核对，若交易额不符合要求将会移除VIP群及交流群
+直到交易额再次达到10000u或一个月后点即可/rejoin重新加回
+如果不知道是否符合要求，机器人也有交易额查询功能可以使用
+
+内容：
+🅰️最高20%合约20%+20%现货手续费减免✨
+🅱️专属团队bitget跟单服务
+🆎蟹老板🦀亲自带单
+⚡️VIP群高盈亏比策略分享
+🧽交流群加入资格
+加入步骤：
+1️⃣点击链接注册账号⭐️
+https://partner.bitget.fit/bg/MrKrabs
+2️⃣发送UID给机器人确认♥️
+https://t.me/wedjatbtcVIP_bot
+⚠️邀请链接为一次性使用⚠️
+⚠️注册后记得点击下方加入在退出⚠️
+⚠️否则无法再点击⚠️
+
+☢️以下是不同会员入群指令以及交易额查询☢️
+
+/start    	  - 开始使用机器人
+/help     	  - 了解所有指令说明 请输入此指令
+/verify <uid>   	  - 验证uid指令 请输入你的数字UID
+/volume <uid>   - 交易总额查询 请输入此指令
+/account <uid>  - 更改电报账号绑定
+/join <uid>     - 重新获取群组邀请链接
+/lang <语言>     - 切换机器人语言
+
+有任何疑问请直接私讯本人谢谢🕳
+https://t.me/wedjatbtc
+
+🦀≡≡≡≡≡≡≡≡▷►◈◄◁≡≡≡≡≡≡≡≡🦀`},
+	MsgHelp:                {Other: "```\n%s\n```", Mode: tele.ModeMarkdownV2},
+	MsgProcessing:          {Other: "正在验证 UID，请稍候..."},
+	MsgServerError:         {Other: "验证服务暂时无法使用，请稍后重试❌"},
+	MsgInternalServerError: {Other: "服务器处理过程中发生错误，请稍后重试"},
+	MsgUnknownError:        {Other: "未知错误"},
+	MsgOnText:              {Other: "我不是聊天机器人 有活人不聊 你找我干啥 我能跳舞吗 输入指令我才干活 不然我会骂街的"},
+
+	MsgInvalidCommandFormat: {Other: "❌请使用正确的格式：%s <UID>\n范例：%s 123456"},
+	MsgInvalidUIDFormat:     {Other: "❌无效的UID格式\n范例：%s 123456"},
+
+	MsgVerifySuccessLine:       {Other: "🦀您已验证成功!感谢关注!✅"},
+	MsgVerifySuccess:           {Other: "🦀您已验证成功!感谢关注!✅\n以下是交流群以及VIP群的链接"},
+	MsgVerifyInvalidUID:        {Other: "🦀您输入的UID不存在 验证失败❌ 请查询正确后再次输入"},
+	MsgVerifyExistsUID:         {Other: "🦀您要验证的uid已存在,无须再次验证!祝您交易顺利!✅"},
+	MsgVerifyExistsSocialUser:  {Other: "🦀您已绑定过电报账号 请使用/account变更您的绑定电报账号❌"},
+	MsgVerifyInactiveUID:       {Other: "🦀此UID所绑定的社交账号状态为非活跃 请使用/volume %s 检查您的交易额度是否达标 或联系群组主❌"},
+	MsgAccountDuplicatedUser:   {Other: "🦀此UID所绑定的社交账号无需更改"},
+	MsgAccountMemberInfoUpdate: {Other: "🦀您目前的社交账号信息已更新成功✅"},
+
+	MsgVolumeSuccess:      {Other: "🔎查询成功,距离本月1号到今日,您的交易额为: USDT$%.2f"},
+	MsgVolumeFailure:      {Other: "❌查询失败请重试"},
+	MsgStatusMemberStatus: {Other: "⚠️ 您目前使用该uid: %s 查询的电报用户群组状态为： %s"},
+	MsgStatusGroupsHeader: {Other: "👥您目前所在的群组："},
+	MsgStatusGroupLine:    {Other: "• %s： %s"},
+	MsgStatusGroupsEmpty:  {Other: "👥您目前不在任何群组中"},
+
+	MsgStatusBoundToSender:     {Other: "🔗UID: %s 已绑定您当前的账号✅"},
+	MsgStatusBoundToOther:      {Other: "🔗UID: %s 未绑定您当前的账号❌ 请使用/account %s 变更绑定"},
+	MsgStatusAccount:           {Other: "👤账号状态: %s"},
+	MsgStatusList:              {Other: "📝名单状态: %s"},
+	MsgStatusVolume:            {Other: "📈本月交易额: USDT$%.2f"},
+	MsgStatusVolumeMet:         {Other: "📈本月交易额: USDT$%.2f, 已达到门槛 USDT$%.2f✅"},
+	MsgStatusVolumeBelow:       {Other: "📈本月交易额: USDT$%.2f, 未达到门槛 USDT$%.2f❌"},
+	MsgStatusVolumeUnavailable: {Other: "📈暂时无法查询本月交易额, 请稍后使用/volume %s 查询"},
+
+	MsgGroupUserWarning: {Other: "⚠️ @%s 请不要在群组中发送任何指令 电报链接 网页链接 UID...等等敏感信息 谢谢合作"},
+	MsgGroupUserMuted:   {Other: "🔇 @%s 已被禁言 %d 分钟，累计违规 %d 次"},
+	MsgGroupUserBanned:  {Other: "⛔ @%s 累计违规 %d 次，已被移出并封锁"},
+
+	MsgJoinRequestDeclined:  {Other: "🦀您申请加入 %s 未通过❌\n请先私讯机器人使用 /verify <uid> 验证您的UID，验证成功后再使用邀请链接申请加入\n若您已验证，请使用 /status <uid> 查询账号状态或联系群组主"},
+	MsgInviteLinkIssued:     {Other: "🦀已重新生成您的群组邀请链接✅ 旧的链接已失效"},
+	MsgInviteLinkFailed:     {Other: "❌邀请链接生成失败，请稍后使用 /join %s 重新获取"},
+	MsgInviteLinkNotOwner:   {Other: "❌此UID并未绑定您当前的电报账号，请使用 /account %s 变更绑定"},
+	MsgInviteLinkNotAllowed: {Other: "🦀此UID目前无法获取邀请链接 请使用 /status %s 查询账号状态或联系群组主❌"},
+
+	MsgLinkSharingIntruder:        {Other: "🚫您使用了发给其他会员的邀请链接加入 %s，已被移出群组\n请私信机器人使用 /verify <uid> 验证您自己的UID以获取专属邀请链接"},
+	MsgLinkSharingRequestDeclined: {Other: "🚫您使用了发给其他会员的邀请链接申请加入 %s，申请已被拒绝\n请私信机器人使用 /verify <uid> 验证您自己的UID以获取专属邀请链接"},
+	MsgLinkSharingAlert:           {Other: "⚠️检测到邀请链接被分享\n群组: %s\n链接拥有者: 客户 %s (电报ID: %s)\n加入者: %s (@%s)\n处理结果: %s"},
+	MsgLinkSharingRemoved:         {Other: "已移出群组，链接已撤销"},
+	MsgLinkSharingNotRemoved:      {Other: "移出失败，请手动处理"},
+	MsgLinkSharingDeclined:        {Other: "已拒绝入群申请，链接已撤销"},
+
+	MsgRateLimited:      {Other: "⏳您的操作太频繁了，请在 %d 秒后再试"},
+	MsgRateLimitBlocked: {Other: "⛔您的操作过于频繁，已被暂时封锁 %d 分钟"},
+
+	MsgCaptchaRules:        {Other: "👋欢迎 %s 加入 %s\n请在 %d 秒内点击下方按钮同意群组规则，否则将被移出群组"},
+	MsgCaptchaMath:         {Other: "👋欢迎 %s 加入 %s\n请在 %d 秒内回答: %d + %d = ?，否则将被移出群组"},
+	MsgCaptchaAcceptButton: {Other: "✅我同意群组规则"},
+	MsgCaptchaNotForYou:    {Other: "这不是给您的验证"},
+	MsgCaptchaPassed:       {Other: "✅验证成功，欢迎加入"},
+	MsgCaptchaFailed:       {Other: "❌答案错误，您已被移出群组"},
+	MsgCaptchaExpired:      {Other: "此验证已失效"},
+
+	MsgLangCurrent:     {Other: "🌐目前语言为: %s\n可用语言: %s\n请使用 /lang <语言> 进行切换"},
+	MsgLangUpdated:     {Other: "🌐语言已切换为: %s✅"},
+	MsgLangUnsupported: {Other: "❌不支持的语言: %s\n可用语言: %s"},
+	MsgLangName:        {Other: "简体中文"},
+
+	MsgAdminUnauthorized:    {Other: "⛔您没有权限使用此指令"},
+	MsgAdminUsage:           {Other: "❌请使用正确的格式：%s <UID|@用户名>\n范例：%s 123456"},
+	MsgAdminNotFound:        {Other: "❌找不到此用户: %s"},
+	MsgAdminLookupResult:    {Other: "🔎查询结果\nUID: %s\n客户ID: %s\n电报ID: %s\n用户名: @%s\n姓名: %s %s\n账号状态: %s\n名单状态: %s\n群组状态: %s\n绑定时间: %s"},
+	MsgAdminStatusUpdated:   {Other: "✅UID: %s 名单状态已更新为: %s"},
+	MsgAdminUnbanned:        {Other: "✅UID: %s 已解除群组封禁"},
+	MsgAdminKicked:          {Other: "✅UID: %s 已被移出群组"},
+	MsgAdminActionFailed:    {Other: "❌UID: %s 操作失败，请稍后重试"},
+	MsgAdminStats:           {Other: "📊会员统计\n总数: %d\n活跃: %d\n非活跃: %d\n一般会员: %d\n白名单: %d\n黑名单: %d"},
+	MsgAdminActive:          {Other: "活跃"},
+	MsgAdminInactive:        {Other: "非活跃"},
+	MsgAdminButtonLookup:    {Other: "🔎查询"},
+	MsgAdminButtonBlacklist: {Other: "⛔黑名单"},
+	MsgAdminButtonWhitelist: {Other: "✅白名单"},
+	MsgAdminButtonUnban:     {Other: "🔓解除封禁"},
+	MsgAdminButtonKick:      {Other: "👢移出群组"},
+
+	MsgCommandStart:     {Other: "开始使用机器人"},
+	MsgCommandHelp:      {Other: "了解所有指令说明"},
+	MsgCommandStatus:    {Other: "查询目前电报账号状态"},
+	MsgCommandVerify:    {Other: "验证您的数字UID"},
+	MsgCommandVolume:    {Other: "查询本月交易总额"},
+	MsgCommandAccount:   {Other: "更改电报账号绑定"},
+	MsgCommandJoin:      {Other: "重新获取群组邀请链接"},
+	MsgCommandLang:      {Other: "切换机器人语言"},
+	MsgCommandLookup:    {Other: "查询会员信息"},
+	MsgCommandBlacklist: {Other: "将会员加入黑名单"},
+	MsgCommandWhitelist: {Other: "将会员加入白名单"},
+	MsgCommandUnban:     {Other: "解除会员的群组封锁"},
+	MsgCommandKick:      {Other: "将会员移出群组"},
+	MsgCommandStats:     {Other: "会员统计"},
+
+	MsgMemberStatusCreator:       {Other: "拥有者"},
+	MsgMemberStatusAdministrator: {Other: "管理员"},
+	MsgMemberStatusMember:        {Other: "成员"},
+	MsgMemberStatusRestricted:    {Other: "受限"},
+	MsgMemberStatusLeft:          {Other: "已退出"},
+	MsgMemberStatusKicked:        {Other: "已封锁"},
+	MsgMemberStatusUnknown:       {Other: "未知"},
+
+	MsgStatusNormal:      {Other: "普通成员"},
+	MsgStatusWhitelisted: {Other: "白名单"},
+	MsgStatusBlacklisted: {Other: "黑名单"},
+	MsgStatusUnknown:     {Other: "未知"},
+}
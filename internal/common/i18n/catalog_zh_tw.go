@@ -0,0 +1,123 @@
+package i18n
+
+import (
+	tele "gopkg.in/telebot.v3"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+)
+
+// zhTW is the default catalog, it's backed by the constants in common
+var zhTW = Catalog{
+	MsgWelcome:             {Other: common.WelcomeMessage},
+	MsgHelp:                {Other: common.HelpMessage, Mode: tele.ModeMarkdownV2},
+	MsgProcessing:          {Other: common.ProcessingMessage},
+	MsgServerError:         {Other: common.ServerErrorMessage},
+	MsgInternalServerError: {Other: common.InternalServerErrorMessage},
+	MsgUnknownError:        {Other: common.UnknownErrorMessage},
+	MsgOnText:              {Other: common.OnTextReplyMessage},
+
+	MsgInvalidCommandFormat: {Other: common.InvalidCommandFormatMessage},
+	MsgInvalidUIDFormat:     {Other: common.InvalidUIDFormatMessage},
+
+	MsgVerifySuccessLine:       {Other: common.LineSuccessVerifyReplyMessage},
+	MsgVerifySuccess:           {Other: common.SuccessVerifyReplyMessage},
+	MsgVerifyInvalidUID:        {Other: common.InvalidUidVerifyReplyMessage},
+	MsgVerifyExistsUID:         {Other: common.ExistsUidVerifyReplyMessage},
+	MsgVerifyExistsSocialUser:  {Other: common.ExistsSocialUserIdVerifyReplyMessage},
+	MsgVerifyInactiveUID:       {Other: common.InvalidUidStatusMessage},
+	MsgAccountDuplicatedUser:   {Other: common.DuplicatedUserReplyMessage},
+	MsgAccountMemberInfoUpdate: {Other: common.MemberInfoUpdatedMessage},
+
+	MsgVolumeSuccess:      {Other: common.SuccessVolumeReplyMessage},
+	MsgVolumeFailure:      {Other: common.FailureVolumeReplyMessage},
+	MsgStatusMemberStatus: {Other: common.MemberStatusReplyMessage},
+	MsgStatusGroupsHeader: {Other: common.GroupMembershipHeaderMessage},
+	MsgStatusGroupLine:    {Other: common.GroupMembershipLineMessage},
+	MsgStatusGroupsEmpty:  {Other: common.GroupMembershipEmptyMessage},
+
+	MsgStatusBoundToSender:     {Other: common.StatusBoundToSenderMessage},
+	MsgStatusBoundToOther:      {Other: common.StatusBoundToOtherMessage},
+	MsgStatusAccount:           {Other: common.StatusAccountMessage},
+	MsgStatusList:              {Other: common.StatusListMessage},
+	MsgStatusVolume:            {Other: common.StatusVolumeMessage},
+	MsgStatusVolumeMet:         {Other: common.StatusVolumeMetMessage},
+	MsgStatusVolumeBelow:       {Other: common.StatusVolumeBelowMessage},
+	MsgStatusVolumeUnavailable: {Other: common.StatusVolumeUnavailableMessage},
+
+	MsgGroupUserWarning: {Other: common.UserWarningMessage},
+	MsgGroupUserMuted:   {Other: common.UserMutedMessage},
+	MsgGroupUserBanned:  {Other: common.UserBannedMessage},
+
+	MsgJoinRequestDeclined:  {Other: common.JoinRequestDeclinedMessage},
+	MsgInviteLinkIssued:     {Other: common.InviteLinkIssuedMessage},
+	MsgInviteLinkFailed:     {Other: common.InviteLinkFailedMessage},
+	MsgInviteLinkNotOwner:   {Other: common.InviteLinkNotOwnerMessage},
+	MsgInviteLinkNotAllowed: {Other: common.InviteLinkNotAllowedMessage},
+
+	MsgLinkSharingIntruder:        {Other: common.LinkSharingIntruderMessage},
+	MsgLinkSharingRequestDeclined: {Other: common.LinkSharingRequestDeclinedMessage},
+	MsgLinkSharingAlert:           {Other: common.LinkSharingAlertMessage},
+	MsgLinkSharingRemoved:         {Other: common.LinkSharingRemovedMessage},
+	MsgLinkSharingNotRemoved:      {Other: common.LinkSharingNotRemovedMessage},
+	MsgLinkSharingDeclined:        {Other: common.LinkSharingDeclinedMessage},
+
+	MsgRateLimited:      {Other: common.RateLimitedMessage},
+	MsgRateLimitBlocked: {Other: common.RateLimitBlockedMessage},
+
+	MsgCaptchaRules:        {Other: common.CaptchaRulesMessage},
+	MsgCaptchaMath:         {Other: common.CaptchaMathMessage},
+	MsgCaptchaAcceptButton: {Other: common.CaptchaAcceptButtonMessage},
+	MsgCaptchaNotForYou:    {Other: common.CaptchaNotForYouMessage},
+	MsgCaptchaPassed:       {Other: common.CaptchaPassedMessage},
+	MsgCaptchaFailed:       {Other: common.CaptchaFailedMessage},
+	MsgCaptchaExpired:      {Other: common.CaptchaExpiredMessage},
+
+	MsgLangCurrent:     {Other: common.LangCurrentMessage},
+	MsgLangUpdated:     {Other: common.LangUpdatedMessage},
+	MsgLangUnsupported: {Other: common.LangUnsupportedMessage},
+	MsgLangName:        {Other: common.LangNameMessage},
+
+	MsgAdminUnauthorized:    {Other: common.AdminUnauthorizedMessage},
+	MsgAdminUsage:           {Other: common.AdminUsageMessage},
+	MsgAdminNotFound:        {Other: common.AdminNotFoundMessage},
+	MsgAdminLookupResult:    {Other: common.AdminLookupResultMessage},
+	MsgAdminStatusUpdated:   {Other: common.AdminStatusUpdatedMessage},
+	MsgAdminUnbanned:        {Other: common.AdminUnbannedMessage},
+	MsgAdminKicked:          {Other: common.AdminKickedMessage},
+	MsgAdminActionFailed:    {Other: common.AdminActionFailedMessage},
+	MsgAdminStats:           {Other: common.AdminStatsMessage},
+	MsgAdminActive:          {Other: common.AdminActiveMessage},
+	MsgAdminInactive:        {Other: common.AdminInactiveMessage},
+	MsgAdminButtonLookup:    {Other: common.AdminButtonLookupMessage},
+	MsgAdminButtonBlacklist: {Other: common.AdminButtonBlacklistMessage},
+	MsgAdminButtonWhitelist: {Other: common.AdminButtonWhitelistMessage},
+	MsgAdminButtonUnban:     {Other: common.AdminButtonUnbanMessage},
+	MsgAdminButtonKick:      {Other: common.AdminButtonKickMessage},
+
+	MsgCommandStart:     {Other: common.StartCommandDescription},
+	MsgCommandHelp:      {Other: common.HelpCommandDescription},
+	MsgCommandStatus:    {Other: common.StatusCommandDescription},
+	MsgCommandVerify:    {Other: common.VerifyCommandDescription},
+	MsgCommandVolume:    {Other: common.VolumeCommandDescription},
+	MsgCommandAccount:   {Other: common.AccountCommandDescription},
+	MsgCommandJoin:      {Other: common.JoinCommandDescription},
+	MsgCommandLang:      {Other: common.LangCommandDescription},
+	MsgCommandLookup:    {Other: common.LookupCommandDescription},
+	MsgCommandBlacklist: {Other: common.BlacklistCommandDescription},
+	MsgCommandWhitelist: {Other: common.WhitelistCommandDescription},
+	MsgCommandUnban:     {Other: common.UnbanCommandDescription},
+	MsgCommandKick:      {Other: common.KickCommandDescription},
+	MsgCommandStats:     {Other: common.StatsCommandDescription},
+
+	MsgMemberStatusCreator:       {Other: common.Creator.Value()},
+	MsgMemberStatusAdministrator: {Other: common.MemberStatus(common.Administrator).Value()},
+	MsgMemberStatusMember:        {Other: common.MemberStatus(common.Member).Value()},
+	MsgMemberStatusRestricted:    {Other: common.MemberStatus(common.Restricted).Value()},
+	MsgMemberStatusLeft:          {Other: common.MemberStatus(common.Left).Value()},
+	MsgMemberStatusKicked:        {Other: common.MemberStatus(common.Kicked).Value()},
+	MsgMemberStatusUnknown:       {Other: common.MemberStatus(common.Unknown).Value()},
+
+	MsgStatusNormal:      {Other: common.Normal.Value()},
+	MsgStatusWhitelisted: {Other: common.Status(common.Whitelisted).Value()},
+	MsgStatusBlacklisted: {Other: common.Status(common.Blacklisted).Value()},
+	MsgStatusUnknown:     {Other: "未知"},
+}
@@ -0,0 +1,157 @@
+package i18n
+
+import (
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"strings"
+	"sync/atomic"
+)
+
+type Lang string
+type Key string
+
+// ContextKey is the tele.Context key holding the resolved Lang of the sender
+const ContextKey = "lang"
+
+const (
+	ZhTW Lang = "zh-TW"
+	ZhCN Lang = "zh-CN"
+	En   Lang = "en"
+)
+
+// Default language used when neither the user nor telegram provides a supported one
+const Default = ZhTW
+
+// Message a single translation entry, One is only used by languages with a singular form
+type Message struct {
+	One   string
+	Other string
+	Mode  tele.ParseMode
+}
+
+type Catalog map[Key]Message
+
+var catalogs = map[Lang]Catalog{
+	ZhTW: zhTW,
+	ZhCN: zhCN,
+	En:   en,
+}
+
+// Supported returns all languages with a message catalog, default first
+func Supported() []Lang {
+	return []Lang{ZhTW, ZhCN, En}
+}
+
+// CatalogMessage returns the built-in message of key in lang
+func CatalogMessage(lang Lang, key Key) (Message, bool) {
+	msg, ok := catalogs[lang][key]
+	return msg, ok
+}
+
+// ParseLang maps telegram language_code (IETF tag) or user input to a supported Lang
+func ParseLang(code string) (Lang, bool) {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"))
+	switch {
+	case code == "":
+		return "", false
+	case code == "zh-tw", code == "zh-hk", code == "zh-mo", strings.HasPrefix(code, "zh-hant"):
+		return ZhTW, true
+	case code == "zh", code == "zh-cn", code == "zh-sg", strings.HasPrefix(code, "zh-hans"):
+		return ZhCN, true
+	case code == "en", strings.HasPrefix(code, "en-"):
+		return En, true
+	default:
+		return "", false
+	}
+}
+
+// FromContext returns the language resolved by the telegram middleware
+func FromContext(c tele.Context) Lang {
+	if lang, ok := c.Get(ContextKey).(Lang); ok {
+		return lang
+	}
+	return ""
+}
+
+type Localizer struct {
+	fallback  Lang
+	catalogs  map[Lang]Catalog
+	templates atomic.Pointer[map[templateKey]*Template]
+}
+
+type templateKey struct {
+	key  Key
+	lang Lang
+}
+
+func NewLocalizer(fallback Lang) *Localizer {
+	if _, ok := catalogs[fallback]; !ok {
+		fallback = Default
+	}
+	return &Localizer{
+		fallback: fallback,
+		catalogs: catalogs,
+	}
+}
+
+// Fallback returns the language used when the requested one is missing
+func (l *Localizer) Fallback() Lang {
+	return l.fallback
+}
+
+// T translate key into lang and format it with args
+func (l *Localizer) T(lang Lang, key Key, args ...interface{}) string {
+	return format(l.lookup(lang, key).Other, args...)
+}
+
+// N translate key with plural form chosen by n, n is not passed to the format args
+func (l *Localizer) N(lang Lang, key Key, n int, args ...interface{}) string {
+	msg := l.lookup(lang, key)
+	if n == 1 && msg.One != "" {
+		return format(msg.One, args...)
+	}
+	return format(msg.Other, args...)
+}
+
+// Render translate an editable key, the runtime template of lang is preferred over the catalog,
+// data feeds the template and args the catalog message
+func (l *Localizer) Render(lang Lang, key Key, data TemplateData, args ...interface{}) Rendered {
+	if lang == "" {
+		lang = l.fallback
+	}
+	if templates := l.templates.Load(); templates != nil {
+		if t, ok := (*templates)[templateKey{key, lang}]; ok {
+			if text, err := t.Execute(data); err == nil {
+				return Rendered{Text: text, ParseMode: t.ParseMode}
+			}
+		}
+	}
+	msg := l.lookup(lang, key)
+	return Rendered{Text: format(msg.Other, args...), ParseMode: msg.Mode}
+}
+
+// SetTemplates replace all runtime templates, used for hot reloading
+func (l *Localizer) SetTemplates(templates []*Template) {
+	m := make(map[templateKey]*Template, len(templates))
+	for _, t := range templates {
+		m[templateKey{t.Key, t.Lang}] = t
+	}
+	l.templates.Store(&m)
+}
+
+func (l *Localizer) lookup(lang Lang, key Key) Message {
+	if msg, ok := l.catalogs[lang][key]; ok {
+		return msg
+	}
+	if msg, ok := l.catalogs[l.fallback][key]; ok {
+		return msg
+	}
+	return Message{Other: string(key)}
+}
+
+func format(text string, args ...interface{}) string {
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
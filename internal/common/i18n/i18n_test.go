@@ -0,0 +1,176 @@
+package i18n
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+var verbPattern = regexp.MustCompile(`%[-+#0]*[0-9]*(\.[0-9]+)?[vTtbcdoOqxXUeEfFgGsp]`)
+
+// declaredKeys collects every Key constant declared in keys.go
+func declaredKeys(t *testing.T) []Key {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "keys.go", nil, 0)
+	require.NoError(t, err)
+
+	var keys []Key
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok || spec.Type == nil {
+			return true
+		}
+		if ident, ok := spec.Type.(*ast.Ident); !ok || ident.Name != "Key" {
+			return true
+		}
+		for _, value := range spec.Values {
+			lit, ok := value.(*ast.BasicLit)
+			require.True(t, ok)
+			key, err := strconv.Unquote(lit.Value)
+			require.NoError(t, err)
+			keys = append(keys, Key(key))
+		}
+		return true
+	})
+	require.NotEmpty(t, keys)
+	return keys
+}
+
+func TestCatalogs_Complete(t *testing.T) {
+	keys := declaredKeys(t)
+
+	for _, lang := range Supported() {
+		t.Run(string(lang), func(t *testing.T) {
+			catalog, ok := catalogs[lang]
+			require.True(t, ok, "missing catalog")
+
+			for _, key := range keys {
+				msg, ok := catalog[key]
+				if assert.True(t, ok, "missing translation key=%s", key) {
+					assert.NotEmpty(t, msg.Other, "empty translation key=%s", key)
+				}
+			}
+			assert.Len(t, catalog, len(keys), "catalog has keys not declared in keys.go")
+		})
+	}
+}
+
+func TestCatalogs_FormatVerbsMatchDefault(t *testing.T) {
+	for key, want := range catalogs[Default] {
+		wantVerbs := verbPattern.FindAllString(want.Other, -1)
+		for _, lang := range Supported() {
+			msg := catalogs[lang][key]
+			assert.Equal(t, wantVerbs, verbPattern.FindAllString(msg.Other, -1),
+				"format verbs differ lang=%s key=%s", lang, key)
+			if msg.One != "" {
+				assert.Equal(t, wantVerbs, verbPattern.FindAllString(msg.One, -1),
+					"plural format verbs differ lang=%s key=%s", lang, key)
+			}
+		}
+	}
+}
+
+func TestParseLang(t *testing.T) {
+	tests := []struct {
+		code   string
+		expect Lang
+		ok     bool
+	}{
+		{"zh-TW", ZhTW, true},
+		{"zh-hant", ZhTW, true},
+		{"zh_HK", ZhTW, true},
+		{"zh-hans", ZhCN, true},
+		{"zh", ZhCN, true},
+		{"zh-CN", ZhCN, true},
+		{"en", En, true},
+		{"en-US", En, true},
+		{"ru", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			lang, ok := ParseLang(tt.code)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expect, lang)
+		})
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	l := NewLocalizer(ZhTW)
+	l.catalogs = map[Lang]Catalog{
+		ZhTW: {"greeting": {Other: "你好 %s"}},
+		En: {
+			"greeting": {Other: "hello %s"},
+			"items":    {One: "%d item", Other: "%d items"},
+		},
+	}
+
+	assert.Equal(t, "hello bob", l.T(En, "greeting", "bob"))
+	assert.Equal(t, "你好 bob", l.T("ru", "greeting", "bob"), "fallback to default language")
+	assert.Equal(t, "missing", l.T(En, "missing"), "fallback to key")
+	assert.Equal(t, "1 item", l.N(En, "items", 1, 1))
+	assert.Equal(t, "3 items", l.N(En, "items", 3, 3))
+	assert.Equal(t, "20% off", l.T(En, "20% off"), "no formatting without args")
+}
+
+func TestCompileTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       Key
+		content   string
+		parseMode string
+		wantErr   bool
+		errIs     error
+	}{
+		{"plain variables", MsgVolumeSuccess, "{{.UID}} traded {{.Volume}}", "", false, nil},
+		{"markdown", MsgWelcome, "*Welcome* {{.Username}}\\!", "MarkdownV2", false, nil},
+		{"not editable", MsgProcessing, "processing", "", true, ErrTemplateKeyNotEditable},
+		{"unknown variable", MsgWelcome, "hi {{.Volume}}", "", true, nil},
+		{"invalid markdown", MsgWelcome, "hello.", "MarkdownV2", true, nil},
+		{"empty", MsgHelp, "{{/* nothing */}}", "", true, ErrEmptyTemplate},
+		{"unsupported parse mode", MsgHelp, "<b>help</b>", "HTML", true, ErrUnsupportedParseMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileTemplate(tt.key, ZhTW, 1, tt.content, tt.parseMode)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			if tt.errIs != nil {
+				assert.ErrorIs(t, err, tt.errIs)
+			}
+		})
+	}
+}
+
+func TestLocalizer_Render(t *testing.T) {
+	l := NewLocalizer(ZhTW)
+	data := TemplateData{"UID": "42", "Username": "a_b", "Volume": "1.00"}
+
+	rendered := l.Render(En, MsgVolumeSuccess, data, 1.0)
+	assert.Equal(t, l.T(En, MsgVolumeSuccess, 1.0), rendered.Text, "catalog without template")
+
+	plain, err := CompileTemplate(MsgVolumeSuccess, En, 1, "{{.UID}} volume {{.Volume}}", "")
+	require.NoError(t, err)
+	markdown, err := CompileTemplate(MsgWelcome, En, 2, "*hi* {{.Username}}", "MarkdownV2")
+	require.NoError(t, err)
+	l.SetTemplates([]*Template{plain, markdown})
+
+	assert.Equal(t, Rendered{Text: "42 volume 1.00"}, l.Render(En, MsgVolumeSuccess, data, 1.0))
+	assert.Equal(t, Rendered{Text: `*hi* a\_b`, ParseMode: "MarkdownV2"}, l.Render(En, MsgWelcome, data),
+		"values are escaped in MarkdownV2 templates")
+	assert.Equal(t, l.T(ZhTW, MsgVolumeSuccess, 1.0), l.Render(ZhTW, MsgVolumeSuccess, data, 1.0).Text,
+		"templates are per language")
+	assert.Equal(t, l.T(En, MsgVolumeSuccess, 1.0), l.Render(En, MsgVolumeSuccess, TemplateData{}, 1.0).Text,
+		"missing data falls back to catalog")
+}
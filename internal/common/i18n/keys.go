@@ -0,0 +1,193 @@
+package i18n
+
+import "ohmycontrolcenter.tech/omcc/internal/common"
+
+// General messages
+const (
+	MsgWelcome             Key = "welcome"
+	MsgHelp                Key = "help"
+	MsgProcessing          Key = "processing"
+	MsgServerError         Key = "server_error"
+	MsgInternalServerError Key = "internal_server_error"
+	MsgUnknownError        Key = "unknown_error"
+	MsgOnText              Key = "on_text"
+)
+
+// Command validation messages
+const (
+	MsgInvalidCommandFormat Key = "invalid_command_format"
+	MsgInvalidUIDFormat     Key = "invalid_uid_format"
+)
+
+// Verify and account messages
+const (
+	MsgVerifySuccess           Key = "verify.success"
+	MsgVerifySuccessLine       Key = "verify.success_line"
+	MsgVerifyInvalidUID        Key = "verify.invalid_uid"
+	MsgVerifyExistsUID         Key = "verify.exists_uid"
+	MsgVerifyExistsSocialUser  Key = "verify.exists_social_user"
+	MsgVerifyInactiveUID       Key = "verify.inactive_uid"
+	MsgAccountDuplicatedUser   Key = "account.duplicated_user"
+	MsgAccountMemberInfoUpdate Key = "account.member_info_updated"
+)
+
+// Volume and status messages
+const (
+	MsgVolumeSuccess      Key = "volume.success"
+	MsgVolumeFailure      Key = "volume.failure"
+	MsgStatusMemberStatus Key = "status.member_status"
+	MsgStatusGroupsHeader Key = "status.groups_header"
+	MsgStatusGroupLine    Key = "status.group_line"
+	MsgStatusGroupsEmpty  Key = "status.groups_empty"
+
+	MsgStatusBoundToSender     Key = "status.bound_to_sender"
+	MsgStatusBoundToOther      Key = "status.bound_to_other"
+	MsgStatusAccount           Key = "status.account"
+	MsgStatusList              Key = "status.list"
+	MsgStatusVolume            Key = "status.volume"
+	MsgStatusVolumeMet         Key = "status.volume_met"
+	MsgStatusVolumeBelow       Key = "status.volume_below"
+	MsgStatusVolumeUnavailable Key = "status.volume_unavailable"
+)
+
+// Group messages
+const (
+	MsgGroupUserWarning Key = "group.user_warning"
+	MsgGroupUserMuted   Key = "group.user_muted"
+	MsgGroupUserBanned  Key = "group.user_banned"
+)
+
+// Join request and invite link messages
+const (
+	MsgJoinRequestDeclined  Key = "join_request.declined"
+	MsgInviteLinkIssued     Key = "invite_link.issued"
+	MsgInviteLinkFailed     Key = "invite_link.failed"
+	MsgInviteLinkNotOwner   Key = "invite_link.not_owner"
+	MsgInviteLinkNotAllowed Key = "invite_link.not_allowed"
+)
+
+// Link sharing messages
+const (
+	MsgLinkSharingIntruder        Key = "link_sharing.intruder"
+	MsgLinkSharingRequestDeclined Key = "link_sharing.request_declined"
+	MsgLinkSharingAlert           Key = "link_sharing.alert"
+	MsgLinkSharingRemoved         Key = "link_sharing.removed"
+	MsgLinkSharingNotRemoved      Key = "link_sharing.not_removed"
+	MsgLinkSharingDeclined        Key = "link_sharing.declined"
+)
+
+// Rate limit messages
+const (
+	MsgRateLimited      Key = "rate_limit.throttled"
+	MsgRateLimitBlocked Key = "rate_limit.blocked"
+)
+
+// Captcha messages
+const (
+	MsgCaptchaRules        Key = "captcha.rules"
+	MsgCaptchaMath         Key = "captcha.math"
+	MsgCaptchaAcceptButton Key = "captcha.accept_button"
+	MsgCaptchaNotForYou    Key = "captcha.not_for_you"
+	MsgCaptchaPassed       Key = "captcha.passed"
+	MsgCaptchaFailed       Key = "captcha.failed"
+	MsgCaptchaExpired      Key = "captcha.expired"
+)
+
+// Language messages
+const (
+	MsgLangCurrent     Key = "lang.current"
+	MsgLangUpdated     Key = "lang.updated"
+	MsgLangUnsupported Key = "lang.unsupported"
+	MsgLangName        Key = "lang.name"
+)
+
+// Admin messages
+const (
+	MsgAdminUnauthorized    Key = "admin.unauthorized"
+	MsgAdminUsage           Key = "admin.usage"
+	MsgAdminNotFound        Key = "admin.not_found"
+	MsgAdminLookupResult    Key = "admin.lookup_result"
+	MsgAdminStatusUpdated   Key = "admin.status_updated"
+	MsgAdminUnbanned        Key = "admin.unbanned"
+	MsgAdminKicked          Key = "admin.kicked"
+	MsgAdminActionFailed    Key = "admin.action_failed"
+	MsgAdminStats           Key = "admin.stats"
+	MsgAdminActive          Key = "admin.active"
+	MsgAdminInactive        Key = "admin.inactive"
+	MsgAdminButtonLookup    Key = "admin.button.lookup"
+	MsgAdminButtonBlacklist Key = "admin.button.blacklist"
+	MsgAdminButtonWhitelist Key = "admin.button.whitelist"
+	MsgAdminButtonUnban     Key = "admin.button.unban"
+	MsgAdminButtonKick      Key = "admin.button.kick"
+)
+
+// Command descriptions, shown by /help and in the telegram command menu
+const (
+	MsgCommandStart     Key = "command.start"
+	MsgCommandHelp      Key = "command.help"
+	MsgCommandStatus    Key = "command.status"
+	MsgCommandVerify    Key = "command.verify"
+	MsgCommandVolume    Key = "command.volume"
+	MsgCommandAccount   Key = "command.account"
+	MsgCommandJoin      Key = "command.join"
+	MsgCommandLang      Key = "command.lang"
+	MsgCommandLookup    Key = "command.lookup"
+	MsgCommandBlacklist Key = "command.blacklist"
+	MsgCommandWhitelist Key = "command.whitelist"
+	MsgCommandUnban     Key = "command.unban"
+	MsgCommandKick      Key = "command.kick"
+	MsgCommandStats     Key = "command.stats"
+)
+
+// Member status labels
+const (
+	MsgMemberStatusCreator       Key = "member_status.creator"
+	MsgMemberStatusAdministrator Key = "member_status.administrator"
+	MsgMemberStatusMember        Key = "member_status.member"
+	MsgMemberStatusRestricted    Key = "member_status.restricted"
+	MsgMemberStatusLeft          Key = "member_status.left"
+	MsgMemberStatusKicked        Key = "member_status.kicked"
+	MsgMemberStatusUnknown       Key = "member_status.unknown"
+)
+
+// Customer status labels
+const (
+	MsgStatusNormal      Key = "customer_status.normal"
+	MsgStatusWhitelisted Key = "customer_status.whitelisted"
+	MsgStatusBlacklisted Key = "customer_status.blacklisted"
+	MsgStatusUnknown     Key = "customer_status.unknown"
+)
+
+// MemberStatusKey returns the label key of telegram member status
+func MemberStatusKey(m common.MemberStatus) Key {
+	switch m {
+	case common.Creator:
+		return MsgMemberStatusCreator
+	case common.Administrator:
+		return MsgMemberStatusAdministrator
+	case common.Member:
+		return MsgMemberStatusMember
+	case common.Restricted:
+		return MsgMemberStatusRestricted
+	case common.Left:
+		return MsgMemberStatusLeft
+	case common.Kicked:
+		return MsgMemberStatusKicked
+	default:
+		return MsgMemberStatusUnknown
+	}
+}
+
+// StatusKey returns the label key of customer status
+func StatusKey(s common.Status) Key {
+	switch s {
+	case common.Normal:
+		return MsgStatusNormal
+	case common.Whitelisted:
+		return MsgStatusWhitelisted
+	case common.Blacklisted:
+		return MsgStatusBlacklisted
+	default:
+		return MsgStatusUnknown
+	}
+}
@@ -0,0 +1,144 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"ohmycontrolcenter.tech/omcc/util"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// TemplateData named variables available to an editable template, e.g. {{.UID}}
+type TemplateData map[string]interface{}
+
+// Rendered localized text with the parse mode it must be sent with
+type Rendered struct {
+	Text      string
+	ParseMode tele.ParseMode
+}
+
+func (r Rendered) SendOptions() *tele.SendOptions {
+	return &tele.SendOptions{ParseMode: r.ParseMode}
+}
+
+var (
+	ErrTemplateKeyNotEditable = errors.New("template key is not editable")
+	ErrUnsupportedLanguage    = errors.New("unsupported language")
+	ErrUnsupportedParseMode   = errors.New("unsupported parse mode")
+	ErrEmptyTemplate          = errors.New("template renders empty text")
+)
+
+// templateVariables editable messages with the variables they expose
+var templateVariables = map[Key][]string{
+	MsgWelcome:            {"Username"},
+	MsgHelp:               {"Username", "Commands"},
+	MsgOnText:             {"Username"},
+	MsgVerifySuccess:      {"UID", "Username"},
+	MsgVolumeSuccess:      {"UID", "Username", "Volume"},
+	MsgStatusMemberStatus: {"UID", "Username", "Status"},
+	MsgGroupUserWarning:   {"Username"},
+}
+
+var sampleValues = map[string]string{
+	"UID":      "123456",
+	"Username": "username",
+	"Volume":   "10000.00",
+	"Status":   "member",
+	"Commands": "/help - help",
+}
+
+// TemplateKeys returns all editable message keys in order
+func TemplateKeys() []Key {
+	keys := make([]Key, 0, len(templateVariables))
+	for key := range templateVariables {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// TemplateVariables returns the variables of an editable message key
+func TemplateVariables(key Key) ([]string, bool) {
+	variables, ok := templateVariables[key]
+	return variables, ok
+}
+
+// SampleData sample values of the variables of key, used for validation and preview
+func SampleData(key Key) TemplateData {
+	data := TemplateData{}
+	for _, name := range templateVariables[key] {
+		data[name] = sampleValues[name]
+	}
+	return data
+}
+
+type Template struct {
+	Key       Key
+	Lang      Lang
+	Version   int
+	ParseMode tele.ParseMode
+	tmpl      *template.Template
+}
+
+// CompileTemplate parse content and validate it against the variables of key and the parse mode
+func CompileTemplate(key Key, lang Lang, version int, content string, parseMode tele.ParseMode) (*Template, error) {
+	if _, ok := templateVariables[key]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateKeyNotEditable, key)
+	}
+	if _, ok := catalogs[lang]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, lang)
+	}
+	if parseMode != tele.ModeDefault && parseMode != tele.ModeMarkdownV2 {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedParseMode, parseMode)
+	}
+
+	tmpl, err := template.New(string(key)).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	t := &Template{
+		Key:       key,
+		Lang:      lang,
+		Version:   version,
+		ParseMode: parseMode,
+		tmpl:      tmpl,
+	}
+
+	// executing with sample data rejects variables which are not available for the key
+	text, err := t.Execute(SampleData(key))
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrEmptyTemplate
+	}
+	if parseMode == tele.ModeMarkdownV2 {
+		if err := util.ValidateMarkdownV2(text); err != nil {
+			return nil, fmt.Errorf("invalid MarkdownV2: %w", err)
+		}
+	}
+	return t, nil
+}
+
+// Execute render the template, only the variables declared for the key are visible
+func (t *Template) Execute(data TemplateData) (string, error) {
+	values := make(map[string]interface{}, len(data))
+	for _, name := range templateVariables[t.Key] {
+		value, ok := data[name]
+		if !ok {
+			continue
+		}
+		if t.ParseMode == tele.ModeMarkdownV2 {
+			value = util.EscapeMarkdownV2(fmt.Sprint(value))
+		}
+		values[name] = value
+	}
+
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, values); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return b.String(), nil
+}
@@ -8,7 +8,37 @@ const (
 	StatusCommandName         = "/status"
 	JoinCommandName           = "/join"
 	AccountCommandName        = "/account"
+	LangCommandName           = "/lang"
 )
+
+// admin commands, only available to the configured admin ids
+const (
+	LookupCommandName    string = "/lookup"
+	BlacklistCommandName        = "/blacklist"
+	WhitelistCommandName        = "/whitelist"
+	UnbanCommandName            = "/unban"
+	KickCommandName             = "/kick"
+	StatsCommandName            = "/stats"
+)
+
+// descriptions of the commands, shown by /help and in the telegram command menu
+const (
+	StartCommandDescription     string = "開始使用機器人"
+	HelpCommandDescription             = "了解所有指令說明"
+	StatusCommandDescription           = "查詢目前電報帳號狀態"
+	VerifyCommandDescription           = "驗證您的數字UID"
+	VolumeCommandDescription           = "查詢本月交易總額"
+	AccountCommandDescription          = "更改電報帳號綁定"
+	JoinCommandDescription             = "重新取得群組邀請鏈接"
+	LangCommandDescription             = "切換機器人語言"
+	LookupCommandDescription           = "查詢會員資訊"
+	BlacklistCommandDescription        = "將會員加入黑名單"
+	WhitelistCommandDescription        = "將會員加入白名單"
+	UnbanCommandDescription            = "解除會員的群組封鎖"
+	KickCommandDescription             = "將會員移出群組"
+	StatsCommandDescription            = "會員統計"
+)
+
 const (
 	WelcomeMessage string = `🦀≡≡≡≡≡≡≡≡▷►◈◄◁≡≡≡≡≡≡≡≡🦀
 
@@ -44,24 +74,22 @@ https://t.me/wedjatbtcVIP_bot
 /verify <uid>   	  - 驗證uid指令 請輸入你的數字UID
 /volume <uid>   - 交易總額查詢 請輸入此指令
 /account <uid>  - 更改電報帳號綁定
+/join <uid>     - 重新取得群組邀請鏈接
+/lang <語言>     - 切換機器人語言
 
 有任何疑問請直接私訊本人謝謝🕳
 https://t.me/wedjatbtc
 
 🦀≡≡≡≡≡≡≡≡▷►◈◄◁≡≡≡≡≡≡≡≡🦀`
 
-	HelpMessage = "```\n" +
-		`/start          - 開始使用機器人
-/help           - 了解所有指令說明 請輸入此指令
-/status <uid>   - 查詢目前電報帳號狀態
-/verify <uid>   - 驗證uid指令，請輸入你的數字UID
-/volume <uid>   - 交易總額查詢，請輸入此指令
-/account <uid>  - 更改電報帳號綁定` +
-		"\n```"
+	// HelpMessage the command list generated from the command registry is formatted into the block
+	HelpMessage = "```\n%s\n```"
 
 	ProcessingMessage          = "正在驗證 UID，請稍候..."
 	ServerErrorMessage         = "驗證服務暫時無法使用，請稍後重試❌"
 	InternalServerErrorMessage = `伺服器處理過程中發生錯誤，請稍後重試`
+	UnknownErrorMessage        = "Unknown Error"
+	OnTextReplyMessage         = "我不是聊天機器人 有活人不聊 你找我幹啥 我能跳舞嗎 輸入指令我才幹活 不然我會罵街的"
 )
 
 const (
@@ -70,7 +98,8 @@ const (
 )
 
 const (
-	SuccessVerifyReplyMessage            string = `🦀您已驗證成功!感謝關注!✅\n以下是交流群以及VIP群的鏈接`
+	SuccessVerifyReplyMessage            string = "🦀您已驗證成功!感謝關注!✅\n以下是交流群以及VIP群的鏈接"
+	LineSuccessVerifyReplyMessage               = "🦀您已驗證成功!感謝關注!✅"
 	InvalidUidVerifyReplyMessage                = `🦀您輸入的UID不存在 驗證失敗❌ 請查詢正確後再次輸入`
 	ExistsUidVerifyReplyMessage                 = `🦀您要驗證的uid已存在,無須再次驗證!祝您交易順利!✅`
 	ExistsSocialUserIdVerifyReplyMessage        = `🦀您已綁定過電報帳號 請使用/account變更您的綁定電報帳號❌`
@@ -88,6 +117,86 @@ const (
 	MemberInfoUpdatedMessage        = "🦀您目前的社交帳號資訊已更新成功✅"
 )
 
+const (
+	GroupMembershipHeaderMessage string = "👥您目前所在的群組："
+	GroupMembershipLineMessage          = "• %s： %s"
+	GroupMembershipEmptyMessage         = "👥您目前不在任何群組中"
+)
+
+const (
+	StatusBoundToSenderMessage     string = "🔗UID: %s 已綁定您目前的帳號✅"
+	StatusBoundToOtherMessage             = "🔗UID: %s 未綁定您目前的帳號❌ 請使用/account %s 變更綁定"
+	StatusAccountMessage                  = "👤帳號狀態: %s"
+	StatusListMessage                     = "📝名單狀態: %s"
+	StatusVolumeMessage                   = "📈本月交易額: USDT$%.2f"
+	StatusVolumeMetMessage                = "📈本月交易額: USDT$%.2f, 已達到門檻 USDT$%.2f✅"
+	StatusVolumeBelowMessage              = "📈本月交易額: USDT$%.2f, 未達到門檻 USDT$%.2f❌"
+	StatusVolumeUnavailableMessage        = "📈暫時無法查詢本月交易額, 請稍後使用/volume %s 查詢"
+)
+
 const (
 	UserWarningMessage string = "⚠️ @%s 請不要在群組中發送任何与指令 電報链接 網頁連結 UID...等等敏感訊息 謝謝合作"
+	UserMutedMessage          = "🔇 @%s 已被禁言 %d 分鐘，累計違規 %d 次"
+	UserBannedMessage         = "⛔ @%s 累計違規 %d 次，已被移出並封鎖"
+)
+
+const (
+	LangCurrentMessage     string = "🌐目前語言為: %s\n可用語言: %s\n請使用 /lang <語言> 進行切換"
+	LangUpdatedMessage            = "🌐語言已切換為: %s✅"
+	LangUnsupportedMessage        = "❌不支援的語言: %s\n可用語言: %s"
+	LangNameMessage               = "繁體中文"
+)
+
+const (
+	AdminUnauthorizedMessage    string = "⛔您沒有權限使用此指令"
+	AdminUsageMessage                  = "❌请使用正确的格式：%s <UID|@用戶名>\n範例：%s 123456"
+	AdminNotFoundMessage               = "❌找不到此用戶: %s"
+	AdminLookupResultMessage           = "🔎查詢結果\nUID: %s\n客戶ID: %s\n電報ID: %s\n用戶名: @%s\n姓名: %s %s\n帳號狀態: %s\n名單狀態: %s\n群組狀態: %s\n綁定時間: %s"
+	AdminStatusUpdatedMessage          = "✅UID: %s 名單狀態已更新為: %s"
+	AdminUnbannedMessage               = "✅UID: %s 已解除群組封鎖"
+	AdminKickedMessage                 = "✅UID: %s 已被移出群組"
+	AdminActionFailedMessage           = "❌UID: %s 操作失敗，請稍後重試"
+	AdminStatsMessage                  = "📊會員統計\n總數: %d\n活躍: %d\n非活躍: %d\n一般會員: %d\n白名單: %d\n黑名單: %d"
+	AdminActiveMessage                 = "活躍"
+	AdminInactiveMessage               = "非活躍"
+	AdminButtonLookupMessage           = "🔎查詢"
+	AdminButtonBlacklistMessage        = "⛔黑名單"
+	AdminButtonWhitelistMessage        = "✅白名單"
+	AdminButtonUnbanMessage            = "🔓解除封鎖"
+	AdminButtonKickMessage             = "👢移出群組"
+)
+
+const (
+	JoinRequestDeclinedMessage string = "🦀您申請加入 %s 未通過❌\n請先私訊機器人使用 /verify <uid> 驗證您的UID，驗證成功後再使用邀請鏈接申請加入\n若您已驗證，請使用 /status <uid> 查詢帳號狀態或聯絡群組主"
+)
+
+const (
+	InviteLinkIssuedMessage     string = "🦀已重新產生您的群組邀請鏈接✅ 舊的鏈接已失效"
+	InviteLinkFailedMessage            = "❌邀請鏈接產生失敗，請稍後使用 /join %s 重新取得"
+	InviteLinkNotOwnerMessage          = "❌此UID並未綁定您目前的電報帳號，請使用 /account %s 變更綁定"
+	InviteLinkNotAllowedMessage        = "🦀此UID目前無法取得邀請鏈接 請使用 /status %s 查詢帳號狀態或聯絡群組主❌"
+)
+
+const (
+	LinkSharingIntruderMessage        string = "🚫您使用了發給其他會員的邀請鏈接加入 %s，已被移出群組\n請私訊機器人使用 /verify <uid> 驗證您自己的UID以取得專屬邀請鏈接"
+	LinkSharingRequestDeclinedMessage        = "🚫您使用了發給其他會員的邀請鏈接申請加入 %s，申請已被拒絕\n請私訊機器人使用 /verify <uid> 驗證您自己的UID以取得專屬邀請鏈接"
+	LinkSharingAlertMessage                  = "⚠️偵測到邀請鏈接被分享\n群組: %s\n鏈接擁有者: 客戶 %s (電報ID: %s)\n加入者: %s (@%s)\n處理結果: %s"
+	LinkSharingRemovedMessage                = "已移出群組，鏈接已撤銷"
+	LinkSharingNotRemovedMessage             = "移出失敗，請手動處理"
+	LinkSharingDeclinedMessage               = "已拒絕入群申請，鏈接已撤銷"
+)
+
+const (
+	RateLimitedMessage      string = "⏳您的操作太頻繁了，請在 %d 秒後再試"
+	RateLimitBlockedMessage        = "⛔您的操作過於頻繁，已被暫時封鎖 %d 分鐘"
+)
+
+const (
+	CaptchaRulesMessage        string = "👋歡迎 %s 加入 %s\n請在 %d 秒內點擊下方按鈕同意群組規則，否則將被移出群組"
+	CaptchaMathMessage                = "👋歡迎 %s 加入 %s\n請在 %d 秒內回答: %d + %d = ?，否則將被移出群組"
+	CaptchaAcceptButtonMessage        = "✅我同意群組規則"
+	CaptchaNotForYouMessage           = "這不是給您的驗證"
+	CaptchaPassedMessage              = "✅驗證成功，歡迎加入"
+	CaptchaFailedMessage              = "❌答案錯誤，您已被移出群組"
+	CaptchaExpiredMessage             = "此驗證已失效"
 )
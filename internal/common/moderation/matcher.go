@@ -0,0 +1,169 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidRule = errors.New("invalid moderation rule")
+
+var linkPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)[^\s]+|\bt\.me/[^\s]+`)
+
+// Message the content of a group message checked against the rules
+type Message struct {
+	Text string
+	// URLs links carried outside the visible text
+	URLs []string
+	// ForwardedChannel username, or id when it has none, of the channel the message was forwarded from
+	ForwardedChannel string
+}
+
+// Links returns the links written in the text followed by the extra URLs
+func (m *Message) Links() []string {
+	return append(linkPattern.FindAllString(m.Text, -1), m.URLs...)
+}
+
+// Matcher reports whether a message breaks a rule
+type Matcher interface {
+	Match(msg *Message) bool
+}
+
+// Compile builds the matcher of a rule, the pattern is a regular expression or a list
+// of keywords, allowed domains or allowed channels separated by commas or new lines
+func Compile(matchType common.RuleMatchType, pattern string) (Matcher, error) {
+	switch matchType {
+	case common.RuleMatchRegex:
+		if pattern == "" {
+			return nil, fmt.Errorf("%w: regular expression is empty", ErrInvalidRule)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+		return &regexMatcher{re: re}, nil
+	case common.RuleMatchKeyword:
+		keywords := splitList(strings.ToLower(pattern))
+		if len(keywords) == 0 {
+			return nil, fmt.Errorf("%w: keyword list is empty", ErrInvalidRule)
+		}
+		return &keywordMatcher{keywords: keywords}, nil
+	case common.RuleMatchDomainAllowlist:
+		domains := splitList(strings.ToLower(pattern))
+		for i, domain := range domains {
+			domains[i] = strings.TrimPrefix(domain, "www.")
+		}
+		return &domainAllowlistMatcher{domains: domains}, nil
+	case common.RuleMatchChannelForward:
+		channels := splitList(strings.ToLower(pattern))
+		for i, channel := range channels {
+			channels[i] = strings.TrimPrefix(channel, "@")
+		}
+		return &channelForwardMatcher{channels: channels}, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported match type %q", ErrInvalidRule, matchType)
+}
+
+type regexMatcher struct {
+	re *regexp.Regexp
+}
+
+func (m *regexMatcher) Match(msg *Message) bool {
+	if m.re.MatchString(msg.Text) {
+		return true
+	}
+	for _, link := range msg.URLs {
+		if m.re.MatchString(link) {
+			return true
+		}
+	}
+	return false
+}
+
+// keywordMatcher matches any of the keywords, case insensitive
+type keywordMatcher struct {
+	keywords []string
+}
+
+func (m *keywordMatcher) Match(msg *Message) bool {
+	texts := []string{strings.ToLower(msg.Text)}
+	for _, link := range msg.URLs {
+		texts = append(texts, strings.ToLower(link))
+	}
+	for _, text := range texts {
+		for _, keyword := range m.keywords {
+			if strings.Contains(text, keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// domainAllowlistMatcher matches any link outside the allowed domains and their subdomains
+type domainAllowlistMatcher struct {
+	domains []string
+}
+
+func (m *domainAllowlistMatcher) Match(msg *Message) bool {
+	for _, link := range msg.Links() {
+		if !m.allowed(hostOf(link)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *domainAllowlistMatcher) allowed(host string) bool {
+	if host == "" {
+		return false
+	}
+	for _, domain := range m.domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// channelForwardMatcher matches any message forwarded from a channel outside the allowed channels
+type channelForwardMatcher struct {
+	channels []string
+}
+
+func (m *channelForwardMatcher) Match(msg *Message) bool {
+	if msg.ForwardedChannel == "" {
+		return false
+	}
+	channel := strings.ToLower(msg.ForwardedChannel)
+	for _, allowed := range m.channels {
+		if channel == allowed {
+			return false
+		}
+	}
+	return true
+}
+
+func hostOf(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
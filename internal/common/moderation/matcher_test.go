@@ -0,0 +1,72 @@
+package moderation
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"testing"
+)
+
+func TestCompile_Regex(t *testing.T) {
+	matcher, err := Compile(common.RuleMatchRegex, `^/\w+`)
+	require.NoError(t, err)
+
+	assert.True(t, matcher.Match(&Message{Text: "/start"}))
+	assert.False(t, matcher.Match(&Message{Text: "hello /start"}))
+
+	_, err = Compile(common.RuleMatchRegex, `(`)
+	assert.ErrorIs(t, err, ErrInvalidRule)
+}
+
+func TestCompile_Keyword(t *testing.T) {
+	matcher, err := Compile(common.RuleMatchKeyword, "airdrop, Free Money\npump")
+	require.NoError(t, err)
+
+	assert.True(t, matcher.Match(&Message{Text: "Claim your AIRDROP now"}))
+	assert.True(t, matcher.Match(&Message{Text: "free money inside"}))
+	assert.False(t, matcher.Match(&Message{Text: "good morning"}))
+
+	_, err = Compile(common.RuleMatchKeyword, " , \n")
+	assert.ErrorIs(t, err, ErrInvalidRule)
+}
+
+func TestCompile_DomainAllowlist(t *testing.T) {
+	matcher, err := Compile(common.RuleMatchDomainAllowlist, "bitget.com, www.example.org")
+	require.NoError(t, err)
+
+	assert.False(t, matcher.Match(&Message{Text: "no links here"}))
+	assert.False(t, matcher.Match(&Message{Text: "see https://www.bitget.com/events"}))
+	assert.False(t, matcher.Match(&Message{Text: "docs at https://docs.example.org"}))
+	assert.True(t, matcher.Match(&Message{Text: "join t.me/scamgroup"}))
+	assert.True(t, matcher.Match(&Message{Text: "https://bitget.com.evil.io/login"}))
+	// links carried outside the text are checked too
+	assert.True(t, matcher.Match(&Message{Text: "click here", URLs: []string{"https://evil.io"}}))
+}
+
+func TestCompile_UnsupportedType(t *testing.T) {
+	_, err := Compile("unknown", "x")
+	assert.ErrorIs(t, err, ErrInvalidRule)
+}
+
+func TestCompile_ChannelForward(t *testing.T) {
+	matcher, err := Compile(common.RuleMatchChannelForward, "@OfficialNews")
+	require.NoError(t, err)
+
+	assert.False(t, matcher.Match(&Message{Text: "not forwarded"}))
+	assert.False(t, matcher.Match(&Message{Text: "news", ForwardedChannel: "officialnews"}))
+	assert.True(t, matcher.Match(&Message{Text: "promo", ForwardedChannel: "spamchannel"}))
+
+	// an empty list forbids the forwards of every channel
+	matcher, err = Compile(common.RuleMatchChannelForward, "")
+	require.NoError(t, err)
+	assert.True(t, matcher.Match(&Message{ForwardedChannel: "-1001234567890"}))
+}
+
+func TestCompile_HiddenLinks(t *testing.T) {
+	keyword, err := Compile(common.RuleMatchKeyword, "scam.io")
+	require.NoError(t, err)
+	assert.True(t, keyword.Match(&Message{Text: "read the docs", URLs: []string{"https://scam.io/x"}}))
+
+	_, err = Compile(common.RuleMatchRegex, "")
+	assert.ErrorIs(t, err, ErrInvalidRule)
+}
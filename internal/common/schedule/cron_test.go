@@ -0,0 +1,51 @@
+package schedule
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, expr string) *Cron {
+	cron, err := ParseCron(expr)
+	require.NoError(t, err)
+	return cron
+}
+
+func TestCron_Next(t *testing.T) {
+	// a wednesday
+	from := time.Date(2026, 1, 7, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 1, 7, 10, 31, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2026, 1, 8, 9, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 1, 7, 10, 45, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2026, 1, 7, 13, 0, 0, 0, time.UTC)},
+		{"0 20 * * 1,5", time.Date(2026, 1, 9, 20, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC)},
+		{"30 8 1 * *", time.Date(2026, 2, 1, 8, 30, 0, 0, time.UTC)},
+		{"0 12 29 2 *", time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)},
+		// day of month and day of week both restricted match either
+		{"0 0 15 * 5", time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			assert.Equal(t, tt.want, mustParse(t, tt.expr).Next(from))
+		})
+	}
+}
+
+func TestCron_NextNeverMatches(t *testing.T) {
+	assert.True(t, mustParse(t, "0 0 30 2 *").Next(time.Now()).IsZero())
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := ParseCron(expr)
+		assert.ErrorIs(t, err, ErrInvalidCron, expr)
+	}
+}
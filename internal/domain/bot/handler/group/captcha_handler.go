@@ -0,0 +1,140 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/outbound"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+	"strings"
+)
+
+var (
+	CaptchaAcceptButton = tele.Btn{Unique: "captcha_accept"}
+	CaptchaAnswerButton = tele.Btn{Unique: "captcha_answer"}
+)
+
+// CaptchaHandler sends the join challenge to the new members and handles their answers
+type CaptchaHandler struct {
+	bot            *tele.Bot
+	sender         *outbound.Queue
+	log            logger.Logger
+	localizer      *i18n.Localizer
+	captchaService *service.CaptchaService
+}
+
+func NewCaptchaHandler(bot *tele.Bot, sender *outbound.Queue, log logger.Logger, localizer *i18n.Localizer, captchaService *service.CaptchaService) *CaptchaHandler {
+	return &CaptchaHandler{
+		bot:            bot,
+		sender:         sender,
+		log:            log,
+		localizer:      localizer,
+		captchaService: captchaService,
+	}
+}
+
+// Challenge restricts the new member and asks them to accept the rules or solve the sum
+func (h *CaptchaHandler) Challenge(c tele.Context, chat *tele.Chat, user *tele.User) error {
+	if !h.captchaService.Enabled(chat.ID) || user.IsBot {
+		return nil
+	}
+	if err := h.captchaService.Restrict(chat, user); err != nil {
+		return fmt.Errorf("failed to restrict new member: %w", err)
+	}
+
+	lang := i18n.FromContext(c)
+	seconds := int(h.captchaService.Timeout().Seconds())
+	userId := strconv.FormatInt(user.ID, 10)
+	markup := &tele.ReplyMarkup{}
+	var text string
+	answer := 0
+	if h.captchaService.MathMode() {
+		question := h.captchaService.NewQuestion()
+		answer = question.A + question.B
+		text = h.localizer.T(lang, i18n.MsgCaptchaMath, mention(user), chat.Title, seconds, question.A, question.B)
+		buttons := make([]tele.Btn, 0, len(question.Options))
+		for _, option := range question.Options {
+			buttons = append(buttons, markup.Data(strconv.Itoa(option), CaptchaAnswerButton.Unique, userId, strconv.Itoa(option)))
+		}
+		markup.Inline(markup.Row(buttons...))
+	} else {
+		text = h.localizer.T(lang, i18n.MsgCaptchaRules, mention(user), chat.Title, seconds)
+		markup.Inline(markup.Row(markup.Data(h.localizer.T(lang, i18n.MsgCaptchaAcceptButton), CaptchaAcceptButton.Unique, userId)))
+	}
+
+	message, err := h.sender.Send(context.TODO(), outbound.Interactive, chat, text, markup)
+	if err == nil {
+		err = h.captchaService.Begin(context.TODO(), chat, user, message, answer)
+	}
+	if err != nil {
+		// a member without a challenge could never be released, lift the restriction instead
+		if liftErr := h.bot.Restrict(chat, &tele.ChatMember{User: user, Rights: tele.NoRestrictions()}); liftErr != nil {
+			h.log.Error("failed to lift captcha restriction", logger.Int64("user_id", user.ID), logger.Error(liftErr))
+		}
+		return fmt.Errorf("failed to start captcha challenge: %w", err)
+	}
+
+	h.log.Info("captcha challenge sent",
+		logger.Int64("chat_id", chat.ID),
+		logger.Int64("user_id", user.ID),
+		logger.String("username", user.Username))
+	return nil
+}
+
+// HandleCallback handles the rules button and the answer buttons of the challenge
+func (h *CaptchaHandler) HandleCallback(c tele.Context) error {
+	callback := c.Callback()
+	args := strings.Split(callback.Data, "|")
+	userId, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return c.Respond()
+	}
+	if callback.Sender.ID != userId {
+		return c.Respond(&tele.CallbackResponse{Text: h.t(c, i18n.MsgCaptchaNotForYou), ShowAlert: true})
+	}
+
+	answer := 0
+	if len(args) > 1 {
+		if answer, err = strconv.Atoi(args[1]); err != nil {
+			return c.Respond()
+		}
+	}
+
+	passed, err := h.captchaService.Answer(context.TODO(), c.Chat(), callback.Sender, answer)
+	if errors.Is(err, service.ErrCaptchaNotFound) {
+		return c.Respond(&tele.CallbackResponse{Text: h.t(c, i18n.MsgCaptchaExpired)})
+	}
+	if err != nil {
+		_ = c.Respond()
+		return err
+	}
+
+	h.log.Info("captcha challenge answered",
+		logger.Int64("chat_id", c.Chat().ID),
+		logger.Int64("user_id", userId),
+		logger.Any("passed", passed))
+	if passed {
+		return c.Respond(&tele.CallbackResponse{Text: h.t(c, i18n.MsgCaptchaPassed)})
+	}
+	return c.Respond(&tele.CallbackResponse{Text: h.t(c, i18n.MsgCaptchaFailed), ShowAlert: true})
+}
+
+// Cancel drops the challenge of a member leaving the group before answering
+func (h *CaptchaHandler) Cancel(chat *tele.Chat, user *tele.User) error {
+	return h.captchaService.Cancel(context.TODO(), chat, user)
+}
+
+func (h *CaptchaHandler) t(c tele.Context, key i18n.Key, args ...interface{}) string {
+	return h.localizer.T(i18n.FromContext(c), key, args...)
+}
+
+func mention(user *tele.User) string {
+	if user.Username != "" {
+		return "@" + user.Username
+	}
+	return strings.TrimSpace(user.FirstName + " " + user.LastName)
+}
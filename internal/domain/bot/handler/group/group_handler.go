@@ -1,23 +1,43 @@
 package group
 
 import (
+	"context"
 	"fmt"
 	tele "gopkg.in/telebot.v3"
 	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/common/moderation"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service"
 	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/outbound"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
 	"regexp"
+	"strconv"
 	"time"
 )
 
+const (
+	// commandPatternsRuleName logged for the messages matching the configured command patterns
+	commandPatternsRuleName = "command_patterns"
+	defaultWarningDuration  = 10 * time.Second
+)
+
 type MessageHandler struct {
 	bot             *tele.Bot
 	log             logger.Logger
+	localizer       *i18n.Localizer
 	cfg             *config.TelegramConfig
 	commandPatterns []*regexp.Regexp
+	penaltyService  *service.PenaltyService
+	moderation      *service.ModerationService
+	queue           *service.DelayedActionService
+	sender          *outbound.Queue
 }
 
-func NewGroupMessageHandler(cfg *config.TelegramConfig, bot *tele.Bot, log logger.Logger) *MessageHandler {
+func NewGroupMessageHandler(cfg *config.TelegramConfig, bot *tele.Bot, log logger.Logger, localizer *i18n.Localizer,
+	penaltyService *service.PenaltyService, moderationService *service.ModerationService,
+	queue *service.DelayedActionService, sender *outbound.Queue) *MessageHandler {
 	var patterns []*regexp.Regexp
 	for _, pattern := range cfg.CommandPatterns {
 		patterns = append(patterns, regexp.MustCompile(pattern))
@@ -25,8 +45,13 @@ func NewGroupMessageHandler(cfg *config.TelegramConfig, bot *tele.Bot, log logge
 	return &MessageHandler{
 		bot:             bot,
 		log:             log,
+		localizer:       localizer,
 		cfg:             cfg,
 		commandPatterns: patterns,
+		penaltyService:  penaltyService,
+		moderation:      moderationService,
+		queue:           queue,
+		sender:          sender,
 	}
 }
 
@@ -47,13 +72,27 @@ func (h *MessageHandler) Handle(c tele.Context) error {
 		return nil
 	}
 
-	if h.shouldDeleteMessage(message, c) {
-		return h.deleteMessage(c)
+	content := moderationMessage(message)
+	for _, verdict := range h.evaluateMessage(message, content, c) {
+		if verdict.Shadow || h.cfg.ModerationShadow {
+			// shadow mode, only record what would have happened
+			verdict.Shadow = true
+			h.audit(message, content, verdict, nil)
+			continue
+		}
+		if verdict.Action == common.RuleActionIgnore {
+			return nil
+		}
+		return h.enforce(c, content, verdict)
 	}
 	return nil
 }
 
 func (h *MessageHandler) shouldProcessMessage(c tele.Context) bool {
+	// groups with database rules are scoped per topic by the rules themselves
+	if h.moderation.HasRules(c.Chat().ID) {
+		return true
+	}
 	if !h.isTargetGroup(c.Chat().ID) {
 		return false
 	}
@@ -65,29 +104,156 @@ func (h *MessageHandler) shouldProcessMessage(c tele.Context) bool {
 	return true
 }
 
-func (h *MessageHandler) shouldDeleteMessage(msg *tele.Message, c tele.Context) bool {
-	// no behaviour if test is empty
-	if msg.Text == "" {
-		return false
+// evaluateMessage returns the rules the message breaks, the configured command patterns
+// are used with a warning for groups without database rules
+func (h *MessageHandler) evaluateMessage(msg *tele.Message, content *moderation.Message, c tele.Context) []*service.ModerationVerdict {
+	// no behaviour if there is nothing to check
+	if content.Text == "" && len(content.URLs) == 0 && content.ForwardedChannel == "" {
+		return nil
+	}
+	if msg.Sender == nil {
+		return nil
 	}
 
-	chatMember, _ := h.bot.ChatMemberOf(&tele.Chat{ID: c.Message().Chat.ID}, &tele.User{ID: msg.Sender.ID})
+	chatMember, err := h.bot.ChatMemberOf(&tele.Chat{ID: c.Message().Chat.ID}, &tele.User{ID: msg.Sender.ID})
+	if err != nil || chatMember == nil {
+		// the admins are exempt, a sender whose role is unknown is left alone
+		h.log.Error("failed to get chat member, skipped moderation",
+			logger.Int64("chat_id", msg.Chat.ID),
+			logger.Int64("user_id", msg.Sender.ID),
+			logger.Error(err))
+		return nil
+	}
 	if chatMember.Role == tele.Administrator || chatMember.Role == tele.Creator {
-		return false
+		return nil
+	}
+
+	if h.moderation.HasRules(msg.Chat.ID) {
+		verdicts := h.moderation.Evaluate(msg.Chat.ID, msg.ThreadID, msg.Sender.ID, content)
+		for _, verdict := range verdicts {
+			h.log.Info("detected moderation rule in group",
+				logger.Int64("rule_id", verdict.Rule.ID),
+				logger.String("action", string(verdict.Action)),
+				logger.Any("shadow", verdict.Shadow),
+				logger.String("text", content.Text),
+				logger.Any("urls", content.URLs),
+				logger.String("forwarded_channel", content.ForwardedChannel),
+				logger.Int64("chat_id", msg.Chat.ID),
+				logger.String("username", msg.Sender.Username),
+			)
+		}
+		return verdicts
 	}
+	if h.moderation.IsTrusted(msg.Chat.ID, msg.Sender.ID) {
+		return nil
+	}
+
 	// check if its forbidden message by regex
 	for _, pattern := range h.commandPatterns {
-		if pattern.MatchString(msg.Text) {
+		if pattern.MatchString(content.Text) {
 			h.log.Info("detected command pattern in group",
-				logger.String("text", msg.Text),
+				logger.String("text", content.Text),
 				logger.Int64("chat_id", msg.Chat.ID),
 				logger.String("username", msg.Sender.Username),
 			)
-			return true
+			return []*service.ModerationVerdict{{Action: common.RuleActionWarn}}
 		}
 	}
 
-	return false
+	return nil
+}
+
+// moderationMessage collects what the rules check: the text or the media caption, the links
+// of the url and text link entities, hidden behind a harmless text, and the forwarding channel
+func moderationMessage(msg *tele.Message) *moderation.Message {
+	content := &moderation.Message{Text: msg.Text}
+	entities := msg.Entities
+	if content.Text == "" {
+		content.Text = msg.Caption
+		entities = msg.CaptionEntities
+	}
+
+	for _, entity := range entities {
+		switch entity.Type {
+		case tele.EntityURL:
+			content.URLs = append(content.URLs, msg.EntityText(entity))
+		case tele.EntityTextLink:
+			content.URLs = append(content.URLs, entity.URL)
+		}
+	}
+
+	if channel := msg.OriginalChat; channel != nil && channel.Type == tele.ChatChannel {
+		content.ForwardedChannel = channel.Username
+		if content.ForwardedChannel == "" {
+			content.ForwardedChannel = strconv.FormatInt(channel.ID, 10)
+		}
+	}
+	return content
+}
+
+// enforce deletes the message, warn and mute also add a strike and notify the group,
+// the action is written to the moderation log
+func (h *MessageHandler) enforce(c tele.Context, content *moderation.Message, verdict *service.ModerationVerdict) error {
+	if err := h.deleteMessage(c); err != nil {
+		return err
+	}
+
+	msg := c.Message()
+	if verdict.Action == common.RuleActionDelete {
+		h.audit(msg, content, verdict, nil)
+		return nil
+	}
+
+	//Optional: sending warning message for forbidden messages
+	warning, penalty := h.penalize(c, msg, verdict)
+	h.audit(msg, content, verdict, penalty)
+	warningMsg, err := h.sender.Send(context.TODO(), outbound.Interactive, msg.Chat, warning.Text, &tele.SendOptions{
+		ThreadID:  msg.ThreadID,
+		ParseMode: warning.ParseMode,
+	})
+	if err != nil {
+		h.log.Error("failed to send warning message", logger.Error(err))
+		return err
+	}
+
+	// delete the warning message after the configured duration, queued so it survives a restart
+	if err := h.queue.ScheduleDeletion(context.TODO(), warningMsg, h.warningDuration()); err != nil {
+		h.log.Error("failed to queue warning deletion",
+			logger.Int64("chat_id", warningMsg.Chat.ID),
+			logger.Int("message_id", warningMsg.ID),
+			logger.Error(err))
+	}
+
+	return nil
+}
+
+// audit persists the action taken on the message, penalty is nil when no strike was recorded
+func (h *MessageHandler) audit(msg *tele.Message, content *moderation.Message, verdict *service.ModerationVerdict, penalty *service.Penalty) {
+	entry := &model.ModerationLog{
+		GroupID:   msg.Chat.ID,
+		TopicID:   msg.ThreadID,
+		UserID:    msg.Sender.ID,
+		Username:  msg.Sender.Username,
+		MessageID: msg.ID,
+		Text:      content.Text,
+		RuleName:  commandPatternsRuleName,
+		Action:    verdict.Action,
+		Shadow:    verdict.Shadow,
+	}
+	if verdict.Rule != nil {
+		entry.RuleID = &verdict.Rule.ID
+		entry.RuleName = verdict.Rule.Name
+	}
+	if penalty != nil {
+		entry.Penalty = penalty.Action
+		entry.Strikes = penalty.Strikes
+	}
+	if err := h.moderation.Audit(context.TODO(), entry); err != nil {
+		h.log.Error("failed to write moderation log",
+			logger.Int64("chat_id", msg.Chat.ID),
+			logger.Int("message_id", msg.ID),
+			logger.Error(err))
+	}
 }
 
 func (h *MessageHandler) deleteMessage(c tele.Context) error {
@@ -110,32 +276,55 @@ func (h *MessageHandler) deleteMessage(c tele.Context) error {
 		logger.String("text", msg.Text),
 		logger.String("username", msg.Sender.Username),
 	)
+	return nil
+}
 
-	//Optional: sending warning message for forbidden messages
-	warning := fmt.Sprintf(common.UserWarningMessage, msg.Sender.Username)
-	warningMsg, err := c.Bot().Send(msg.Chat, warning, &tele.SendOptions{
-		ThreadID: msg.ThreadID,
-	})
+// penalize records the strike of the sender and applies the escalated penalty,
+// the returned notice tells the group what happened, the penalty is nil when the strike could not be recorded
+func (h *MessageHandler) penalize(c tele.Context, msg *tele.Message, verdict *service.ModerationVerdict) (i18n.Rendered, *service.Penalty) {
+	lang := i18n.FromContext(c)
+	warning := h.localizer.Render(lang, i18n.MsgGroupUserWarning, i18n.TemplateData{
+		"Username": msg.Sender.Username,
+	}, msg.Sender.Username)
+
+	penalty, err := h.penaltyService.Record(context.TODO(), msg.Chat.ID, msg.Sender)
 	if err != nil {
-		h.log.Error("failed to send warning message", logger.Error(err))
-		return err
+		h.log.Error("failed to record violation", logger.Error(err))
+		return warning, nil
+	}
+	// a mute rule mutes at once, unless the strikes already escalated further
+	if verdict.Action == common.RuleActionMute && penalty.Action == common.PenaltyWarn {
+		penalty = h.penaltyService.Mute(penalty, time.Duration(verdict.Rule.MuteDuration)*time.Second)
+	}
+	if err := h.penaltyService.Apply(msg.Chat, msg.Sender, penalty); err != nil {
+		h.log.Error("failed to apply penalty",
+			logger.Int64("chat_id", msg.Chat.ID),
+			logger.Int64("user_id", msg.Sender.ID),
+			logger.String("action", string(penalty.Action)),
+			logger.Error(err))
+		return warning, penalty
 	}
 
-	// delete the warning message after 10 secs
-	time.AfterFunc(10*time.Second, func() {
-		_ = c.Bot().Delete(warningMsg)
-	})
-
-	return nil
+	h.log.Info("penalty applied",
+		logger.Int64("chat_id", msg.Chat.ID),
+		logger.Int64("user_id", msg.Sender.ID),
+		logger.String("action", string(penalty.Action)),
+		logger.Int("strikes", penalty.Strikes))
+	switch penalty.Action {
+	case common.PenaltyMute:
+		return i18n.Rendered{Text: h.localizer.T(lang, i18n.MsgGroupUserMuted,
+			msg.Sender.Username, int(penalty.Duration.Minutes()), penalty.Strikes)}, penalty
+	case common.PenaltyBan:
+		return i18n.Rendered{Text: h.localizer.T(lang, i18n.MsgGroupUserBanned, msg.Sender.Username, penalty.Strikes)}, penalty
+	}
+	return warning, penalty
 }
 
-func (h *MessageHandler) isCommandFormat(text string) bool {
-	for _, pattern := range h.commandPatterns {
-		if pattern.MatchString(text) {
-			return true
-		}
+func (h *MessageHandler) warningDuration() time.Duration {
+	if h.cfg.WarningDuration <= 0 {
+		return defaultWarningDuration
 	}
-	return false
+	return time.Duration(h.cfg.WarningDuration) * time.Second
 }
 
 func (h *MessageHandler) isTargetGroup(chatId int64) bool {
@@ -0,0 +1,132 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/outbound"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"slices"
+	"strconv"
+)
+
+// JoinRequestHandler approves the join requests of verified customers and declines everyone else
+type JoinRequestHandler struct {
+	bot         *tele.Bot
+	sender      *outbound.Queue
+	log         logger.Logger
+	localizer   *i18n.Localizer
+	cfg         *config.TelegramConfig
+	joinService *service.JoinService
+	inviteLinks *service.InviteLinkService
+}
+
+func NewJoinRequestHandler(cfg *config.TelegramConfig, bot *tele.Bot, sender *outbound.Queue, log logger.Logger, localizer *i18n.Localizer,
+	joinService *service.JoinService, inviteLinks *service.InviteLinkService) *JoinRequestHandler {
+	return &JoinRequestHandler{
+		bot:         bot,
+		sender:      sender,
+		log:         log,
+		localizer:   localizer,
+		cfg:         cfg,
+		joinService: joinService,
+		inviteLinks: inviteLinks,
+	}
+}
+
+func (h *JoinRequestHandler) Handle(c tele.Context) error {
+	request := c.ChatJoinRequest()
+	if request == nil || request.Sender == nil {
+		return nil
+	}
+	groupIds, err := h.cfg.GroupIds()
+	if err != nil {
+		return err
+	}
+	// requests of groups not managed by the bot are left to the group admins
+	if !slices.Contains(groupIds, request.Chat.ID) {
+		return nil
+	}
+
+	logFields := []logger.Field{
+		logger.Int64("chat_id", request.Chat.ID),
+		logger.Int64("user_id", request.Sender.ID),
+		logger.String("username", request.Sender.Username),
+	}
+	if request.InviteLink != nil {
+		logFields = append(logFields, logger.String("invite_link", request.InviteLink.InviteLink))
+	}
+
+	userId := strconv.FormatInt(request.Sender.ID, 10)
+	canJoin, err := h.joinService.CanJoin(context.TODO(), common.Telegram, userId)
+	if err != nil {
+		// keep the request pending, it can still be handled manually
+		return err
+	}
+
+	if canJoin && request.InviteLink != nil {
+		// a customer can only join with the links issued to them, the request is checked before the user reads the group
+		declined, err := h.checkLinkSharing(c, request, logFields)
+		if err != nil || declined {
+			return err
+		}
+	}
+
+	if canJoin {
+		if err := h.bot.ApproveJoinRequest(request.Chat, request.Sender); err != nil {
+			return fmt.Errorf("failed to approve join request: %w", err)
+		}
+		h.log.Info("join request approved", logFields...)
+		if request.InviteLink != nil {
+			if err := h.inviteLinks.MarkUsed(context.TODO(), request.InviteLink.InviteLink, userId); err != nil {
+				h.log.Error("failed to mark invite link used", append(logFields, logger.Error(err))...)
+			}
+		}
+		return nil
+	}
+
+	if err := h.decline(c, request, i18n.MsgJoinRequestDeclined, logFields); err != nil {
+		return err
+	}
+	h.log.Info("join request declined", logFields...)
+	return nil
+}
+
+// checkLinkSharing declines the request when the invite link was issued to another customer,
+// the admins are notified and the incident is recorded against the link owner
+func (h *JoinRequestHandler) checkLinkSharing(c tele.Context, request *tele.ChatJoinRequest, logFields []logger.Field) (bool, error) {
+	userId := strconv.FormatInt(request.Sender.ID, 10)
+	sharing, err := h.inviteLinks.DetectSharing(context.TODO(), request.InviteLink.InviteLink, userId)
+	if err != nil || sharing == nil {
+		// keep the request pending on error, it can still be handled manually
+		return false, err
+	}
+
+	logFields = append(logFields, logger.String("customer_id", sharing.Link.CustomerID))
+	h.log.Info("invite link sharing detected", logFields...)
+	if err := h.decline(c, request, i18n.MsgLinkSharingRequestDeclined, logFields); err != nil {
+		return false, err
+	}
+	if err := h.inviteLinks.RecordSharing(context.TODO(), sharing, request.Sender, true); err != nil {
+		h.log.Error("failed to record link sharing incident", append(logFields, logger.Error(err))...)
+	}
+	alertLinkSharing(h.cfg, h.sender, h.localizer, h.log, request.Chat, request.Sender, sharing, i18n.MsgLinkSharingDeclined)
+	return true, nil
+}
+
+// decline tells the user why and declines the request, the user chat id is only usable until the request
+// is processed so the notice goes first
+func (h *JoinRequestHandler) decline(c tele.Context, request *tele.ChatJoinRequest, key i18n.Key, logFields []logger.Field) error {
+	notice := h.localizer.T(i18n.FromContext(c), key, request.Chat.Title)
+	if _, err := h.sender.Send(context.TODO(), outbound.Interactive, tele.ChatID(request.UserChatID), notice); err != nil {
+		h.log.Error("failed to send join request declined message", append(logFields, logger.Error(err))...)
+	}
+	if err := h.bot.DeclineJoinRequest(request.Chat, request.Sender); err != nil {
+		return fmt.Errorf("failed to decline join request: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package group
+
+import (
+	"context"
+	tele "gopkg.in/telebot.v3"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/outbound"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// MemberHandler syncs the member status of the bound users from the chat_member updates of the managed groups
+// and removes the users joining through an invite link issued to someone else
+type MemberHandler struct {
+	bot                 *tele.Bot
+	sender              *outbound.Queue
+	log                 logger.Logger
+	localizer           *i18n.Localizer
+	cfg                 *config.TelegramConfig
+	memberStatusService *service.MemberStatusService
+	inviteLinks         *service.InviteLinkService
+	captcha             *CaptchaHandler
+}
+
+func NewMemberHandler(cfg *config.TelegramConfig, bot *tele.Bot, sender *outbound.Queue, log logger.Logger, localizer *i18n.Localizer,
+	memberStatusService *service.MemberStatusService, inviteLinks *service.InviteLinkService, captcha *CaptchaHandler) *MemberHandler {
+	return &MemberHandler{
+		bot:                 bot,
+		sender:              sender,
+		log:                 log,
+		localizer:           localizer,
+		cfg:                 cfg,
+		memberStatusService: memberStatusService,
+		inviteLinks:         inviteLinks,
+		captcha:             captcha,
+	}
+}
+
+func (h *MemberHandler) Handle(c tele.Context) error {
+	update := c.ChatMember()
+	if update == nil || update.Chat == nil || update.NewChatMember == nil || update.NewChatMember.User == nil {
+		return nil
+	}
+	if !h.isManagedGroup(update.Chat.ID) {
+		return nil
+	}
+
+	change := &service.MemberStatusChange{
+		Platform:   common.Telegram,
+		UserId:     strconv.FormatInt(update.NewChatMember.User.ID, 10),
+		GroupId:    update.Chat.ID,
+		GroupTitle: update.Chat.Title,
+		NewStatus:  common.GetMemberStatusFromValue(update.NewChatMember.Role),
+		ChangedAt:  update.Time(),
+	}
+	if update.OldChatMember != nil {
+		change.OldStatus = common.GetMemberStatusFromValue(update.OldChatMember.Role)
+	}
+	if update.InviteLink != nil {
+		change.InviteLink = update.InviteLink.InviteLink
+	}
+	if update.Unixtime == 0 {
+		change.ChangedAt = time.Now()
+	}
+
+	if err := h.memberStatusService.Sync(context.TODO(), change); err != nil {
+		return err
+	}
+
+	user := update.NewChatMember.User
+	switch {
+	case isJoin(change):
+		if change.InviteLink != "" {
+			removed, err := h.checkLinkSharing(c, update.Chat, user, change.InviteLink)
+			if err != nil || removed {
+				return err
+			}
+		}
+		return h.captcha.Challenge(c, update.Chat, user)
+	case change.NewStatus == common.Left || change.NewStatus == common.Kicked:
+		return h.captcha.Cancel(update.Chat, user)
+	}
+	return nil
+}
+
+// isJoin reports whether the user became a member, restricting or releasing an existing member is not a join
+func isJoin(change *service.MemberStatusChange) bool {
+	if change.NewStatus != common.Member {
+		return false
+	}
+	return change.OldStatus == "" || change.OldStatus == common.Left || change.OldStatus == common.Kicked
+}
+
+// checkLinkSharing removes the user when the invite link was issued to another customer,
+// the intruder and the admins are notified and the incident is recorded against the link owner
+func (h *MemberHandler) checkLinkSharing(c tele.Context, chat *tele.Chat, user *tele.User, inviteLink string) (bool, error) {
+	userId := strconv.FormatInt(user.ID, 10)
+	sharing, err := h.inviteLinks.DetectSharing(context.TODO(), inviteLink, userId)
+	if err != nil || sharing == nil {
+		return false, err
+	}
+
+	logFields := []logger.Field{
+		logger.Int64("chat_id", chat.ID),
+		logger.String("user_id", userId),
+		logger.String("customer_id", sharing.Link.CustomerID),
+		logger.String("invite_link", inviteLink),
+	}
+	h.log.Info("invite link sharing detected", logFields...)
+
+	removed := true
+	if err := h.remove(chat, user); err != nil {
+		removed = false
+		h.log.Error("failed to remove user joined with a shared invite link", append(logFields, logger.Error(err))...)
+	}
+	if err := h.inviteLinks.RecordSharing(context.TODO(), sharing, user, removed); err != nil {
+		h.log.Error("failed to record link sharing incident", append(logFields, logger.Error(err))...)
+	}
+
+	// the intruder may never have started the bot, the message is best effort
+	if removed {
+		notice := h.localizer.T(i18n.FromContext(c), i18n.MsgLinkSharingIntruder, chat.Title)
+		if _, err := h.sender.Send(context.TODO(), outbound.Interactive, user, notice); err != nil {
+			h.log.Info("failed to notify link sharing intruder", append(logFields, logger.Error(err))...)
+		}
+	}
+	result := i18n.MsgLinkSharingRemoved
+	if !removed {
+		result = i18n.MsgLinkSharingNotRemoved
+	}
+	alertLinkSharing(h.cfg, h.sender, h.localizer, h.log, chat, user, sharing, result)
+	return removed, nil
+}
+
+// remove kicks the user without leaving a ban, the user can join again with a link of their own
+func (h *MemberHandler) remove(chat *tele.Chat, user *tele.User) error {
+	if err := h.bot.Ban(chat, &tele.ChatMember{User: user}); err != nil {
+		return err
+	}
+	return h.bot.Unban(chat, user, true)
+}
+
+// alertLinkSharing notifies the admin chats of the link sharing, result tells how the intruder was handled
+func alertLinkSharing(cfg *config.TelegramConfig, sender *outbound.Queue, localizer *i18n.Localizer, log logger.Logger,
+	chat *tele.Chat, user *tele.User, sharing *service.LinkSharing, result i18n.Key) {
+	lang := localizer.Fallback()
+	alert := localizer.T(lang, i18n.MsgLinkSharingAlert,
+		chat.Title, sharing.Link.CustomerID, sharing.Owner.UserID,
+		strconv.FormatInt(user.ID, 10), user.Username, localizer.T(lang, result))
+
+	for _, chatId := range cfg.AdminChats() {
+		if _, err := sender.Send(context.TODO(), outbound.Interactive, &tele.Chat{ID: chatId}, alert); err != nil {
+			log.Error("failed to notify admins about link sharing",
+				logger.Int64("admin_chat_id", chatId),
+				logger.Error(err))
+		}
+	}
+}
+
+// HandleBotMember logs the status changes of the bot itself, chat_member updates are only
+// delivered while the bot is an administrator of the group
+func (h *MemberHandler) HandleBotMember(c tele.Context) error {
+	update := c.ChatMember()
+	if update == nil || update.Chat == nil || update.NewChatMember == nil {
+		return nil
+	}
+	h.log.Info("bot member status changed",
+		logger.Int64("chat_id", update.Chat.ID),
+		logger.String("chat_title", update.Chat.Title),
+		logger.String("status", string(update.NewChatMember.Role)),
+		logger.Any("managed", h.isManagedGroup(update.Chat.ID)))
+	return nil
+}
+
+func (h *MemberHandler) isManagedGroup(chatId int64) bool {
+	if slices.Contains(h.cfg.MonitoredGroups, chatId) {
+		return true
+	}
+	groupIds, err := h.cfg.GroupIds()
+	if err != nil {
+		h.log.Error("failed to parse group ids", logger.Error(err))
+		return false
+	}
+	return slices.Contains(groupIds, chatId)
+}
@@ -0,0 +1,32 @@
+package group
+
+import (
+	"github.com/stretchr/testify/assert"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service"
+	"testing"
+)
+
+func TestIsJoin(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldStatus common.MemberStatus
+		newStatus common.MemberStatus
+		expected  bool
+	}{
+		{name: "first seen member", newStatus: common.Member, expected: true},
+		{name: "member after leaving", oldStatus: common.Left, newStatus: common.Member, expected: true},
+		{name: "member after being kicked", oldStatus: common.Kicked, newStatus: common.Member, expected: true},
+		{name: "released from a restriction", oldStatus: common.Restricted, newStatus: common.Member},
+		{name: "demoted administrator", oldStatus: common.Administrator, newStatus: common.Member},
+		{name: "restricted", oldStatus: common.Member, newStatus: common.Restricted},
+		{name: "left", oldStatus: common.Member, newStatus: common.Left},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := &service.MemberStatusChange{OldStatus: tt.oldStatus, NewStatus: tt.newStatus}
+			assert.Equal(t, tt.expected, isJoin(change))
+		})
+	}
+}
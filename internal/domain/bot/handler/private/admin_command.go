@@ -0,0 +1,335 @@
+package private
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/command"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/customer"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/exception"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// inline buttons of the admin follow-up actions, the callback data is the uid
+var (
+	AdminLookupButton    = tele.Btn{Unique: "admin_lookup"}
+	AdminBlacklistButton = tele.Btn{Unique: "admin_blacklist"}
+	AdminWhitelistButton = tele.Btn{Unique: "admin_whitelist"}
+	AdminUnbanButton     = tele.Btn{Unique: "admin_unban"}
+	AdminKickButton      = tele.Btn{Unique: "admin_kick"}
+)
+
+var adminButtonLabels = map[string]i18n.Key{
+	AdminLookupButton.Unique:    i18n.MsgAdminButtonLookup,
+	AdminBlacklistButton.Unique: i18n.MsgAdminButtonBlacklist,
+	AdminWhitelistButton.Unique: i18n.MsgAdminButtonWhitelist,
+	AdminUnbanButton.Unique:     i18n.MsgAdminButtonUnban,
+	AdminKickButton.Unique:      i18n.MsgAdminButtonKick,
+}
+
+// AdminCommand owner commands, access is checked by middleware.Manager.AdminAuthorization
+type AdminCommand struct {
+	log logger.Logger
+	BaseCommand
+	bot             *tele.Bot
+	cfg             *config.TelegramConfig
+	customerService customer.CustomerServiceInterface
+}
+
+func NewAdminCommand(bot *tele.Bot, log logger.Logger, localizer *i18n.Localizer, cfg *config.TelegramConfig,
+	customerService customer.CustomerServiceInterface) *AdminCommand {
+	return &AdminCommand{
+		log: log,
+		BaseCommand: BaseCommand{
+			log:          log,
+			localizer:    localizer,
+			validator:    &CommandValidator{MinArgs: 2, MaxArgs: 2, ValidateArg: command.IsNumeric},
+			errorHandler: exception.NewErrorHandler(log),
+		},
+		bot:             bot,
+		cfg:             cfg,
+		customerService: customerService,
+	}
+}
+
+// Commands the admin commands, the access is checked by middleware.Manager.AdminAuthorization once registered
+func (a *AdminCommand) Commands() []*Command {
+	return []*Command{
+		// lookup checks its argument itself, a username is accepted as well as a uid
+		{Name: common.LookupCommandName, Args: "<uid|@username>", Description: i18n.MsgCommandLookup, Scope: ScopeAdmin, Handler: a.Lookup},
+		{Name: common.BlacklistCommandName, Args: "<uid>", Description: i18n.MsgCommandBlacklist, Scope: ScopeAdmin, Validator: a.validator, Handler: a.Blacklist},
+		{Name: common.WhitelistCommandName, Args: "<uid>", Description: i18n.MsgCommandWhitelist, Scope: ScopeAdmin, Validator: a.validator, Handler: a.Whitelist},
+		{Name: common.UnbanCommandName, Args: "<uid>", Description: i18n.MsgCommandUnban, Scope: ScopeAdmin, Validator: a.validator, Handler: a.Unban},
+		{Name: common.KickCommandName, Args: "<uid>", Description: i18n.MsgCommandKick, Scope: ScopeAdmin, Validator: a.validator, Handler: a.Kick},
+		{Name: common.StatsCommandName, Description: i18n.MsgCommandStats, Scope: ScopeAdmin, Handler: a.Stats},
+	}
+}
+
+// Lookup handles /lookup <uid|@username>
+func (a *AdminCommand) Lookup(c tele.Context) error {
+	args := strings.Fields(c.Text())
+	if len(args) != 2 || (!strings.HasPrefix(args[1], "@") && !command.IsNumeric(args[1])) {
+		return &exception.CommandError{
+			Key:  i18n.MsgAdminUsage,
+			Args: []interface{}{common.LookupCommandName, common.LookupCommandName},
+			Type: exception.ErrInvalidFormat,
+		}
+	}
+	return a.handleResponse(c, a.lookup(c, args[1]), args[1])
+}
+
+// Blacklist handles /blacklist <uid>
+func (a *AdminCommand) Blacklist(c tele.Context) error {
+	uid, err := a.validateUidInput(c, common.BlacklistCommandName)
+	if err != nil {
+		return err
+	}
+	return a.handleResponse(c, a.blacklist(c, uid), uid)
+}
+
+// Whitelist handles /whitelist <uid>
+func (a *AdminCommand) Whitelist(c tele.Context) error {
+	uid, err := a.validateUidInput(c, common.WhitelistCommandName)
+	if err != nil {
+		return err
+	}
+	return a.handleResponse(c, a.whitelist(c, uid), uid)
+}
+
+// Unban handles /unban <uid>
+func (a *AdminCommand) Unban(c tele.Context) error {
+	uid, err := a.validateUidInput(c, common.UnbanCommandName)
+	if err != nil {
+		return err
+	}
+	return a.handleResponse(c, a.unban(c, uid), uid)
+}
+
+// Kick handles /kick <uid>
+func (a *AdminCommand) Kick(c tele.Context) error {
+	uid, err := a.validateUidInput(c, common.KickCommandName)
+	if err != nil {
+		return err
+	}
+	return a.handleResponse(c, a.kick(c, uid), uid)
+}
+
+// Stats handles /stats
+func (a *AdminCommand) Stats(c tele.Context) error {
+	stats, err := a.customerService.GetCustomerStats(context.TODO())
+	if err != nil {
+		return a.handleResponse(c, err, common.StatsCommandName)
+	}
+	a.logResponse(nil, stats)
+	return c.Send(a.t(c, i18n.MsgAdminStats,
+		stats.Total, stats.Active, stats.Inactive, stats.Normal, stats.Whitelisted, stats.Blacklisted))
+}
+
+// HandleCallback dispatches the inline buttons attached to the admin replies
+func (a *AdminCommand) HandleCallback(c tele.Context) error {
+	callback := c.Callback()
+	uid := callback.Data
+	if err := c.Respond(); err != nil {
+		a.log.Error("failed to respond callback", logger.Error(err))
+	}
+	if !command.IsNumeric(uid) {
+		return &exception.CommandError{
+			Key:  i18n.MsgInvalidUIDFormat,
+			Args: []interface{}{common.LookupCommandName},
+			Type: exception.ErrInvalidFormat,
+		}
+	}
+
+	var err error
+	switch callback.Unique {
+	case AdminLookupButton.Unique:
+		err = a.lookup(c, uid)
+	case AdminBlacklistButton.Unique:
+		err = a.blacklist(c, uid)
+	case AdminWhitelistButton.Unique:
+		err = a.whitelist(c, uid)
+	case AdminUnbanButton.Unique:
+		err = a.unban(c, uid)
+	case AdminKickButton.Unique:
+		err = a.kick(c, uid)
+	default:
+		err = fmt.Errorf("unknown admin callback %s", callback.Unique)
+	}
+	return a.handleResponse(c, err, uid)
+}
+
+func (a *AdminCommand) lookup(c tele.Context, query string) error {
+	var info *model.CustomerInfoResponse
+	var err error
+	if username, ok := strings.CutPrefix(query, "@"); ok {
+		info, err = a.customerService.GetCustomerInfoBySocialUsername(context.TODO(), username)
+	} else {
+		info, err = a.customerService.GetCustomerInfoByUid(context.TODO(), query)
+	}
+	if err != nil {
+		return err
+	}
+	return c.Send(a.formatCustomerInfo(c, info), a.actionMarkup(c, info.TradingAccountInfo.UID,
+		AdminBlacklistButton, AdminWhitelistButton, AdminUnbanButton, AdminKickButton))
+}
+
+func (a *AdminCommand) blacklist(c tele.Context, uid string) error {
+	info, err := a.customerService.UpdateCustomerStatusByUid(context.TODO(), uid, common.Blacklisted, common.Kicked)
+	if err != nil {
+		return err
+	}
+	if err := a.applyInGroups(info, a.ban); err != nil {
+		return err
+	}
+	return a.sendStatusUpdated(c, uid, common.Blacklisted, AdminUnbanButton, AdminLookupButton)
+}
+
+func (a *AdminCommand) whitelist(c tele.Context, uid string) error {
+	if _, err := a.customerService.UpdateCustomerStatusByUid(context.TODO(), uid, common.Whitelisted, ""); err != nil {
+		return err
+	}
+	return a.sendStatusUpdated(c, uid, common.Whitelisted, AdminBlacklistButton, AdminLookupButton)
+}
+
+// unban lifts the telegram ban in every group and clears the blacklist
+func (a *AdminCommand) unban(c tele.Context, uid string) error {
+	info, err := a.customerService.GetCustomerInfoByUid(context.TODO(), uid)
+	if err != nil {
+		return err
+	}
+	if err := a.applyInGroups(info, a.unbanUser); err != nil {
+		return err
+	}
+
+	var status common.Status
+	if info.SocialAccountInfo.Status == common.Blacklisted {
+		status = common.Normal
+	}
+	var memberStatus common.MemberStatus
+	if info.SocialAccountInfo.MemberStatus == string(common.Kicked) {
+		memberStatus = common.Left
+	}
+	if _, err := a.customerService.UpdateCustomerStatusByUid(context.TODO(), uid, status, memberStatus); err != nil {
+		return err
+	}
+	return c.Send(a.t(c, i18n.MsgAdminUnbanned, uid), a.actionMarkup(c, uid, AdminLookupButton))
+}
+
+// kick removes the user from every group without a permanent ban, the user is able to rejoin
+func (a *AdminCommand) kick(c tele.Context, uid string) error {
+	info, err := a.customerService.GetCustomerInfoByUid(context.TODO(), uid)
+	if err != nil {
+		return err
+	}
+	err = a.applyInGroups(info, func(chat *tele.Chat, user *tele.User) error {
+		if err := a.ban(chat, user); err != nil {
+			return err
+		}
+		return a.unbanUser(chat, user)
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := a.customerService.UpdateCustomerStatusByUid(context.TODO(), uid, "", common.Left); err != nil {
+		return err
+	}
+	return c.Send(a.t(c, i18n.MsgAdminKicked, uid), a.actionMarkup(c, uid, AdminBlacklistButton, AdminLookupButton))
+}
+
+func (a *AdminCommand) ban(chat *tele.Chat, user *tele.User) error {
+	return a.bot.Ban(chat, &tele.ChatMember{User: user})
+}
+
+func (a *AdminCommand) unbanUser(chat *tele.Chat, user *tele.User) error {
+	// only_if_banned, users still in the group are left untouched
+	return a.bot.Unban(chat, user, true)
+}
+
+// applyInGroups runs action for the bound telegram user in every configured group
+func (a *AdminCommand) applyInGroups(info *model.CustomerInfoResponse, action func(chat *tele.Chat, user *tele.User) error) error {
+	userId, err := strconv.ParseInt(info.SocialAccountInfo.UserID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid telegram user id %q: %w", info.SocialAccountInfo.UserID, err)
+	}
+	groupIds, err := a.cfg.GroupIds()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, groupId := range groupIds {
+		if err := action(&tele.Chat{ID: groupId}, &tele.User{ID: userId}); err != nil {
+			a.log.Error("failed to apply admin action in group",
+				logger.Int64("group_id", groupId),
+				logger.Int64("user_id", userId),
+				logger.Error(err))
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *AdminCommand) sendStatusUpdated(c tele.Context, uid string, status common.Status, buttons ...tele.Btn) error {
+	return c.Send(a.t(c, i18n.MsgAdminStatusUpdated, uid, a.t(c, i18n.StatusKey(status))), a.actionMarkup(c, uid, buttons...))
+}
+
+func (a *AdminCommand) formatCustomerInfo(c tele.Context, info *model.CustomerInfoResponse) string {
+	active := i18n.MsgAdminInactive
+	if info.SocialAccountInfo.IsActive {
+		active = i18n.MsgAdminActive
+	}
+	return a.t(c, i18n.MsgAdminLookupResult,
+		info.TradingAccountInfo.UID,
+		info.Customer.ID,
+		info.SocialAccountInfo.UserID,
+		info.SocialAccountInfo.Username,
+		info.SocialAccountInfo.Firstname,
+		info.SocialAccountInfo.Lastname,
+		a.t(c, active),
+		a.t(c, i18n.StatusKey(common.Status(info.SocialAccountInfo.Status))),
+		a.t(c, i18n.MemberStatusKey(common.GetMemberStatusFromString(info.SocialAccountInfo.MemberStatus))),
+		info.SocialAccountInfo.CreatedAt.Format(time.DateTime),
+	)
+}
+
+func (a *AdminCommand) actionMarkup(c tele.Context, uid string, buttons ...tele.Btn) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+	btns := make([]tele.Btn, 0, len(buttons))
+	for _, btn := range buttons {
+		btns = append(btns, markup.Data(a.t(c, adminButtonLabels[btn.Unique]), btn.Unique, uid))
+	}
+	markup.Inline(markup.Split(2, btns)...)
+	return markup
+}
+
+func (a *AdminCommand) handleResponse(c tele.Context, err error, args ...interface{}) error {
+	a.logResponse(err, args)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, repository.ErrRecordNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return &exception.CommandError{
+			Key:  i18n.MsgAdminNotFound,
+			Args: []interface{}{args[0]},
+			Type: exception.ErrInvalidFormat,
+		}
+	}
+	a.log.Error("admin command failed",
+		logger.Any("args", args),
+		logger.Error(err))
+	return &exception.CommandError{
+		Key:  i18n.MsgAdminActionFailed,
+		Args: []interface{}{args[0]},
+		Type: exception.ErrInternal,
+	}
+}
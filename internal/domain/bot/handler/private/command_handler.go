@@ -2,10 +2,9 @@ package private
 
 import (
 	tele "gopkg.in/telebot.v3"
-	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
 	"ohmycontrolcenter.tech/omcc/pkg/exception"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
-	"strconv"
 	"sync"
 )
 
@@ -17,17 +16,27 @@ type CommandHandler interface {
 type TelegramCommandHandler interface {
 	CommandHandler
 	validateUidInput(c tele.Context, command string) (string, error)
-	buildUserInfoContext(c tele.Context, uid string) *common.UserInfo
-	sendProcessingMessage(c tele.Context, text string) error
 	handleResponse(c tele.Context, err error, args ...interface{}) error
 }
 
 type BaseCommand struct {
 	log          logger.Logger
+	localizer    *i18n.Localizer
 	validator    *CommandValidator
 	errorHandler *exception.ErrorHandler
 }
 
+// t translate key into the language of the sender
+func (b *BaseCommand) t(c tele.Context, key i18n.Key, args ...interface{}) string {
+	return b.localizer.T(i18n.FromContext(c), key, args...)
+}
+
+// send render an editable message in the language of the sender and send it with its parse mode
+func (b *BaseCommand) send(c tele.Context, key i18n.Key, data i18n.TemplateData, args ...interface{}) error {
+	rendered := b.localizer.Render(i18n.FromContext(c), key, data, args...)
+	return c.Send(rendered.Text, rendered.SendOptions())
+}
+
 func (b *BaseCommand) logResponse(err error, args ...interface{}) {
 	if err != nil {
 		b.log.Info("Telegram command execution failed with error",
@@ -42,35 +51,7 @@ func (b *BaseCommand) logResponse(err error, args ...interface{}) {
 }
 
 func (b *BaseCommand) validateUidInput(c tele.Context, command string) (string, error) {
-	return b.validator.validateUidInput(c, command)
-}
-
-func (b *BaseCommand) buildUserInfoContext(c tele.Context, uid string, memberStatus common.MemberStatus) *common.UserInfo {
-	return &common.UserInfo{
-		UID:            uid,
-		UserId:         strconv.FormatInt(c.Chat().ID, 10),
-		Firstname:      c.Chat().FirstName,
-		Lastname:       c.Chat().LastName,
-		Username:       c.Chat().Username,
-		MemberStatus:   memberStatus,
-		SocialPlatform: common.Telegram,
-	}
-}
-
-func (b *BaseCommand) sendProcessingMessage(c tele.Context, text string) error {
-	if err := c.Send(text); err != nil {
-		return &exception.CommandError{
-			Message: common.ServerErrorMessage,
-			Type:    exception.ErrServiceUnavailable,
-		}
-	}
-	return nil
-}
-
-func (b *BaseCommand) generateInviteLinks(c tele.Context) error {
-	// TODO currently add in only verify_command.go, because of the requirements
-	// TODO add if needed
-	return nil
+	return validateUidInput(b.validator, c, command)
 }
 
 func (b *BaseCommand) sendMultipleMessage(c tele.Context, messages []string) error {
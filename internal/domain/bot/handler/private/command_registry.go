@@ -0,0 +1,218 @@
+package private
+
+import (
+	"errors"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strings"
+)
+
+// CommandScope where a command is offered, a command may be offered in several scopes
+type CommandScope int
+
+const (
+	// ScopePrivate private chats with the bot
+	ScopePrivate CommandScope = 1 << iota
+	// ScopeGroup the managed groups
+	ScopeGroup
+	// ScopeAdmin private chats of the configured admin ids
+	ScopeAdmin
+)
+
+// Command self description of a command, used to route it, to list it in /help and in the telegram command menu
+type Command struct {
+	Name        string
+	Args        string
+	Description i18n.Key
+	Scope       CommandScope
+	// Validator checks the arguments before Handler runs, nil when the handler checks them itself
+	Validator *CommandValidator
+	Handler   tele.HandlerFunc
+}
+
+// Endpoint returns the handler of the command running its validator first
+func (c *Command) Endpoint() tele.HandlerFunc {
+	if c.Validator == nil {
+		return c.Handler
+	}
+	return func(ctx tele.Context) error {
+		if _, err := c.Validator.ValidateGeneralCommand(ctx.Text(), c.Name); err != nil {
+			return err
+		}
+		return c.Handler(ctx)
+	}
+}
+
+// Usage returns the command with its arguments, e.g. /verify <uid>
+func (c *Command) Usage() string {
+	if c.Args == "" {
+		return c.Name
+	}
+	return c.Name + " " + c.Args
+}
+
+// CommandProvider a handler declaring the commands it handles
+type CommandProvider interface {
+	Commands() []*Command
+}
+
+// CommandRegistry the commands of the bot in registration order
+type CommandRegistry struct {
+	log       logger.Logger
+	localizer *i18n.Localizer
+	admins    map[int64]struct{}
+	commands  []*Command
+	names     map[string]struct{}
+}
+
+func NewCommandRegistry(log logger.Logger, localizer *i18n.Localizer, adminIds []int64) *CommandRegistry {
+	admins := make(map[int64]struct{}, len(adminIds))
+	for _, id := range adminIds {
+		admins[id] = struct{}{}
+	}
+	return &CommandRegistry{
+		log:       log,
+		localizer: localizer,
+		admins:    admins,
+		names:     make(map[string]struct{}),
+	}
+}
+
+// Register adds the commands of the providers, a name already registered is skipped
+func (r *CommandRegistry) Register(providers ...CommandProvider) {
+	for _, provider := range providers {
+		for _, cmd := range provider.Commands() {
+			if _, ok := r.names[cmd.Name]; ok {
+				r.log.Error("command registered twice", logger.String("command", cmd.Name))
+				continue
+			}
+			r.names[cmd.Name] = struct{}{}
+			r.commands = append(r.commands, cmd)
+		}
+	}
+}
+
+// Commands returns all registered commands
+func (r *CommandRegistry) Commands() []*Command {
+	return r.commands
+}
+
+// Visible returns the commands offered in any of the scopes
+func (r *CommandRegistry) Visible(scope CommandScope) []*Command {
+	var visible []*Command
+	for _, cmd := range r.commands {
+		if cmd.Scope&scope != 0 {
+			visible = append(visible, cmd)
+		}
+	}
+	return visible
+}
+
+// IsAdmin reports whether the user is one of the configured admins
+func (r *CommandRegistry) IsAdmin(user *tele.User) bool {
+	if user == nil {
+		return false
+	}
+	_, ok := r.admins[user.ID]
+	return ok
+}
+
+// ScopeOf returns the scopes of the commands offered to the sender of the update
+func (r *CommandRegistry) ScopeOf(c tele.Context) CommandScope {
+	if c.Chat() != nil && c.Chat().Type != tele.ChatPrivate {
+		return ScopeGroup
+	}
+	if r.IsAdmin(c.Sender()) {
+		return ScopePrivate | ScopeAdmin
+	}
+	return ScopePrivate
+}
+
+// Help returns the usage and the description of the commands offered in scope, one command per line
+func (r *CommandRegistry) Help(lang i18n.Lang, scope CommandScope) string {
+	commands := r.Visible(scope)
+	width := 0
+	for _, cmd := range commands {
+		width = max(width, len([]rune(cmd.Usage())))
+	}
+	lines := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		usage := cmd.Usage()
+		padding := strings.Repeat(" ", width-len([]rune(usage)))
+		lines = append(lines, fmt.Sprintf("%s%s - %s", usage, padding, r.localizer.T(lang, cmd.Description)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// BotCommands returns the telegram command menu of scope in lang
+func (r *CommandRegistry) BotCommands(lang i18n.Lang, scope CommandScope) []tele.Command {
+	commands := r.Visible(scope)
+	menu := make([]tele.Command, 0, len(commands))
+	for _, cmd := range commands {
+		menu = append(menu, tele.Command{
+			Text:        strings.TrimPrefix(cmd.Name, "/"),
+			Description: r.localizer.T(lang, cmd.Description),
+		})
+	}
+	return menu
+}
+
+// commandMenu the commands of scope pushed to the telegram scope
+type commandMenu struct {
+	scope    CommandScope
+	telegram tele.CommandScope
+}
+
+// menuLanguage the language of the menu shown to the telegram users with code, an empty code is the default menu
+type menuLanguage struct {
+	code string
+	lang i18n.Lang
+}
+
+// SyncCommands pushes the command menu of every scope and language to telegram, the admins get their own menu
+// in their private chat with the bot, a failed menu does not stop the others
+func (r *CommandRegistry) SyncCommands(bot *tele.Bot) error {
+	menus := []commandMenu{
+		{ScopePrivate, tele.CommandScope{Type: tele.CommandScopeAllPrivateChats}},
+		{ScopeGroup, tele.CommandScope{Type: tele.CommandScopeAllGroupChats}},
+	}
+	for id := range r.admins {
+		menus = append(menus, commandMenu{ScopePrivate | ScopeAdmin, tele.CommandScope{Type: tele.CommandScopeChat, ChatID: id}})
+	}
+
+	var errs []error
+	for _, menu := range menus {
+		for _, language := range r.menuLanguages() {
+			if err := r.setMenu(bot, menu, language); err != nil {
+				errs = append(errs, fmt.Errorf("failed to set the %s commands in %q: %w", menu.telegram.Type, language.code, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *CommandRegistry) setMenu(bot *tele.Bot, menu commandMenu, language menuLanguage) error {
+	commands := r.BotCommands(language.lang, menu.scope)
+	if len(commands) == 0 {
+		return bot.DeleteCommands(menu.telegram, language.code)
+	}
+	return bot.SetCommands(commands, menu.telegram, language.code)
+}
+
+// menuLanguages telegram tells the menu languages apart by their ISO 639-1 code only, zh-TW and zh-CN share zh
+// which gets the first of them in the supported order
+func (r *CommandRegistry) menuLanguages() []menuLanguage {
+	languages := []menuLanguage{{code: "", lang: r.localizer.Fallback()}}
+	seen := make(map[string]bool)
+	for _, lang := range i18n.Supported() {
+		code, _, _ := strings.Cut(string(lang), "-")
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		languages = append(languages, menuLanguage{code: code, lang: lang})
+	}
+	return languages
+}
@@ -0,0 +1,73 @@
+package private
+
+import (
+	"github.com/stretchr/testify/assert"
+	tele "gopkg.in/telebot.v3"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/command"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"ohmycontrolcenter.tech/omcc/util"
+	"testing"
+)
+
+type testProvider []*Command
+
+func (p testProvider) Commands() []*Command {
+	return p
+}
+
+func newTestRegistry() *CommandRegistry {
+	registry := NewCommandRegistry(logger.NewLogger(), i18n.NewLocalizer(i18n.En), []int64{42})
+	registry.Register(testProvider{
+		{Name: common.StartCommandName, Description: i18n.MsgCommandStart, Scope: ScopePrivate},
+		{Name: common.VerifyCommandName, Args: "<uid>", Description: i18n.MsgCommandVerify, Scope: ScopePrivate},
+		{Name: common.StatsCommandName, Description: i18n.MsgCommandStats, Scope: ScopeAdmin},
+	}, testProvider{
+		{Name: common.StartCommandName, Description: i18n.MsgCommandHelp, Scope: ScopeGroup},
+	})
+	return registry
+}
+
+func TestCommandRegistry_Help(t *testing.T) {
+	registry := newTestRegistry()
+
+	assert.Len(t, registry.Commands(), 3)
+	assert.Equal(t, "/start        - Start using the bot\n/verify <uid> - Verify your numeric UID",
+		registry.Help(i18n.En, ScopePrivate))
+	assert.Equal(t, "/start        - Start using the bot\n/verify <uid> - Verify your numeric UID\n/stats        - Member statistics",
+		registry.Help(i18n.En, ScopePrivate|ScopeAdmin))
+	assert.Empty(t, registry.Help(i18n.En, ScopeGroup))
+
+	rendered := registry.localizer.Render(i18n.En, i18n.MsgHelp, nil, registry.Help(i18n.En, ScopePrivate|ScopeAdmin))
+	assert.NoError(t, util.ValidateMarkdownV2(rendered.Text))
+}
+
+func TestCommandRegistry_BotCommands(t *testing.T) {
+	registry := newTestRegistry()
+
+	assert.Equal(t, []tele.Command{
+		{Text: "start", Description: "开始使用机器人"},
+		{Text: "verify", Description: "验证您的数字UID"},
+	}, registry.BotCommands(i18n.ZhCN, ScopePrivate))
+	assert.Equal(t, []menuLanguage{{"", i18n.En}, {"zh", i18n.ZhTW}, {"en", i18n.En}}, registry.menuLanguages())
+}
+
+func TestCommand_Endpoint(t *testing.T) {
+	called := false
+	cmd := &Command{
+		Name:      common.VerifyCommandName,
+		Validator: &CommandValidator{MinArgs: 2, MaxArgs: 2, ValidateArg: command.IsNumeric},
+		Handler: func(c tele.Context) error {
+			called = true
+			return nil
+		},
+	}
+
+	b, err := tele.NewBot(tele.Settings{Offline: true})
+	assert.NoError(t, err)
+	assert.Error(t, cmd.Endpoint()(b.NewContext(tele.Update{Message: &tele.Message{Text: "/verify abc"}})))
+	assert.False(t, called)
+	assert.NoError(t, cmd.Endpoint()(b.NewContext(tele.Update{Message: &tele.Message{Text: "/verify 123"}})))
+	assert.True(t, called)
+}
@@ -0,0 +1,92 @@
+package private
+
+import (
+	"context"
+	tele "gopkg.in/telebot.v3"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/command"
+	"ohmycontrolcenter.tech/omcc/pkg/exception"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+)
+
+// CoreCommand runs the platform-neutral commands in the private chats, the errors are rendered by the middleware
+type CoreCommand struct {
+	BaseCommand
+	dispatcher *command.Dispatcher
+}
+
+func NewCoreCommand(log logger.Logger, localizer *i18n.Localizer, dispatcher *command.Dispatcher) *CoreCommand {
+	return &CoreCommand{
+		BaseCommand: BaseCommand{
+			log:          log,
+			localizer:    localizer,
+			errorHandler: exception.NewErrorHandler(log),
+		},
+		dispatcher: dispatcher,
+	}
+}
+
+// Commands the commands of the dispatcher, validated by the dispatcher itself
+func (h *CoreCommand) Commands() []*Command {
+	commands := make([]*Command, 0, len(h.dispatcher.Commands()))
+	for _, cmd := range h.dispatcher.Commands() {
+		if !cmd.OfferedOn(common.Telegram) {
+			continue
+		}
+		commands = append(commands, &Command{
+			Name:        cmd.Name,
+			Args:        cmd.Args,
+			Description: cmd.Description,
+			Scope:       ScopePrivate,
+			Handler:     h.handler(cmd.Name),
+		})
+	}
+	return commands
+}
+
+func (h *CoreCommand) handler(name string) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		reply, err := h.dispatcher.Execute(context.TODO(), h.request(c, name))
+		if err != nil {
+			return err
+		}
+		return h.sendReply(c, reply)
+	}
+}
+
+// request the command sent in the private chat, the progress messages are sent right away
+func (h *CoreCommand) request(c tele.Context, name string) *command.Request {
+	_, args := command.ParseText(c.Text())
+	sender := c.Sender()
+	return &command.Request{
+		Platform: common.Telegram,
+		User: command.User{
+			Id:        strconv.FormatInt(sender.ID, 10),
+			Username:  sender.Username,
+			Firstname: sender.FirstName,
+			Lastname:  sender.LastName,
+		},
+		Lang: i18n.FromContext(c),
+		Name: name,
+		Args: args,
+		Progress: func(ctx context.Context, msg command.Message) error {
+			return h.sendMessage(c, msg)
+		},
+	}
+}
+
+// sendReply sends the messages in order, then the links concurrently
+func (h *CoreCommand) sendReply(c tele.Context, reply *command.Reply) error {
+	for _, msg := range reply.Messages {
+		if err := h.sendMessage(c, msg); err != nil {
+			return err
+		}
+	}
+	return h.sendMultipleMessage(c, reply.Links)
+}
+
+func (h *CoreCommand) sendMessage(c tele.Context, msg command.Message) error {
+	return c.Send(msg.Text, &tele.SendOptions{ParseMode: msg.ParseMode})
+}
@@ -3,19 +3,36 @@ package private
 import (
 	tele "gopkg.in/telebot.v3"
 	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
 )
 
 type HelpCommand struct {
-	log logger.Logger
+	log       logger.Logger
+	localizer *i18n.Localizer
+	registry  *CommandRegistry
 }
 
-func NewHelpCommand(log logger.Logger) HelpCommand {
-	return HelpCommand{log: log}
+func NewHelpCommand(log logger.Logger, localizer *i18n.Localizer, registry *CommandRegistry) HelpCommand {
+	return HelpCommand{log: log, localizer: localizer, registry: registry}
 }
 
+func (h *HelpCommand) Commands() []*Command {
+	return []*Command{{
+		Name:        common.HelpCommandName,
+		Description: i18n.MsgCommandHelp,
+		Scope:       ScopePrivate,
+		Handler:     h.Handle,
+	}}
+}
+
+// Handle lists the commands offered to the sender, the admins also get the admin commands
 func (h *HelpCommand) Handle(c tele.Context) error {
-	return c.Send(common.HelpMessage, &tele.SendOptions{
-		ParseMode: tele.ModeMarkdownV2,
-	})
+	lang := i18n.FromContext(c)
+	commands := h.registry.Help(lang, h.registry.ScopeOf(c))
+	rendered := h.localizer.Render(lang, i18n.MsgHelp, i18n.TemplateData{
+		"Username": c.Sender().Username,
+		"Commands": commands,
+	}, commands)
+	return c.Send(rendered.Text, rendered.SendOptions())
 }
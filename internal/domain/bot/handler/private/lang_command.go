@@ -0,0 +1,88 @@
+package private
+
+import (
+	"context"
+	tele "gopkg.in/telebot.v3"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service"
+	"ohmycontrolcenter.tech/omcc/pkg/exception"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+	"strings"
+)
+
+type LangCommand struct {
+	log logger.Logger
+	BaseCommand
+	languageService *service.LanguageService
+}
+
+func NewLangCommand(log logger.Logger, localizer *i18n.Localizer, languageService *service.LanguageService) *LangCommand {
+	return &LangCommand{
+		log: log,
+		BaseCommand: BaseCommand{
+			log:          log,
+			localizer:    localizer,
+			validator:    &CommandValidator{MinArgs: 1, MaxArgs: 2},
+			errorHandler: exception.NewErrorHandler(log),
+		},
+		languageService: languageService,
+	}
+}
+
+func (l *LangCommand) Commands() []*Command {
+	return []*Command{{
+		Name:        common.LangCommandName,
+		Args:        "[zh-TW|zh-CN|en]",
+		Description: i18n.MsgCommandLang,
+		Scope:       ScopePrivate,
+		Validator:   l.validator,
+		Handler:     l.Handle,
+	}}
+}
+
+func (l *LangCommand) Handle(c tele.Context) error {
+	args, err := l.validator.ValidateGeneralCommand(c.Text(), common.LangCommandName)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		current := i18n.FromContext(c)
+		return c.Send(l.t(c, i18n.MsgLangCurrent, l.localizer.T(current, i18n.MsgLangName), supportedLanguages()))
+	}
+
+	lang, ok := i18n.ParseLang(args[0])
+	if !ok {
+		return &exception.CommandError{
+			Key:  i18n.MsgLangUnsupported,
+			Args: []interface{}{args[0], supportedLanguages()},
+			Type: exception.ErrInvalidFormat,
+		}
+	}
+
+	userId := strconv.FormatInt(c.Sender().ID, 10)
+	err = l.languageService.Update(context.TODO(), common.Telegram, userId, lang)
+	return l.handleResponse(c, err, userId, lang)
+}
+
+func (l *LangCommand) handleResponse(c tele.Context, err error, args ...interface{}) error {
+	l.logResponse(err, args)
+	userId := args[0].(string)
+	lang := args[1].(i18n.Lang)
+	if err != nil {
+		return l.errorHandler.HandleServiceError(err, map[string]interface{}{
+			"user_id": userId,
+		})
+	}
+	// reply in the newly chosen language
+	return c.Send(l.localizer.T(lang, i18n.MsgLangUpdated, l.localizer.T(lang, i18n.MsgLangName)))
+}
+
+func supportedLanguages() string {
+	var langs []string
+	for _, lang := range i18n.Supported() {
+		langs = append(langs, string(lang))
+	}
+	return strings.Join(langs, ", ")
+}
@@ -2,17 +2,22 @@ package private
 
 import (
 	tele "gopkg.in/telebot.v3"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
 )
 
 type OnTextCommand struct {
-	log logger.Logger
+	log       logger.Logger
+	localizer *i18n.Localizer
 }
 
-func NewOnTextCommand(log logger.Logger) OnTextCommand {
-	return OnTextCommand{log: log}
+func NewOnTextCommand(log logger.Logger, localizer *i18n.Localizer) OnTextCommand {
+	return OnTextCommand{log: log, localizer: localizer}
 }
 
 func (h *OnTextCommand) Handle(c tele.Context) error {
-	return c.Send("我不是聊天機器人 有活人不聊 你找我幹啥 我能跳舞嗎 輸入指令我才幹活 不然我會罵街的")
+	rendered := h.localizer.Render(i18n.FromContext(c), i18n.MsgOnText, i18n.TemplateData{
+		"Username": c.Sender().Username,
+	})
+	return c.Send(rendered.Text, rendered.SendOptions())
 }
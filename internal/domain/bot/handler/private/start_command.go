@@ -3,17 +3,31 @@ package private
 import (
 	tele "gopkg.in/telebot.v3"
 	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
 )
 
 type StartCommand struct {
-	log logger.Logger
+	log       logger.Logger
+	localizer *i18n.Localizer
 }
 
-func NewStartCommand(log logger.Logger) StartCommand {
-	return StartCommand{log: log}
+func NewStartCommand(log logger.Logger, localizer *i18n.Localizer) StartCommand {
+	return StartCommand{log: log, localizer: localizer}
+}
+
+func (h *StartCommand) Commands() []*Command {
+	return []*Command{{
+		Name:        common.StartCommandName,
+		Description: i18n.MsgCommandStart,
+		Scope:       ScopePrivate,
+		Handler:     h.Handle,
+	}}
 }
 
 func (h *StartCommand) Handle(c tele.Context) error {
-	return c.Send(common.WelcomeMessage)
+	rendered := h.localizer.Render(i18n.FromContext(c), i18n.MsgWelcome, i18n.TemplateData{
+		"Username": c.Sender().Username,
+	})
+	return c.Send(rendered.Text, rendered.SendOptions())
 }
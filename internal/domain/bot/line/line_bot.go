@@ -0,0 +1,139 @@
+package line
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/command"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/pkg/client"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"sync"
+	"time"
+)
+
+const (
+	maxWebhookBody = 1 << 20
+	// eventTimeout bounds the handling of an event, the reply token expires soon after the event anyway
+	eventTimeout = time.Minute
+)
+
+type LanguageResolver interface {
+	Resolve(ctx context.Context, platform common.SocialPlatformType, userId string, languageCode string) i18n.Lang
+}
+
+// LineBot runs the verify, volume, status and account commands sent to the LINE channel, the webhook answers at once
+// and the commands are answered with the reply api, or pushed once the reply token expired
+type LineBot struct {
+	cfg        *config.LineConfig
+	log        logger.Logger
+	client     *client.LineClient
+	dispatcher *command.Dispatcher
+	languages  LanguageResolver
+	// events the events being handled, waited for by Stop
+	events sync.WaitGroup
+}
+
+func NewLineBot(cfg *config.LineConfig, log logger.Logger, dispatcher *command.Dispatcher, languages LanguageResolver) *LineBot {
+	return &LineBot{
+		cfg:        cfg,
+		log:        log,
+		client:     client.NewLineClient(cfg, log),
+		dispatcher: dispatcher,
+		languages:  languages,
+	}
+}
+
+// Stop waits for the events being handled
+func (b *LineBot) Stop() {
+	b.events.Wait()
+}
+
+// ServeHTTP receives the webhook events, the requests without the signature of the channel secret are rejected
+func (b *LineBot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if !b.client.ValidateSignature(body, r.Header.Get(client.LineSignatureHeader)) {
+		b.log.Warn("rejected line webhook request with invalid signature",
+			logger.String("ip", r.RemoteAddr))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var webhook client.LineWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		b.log.Error("failed to decode line webhook", logger.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	for _, event := range webhook.Events {
+		b.events.Add(1)
+		go func(event client.LineEvent) {
+			defer b.events.Done()
+			b.handleEvent(event)
+		}(event)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleEvent answers the texts sent in the chat with the bot, the other events are ignored
+func (b *LineBot) handleEvent(event client.LineEvent) {
+	if event.Type != "message" || event.Message == nil || event.Message.Type != "text" || event.Source.Type != "user" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), eventTimeout)
+	defer cancel()
+
+	userId := event.Source.UserId
+	profile, err := b.client.GetProfile(userId)
+	if err != nil {
+		b.log.Warn("failed to get line profile",
+			logger.String("user_id", userId),
+			logger.Error(err))
+		profile = &client.LineProfile{UserId: userId}
+	}
+	lang := b.languages.Resolve(ctx, common.Line, userId, profile.Language)
+
+	b.log.Info("Received LINE message",
+		logger.String("user_id", userId),
+		logger.String("text", event.Message.Text))
+
+	name, args := command.ParseText(event.Message.Text)
+	reply := b.dispatcher.Dispatch(ctx, &command.Request{
+		Platform: common.Line,
+		User:     command.User{Id: userId, Username: profile.DisplayName},
+		Lang:     lang,
+		Name:     name,
+		Args:     args,
+	})
+	b.send(event.ReplyToken, userId, reply.Texts())
+}
+
+// send replies the first messages and pushes the others, the reply is pushed when the reply token expired
+func (b *LineBot) send(replyToken string, userId string, messages []string) {
+	for i := 0; i < len(messages); i += client.LineMaxMessages {
+		batch := messages[i:min(i+client.LineMaxMessages, len(messages))]
+		if i == 0 {
+			err := b.client.Reply(replyToken, batch...)
+			if err == nil {
+				continue
+			}
+			b.log.Warn("failed to reply line message, pushing it",
+				logger.String("user_id", userId),
+				logger.Error(err))
+		}
+		if err := b.client.Push(userId, batch...); err != nil {
+			b.log.Error("failed to push line message",
+				logger.String("user_id", userId),
+				logger.Error(err))
+			return
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package line
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/command"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/client"
+	"ohmycontrolcenter.tech/omcc/pkg/client/linetest"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"testing"
+)
+
+const testSecret = "channel-secret"
+
+type fakeServices struct {
+	verified  *common.UserInfo
+	verifyErr error
+	volume    *big.Float
+	status    common.MemberStatus
+	updated   *common.UserInfo
+}
+
+func (f *fakeServices) HandleVerification(_ context.Context, _ string, userInfo *common.UserInfo) error {
+	f.verified = userInfo
+	return f.verifyErr
+}
+
+func (f *fakeServices) HandleVolumeCheck(context.Context, string) (*big.Float, error) {
+	return f.volume, nil
+}
+
+func (f *fakeServices) Report(_ context.Context, uid string, _ common.SocialPlatformType, _ string) (*service.StatusReport, error) {
+	return &service.StatusReport{UID: uid, MemberStatus: f.status}, nil
+}
+
+func (f *fakeServices) HandleUpdateCommandService(_ context.Context, _ string, userInfo *common.UserInfo) error {
+	f.updated = userInfo
+	return nil
+}
+
+func (f *fakeServices) Resolve(context.Context, common.SocialPlatformType, string, string) i18n.Lang {
+	return i18n.En
+}
+
+func newTestLineBot(t *testing.T) (*LineBot, *linetest.Server, *fakeServices) {
+	server := linetest.NewServer()
+	t.Cleanup(server.Close)
+	server.SetProfile(client.LineProfile{UserId: "U1", DisplayName: "alice", Language: "en"})
+
+	services := &fakeServices{volume: big.NewFloat(1234.5), status: common.Left}
+	cfg := &config.LineConfig{
+		Enabled:            true,
+		ChannelSecret:      testSecret,
+		ChannelAccessToken: "token",
+		BaseUrl:            server.URL,
+		WebhookPath:        "/line/webhook",
+	}
+	log := logger.NewLogger()
+	dispatcher := command.NewDispatcher(log, i18n.NewLocalizer(i18n.En), command.Services{
+		Verify:  services,
+		Volume:  services,
+		Status:  services,
+		Account: services,
+	})
+	b := NewLineBot(cfg, log, dispatcher, services)
+	return b, server, services
+}
+
+// deliver sends the events to the webhook and waits for the answers
+func deliver(t *testing.T, b *LineBot, events ...client.LineEvent) {
+	w := httptest.NewRecorder()
+	b.ServeHTTP(w, linetest.NewWebhookRequest(testSecret, "/line/webhook", events...))
+	require.Equal(t, http.StatusOK, w.Code)
+	b.Stop()
+}
+
+func TestLineBot_RejectsInvalidSignature(t *testing.T) {
+	b, server, _ := newTestLineBot(t)
+
+	req := linetest.NewWebhookRequest("wrong-secret", "/line/webhook", linetest.TextEvent("U1", "r1", "/volume 123"))
+	w := httptest.NewRecorder()
+	b.ServeHTTP(w, req)
+	b.Stop()
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Empty(t, server.Texts())
+}
+
+func TestLineBot_Volume(t *testing.T) {
+	b, server, _ := newTestLineBot(t)
+
+	deliver(t, b, linetest.TextEvent("U1", "r1", "/volume 123"))
+
+	replies := server.Replies()
+	require.Len(t, replies, 1)
+	assert.Equal(t, "r1", replies[0].ReplyToken)
+	assert.Contains(t, replies[0].Messages[0].Text, "1234.50")
+}
+
+func TestLineBot_VerifyBindsLineAccount(t *testing.T) {
+	b, server, services := newTestLineBot(t)
+
+	deliver(t, b, linetest.TextEvent("U1", "r1", "/verify 123"))
+
+	require.NotNil(t, services.verified)
+	assert.Equal(t, common.Line, services.verified.SocialPlatform)
+	assert.Equal(t, "U1", services.verified.UserId)
+	assert.Equal(t, "alice", services.verified.Username)
+	assert.Equal(t, []string{i18n.NewLocalizer(i18n.En).T(i18n.En, i18n.MsgVerifySuccessLine)}, server.Texts())
+}
+
+func TestLineBot_ServiceError(t *testing.T) {
+	b, server, services := newTestLineBot(t)
+	services.verifyErr = repository.ErrInvalidUID
+
+	deliver(t, b, linetest.TextEvent("U1", "r1", "/verify 123"))
+
+	assert.Equal(t, []string{i18n.NewLocalizer(i18n.En).T(i18n.En, i18n.MsgVerifyInvalidUID)}, server.Texts())
+}
+
+func TestLineBot_InvalidUID(t *testing.T) {
+	b, server, services := newTestLineBot(t)
+
+	deliver(t, b, linetest.TextEvent("U1", "r1", "/verify abc"))
+
+	assert.Nil(t, services.verified)
+	assert.Equal(t, []string{i18n.NewLocalizer(i18n.En).T(i18n.En, i18n.MsgInvalidUIDFormat, "/verify")}, server.Texts())
+}
+
+func TestLineBot_IgnoresGroupsAndOtherEvents(t *testing.T) {
+	b, server, _ := newTestLineBot(t)
+
+	group := linetest.TextEvent("U1", "r1", "/volume 123")
+	group.Source = client.LineSource{Type: "group", UserId: "U1", GroupId: "G1"}
+	follow := client.LineEvent{Type: "follow", ReplyToken: "r2", Source: client.LineSource{Type: "user", UserId: "U1"}}
+	deliver(t, b, group, follow)
+
+	assert.Empty(t, server.Texts())
+}
+
+func TestLineBot_PushesWhenReplyFails(t *testing.T) {
+	b, server, _ := newTestLineBot(t)
+	server.FailReplies(true)
+
+	deliver(t, b, linetest.TextEvent("U1", "r1", "/status 123"))
+
+	pushes := server.Pushes()
+	require.Len(t, pushes, 1)
+	assert.Equal(t, "U1", pushes[0].To)
+	assert.Contains(t, pushes[0].Messages[0].Text, "123")
+	assert.Empty(t, server.Replies())
+}
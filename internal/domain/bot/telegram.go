@@ -4,47 +4,97 @@ import (
 	"context"
 	"fmt"
 	tele "gopkg.in/telebot.v3"
-	"log"
+	"gorm.io/gorm"
+	"net/http"
 	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
 	"ohmycontrolcenter.tech/omcc/internal/domain/bot/handler/group"
 	"ohmycontrolcenter.tech/omcc/internal/domain/bot/handler/private"
+	"ohmycontrolcenter.tech/omcc/internal/domain/command"
 	"ohmycontrolcenter.tech/omcc/internal/domain/service"
-	"ohmycontrolcenter.tech/omcc/internal/domain/service/exchange"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/customer"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/message"
 	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/outbound"
 	"ohmycontrolcenter.tech/omcc/internal/middleware"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
 )
 
+const (
+	defaultPollTimeout       = 10 * time.Second
+	delayedActionInterval    = 2 * time.Second
+	captchaExpireInterval    = 5 * time.Second
+	moderationReloadInterval = 30 * time.Second
+	announcementInterval     = 15 * time.Second
+	broadcastInterval        = 5 * time.Second
+)
+
+// allowedUpdates the update types the handlers need, in both poller modes
+var allowedUpdates = []string{"message", "edited_message", "callback_query", "chat_join_request", "chat_member", "my_chat_member"}
+
 type TelegramBot struct {
-	bot        *tele.Bot
-	cfg        *config.Config
-	log        logger.Logger
-	middleware *middleware.Manager
+	bot             *tele.Bot
+	cfg             *config.Config
+	log             logger.Logger
+	db              *gorm.DB
+	middleware      *middleware.Manager
+	localizer       *i18n.Localizer
+	languageService *service.LanguageService
+	// sendQueue every outbound message goes through, within the flood limits of telegram
+	sendQueue *outbound.Queue
+	// delayedActionService shared by the services queuing timed actions and the worker running them
+	delayedActionService *service.DelayedActionService
+	// inviteLinkService shared by the commands issuing invite links and the revocation worker
+	inviteLinkService *service.InviteLinkService
+	// memberStatusService shared by the chat_member handler and the commands resolving the membership
+	memberStatusService *service.MemberStatusService
+	// captchaService shared by the join challenge handlers and the expiry worker
+	captchaService *service.CaptchaService
+	// moderationService shared by the group message handler and the rule reload worker
+	moderationService *service.ModerationService
+	// announcementService posts the scheduled announcements
+	announcementService *service.AnnouncementService
+	// broadcastService sends the broadcasts queued through the admin api
+	broadcastService *service.BroadcastService
+	// commands the registered commands, listed by /help and pushed to the telegram command menu
+	commands *private.CommandRegistry
+	// dispatcher the platform-neutral commands, also run through the admin api
+	dispatcher *command.Dispatcher
 }
 
-func NewTelegramBot(cfg *config.Config, log logger.Logger, middleware *middleware.Manager) (*TelegramBot, error) {
+func NewTelegramBot(cfg *config.Config, log logger.Logger, db *gorm.DB, middleware *middleware.Manager,
+	localizer *i18n.Localizer, languageService *service.LanguageService) (*TelegramBot, error) {
 	log.Info("initializing telegram bot",
+		logger.String("mode", cfg.Telegram.PollerMode()),
 		logger.String("webhook_url", cfg.Telegram.WebhookURL),
 	)
 
-	webhook := &tele.Webhook{
-		Listen: cfg.Telegram.Port,
-		Endpoint: &tele.WebhookEndpoint{
-			PublicURL: cfg.Telegram.WebhookURL,
-		},
-		AllowedUpdates: []string{"message", "callback_query"},
-		MaxConnections: 40,
+	var poller tele.Poller = newLongPoller(&cfg.Telegram)
+	if cfg.Telegram.PollerMode() == common.BotModeWebhook {
+		if cfg.Telegram.WebhookSecret == "" {
+			log.Warn("webhook secret token is not set, updates are accepted from anyone knowing the url")
+		}
+		// mounted on the admin server the webhook has no listener of its own
+		listen := cfg.Telegram.Port
+		if cfg.Telegram.WebhookOnServer() {
+			listen = ""
+		}
+		poller = &tele.Webhook{
+			Listen: listen,
+			Endpoint: &tele.WebhookEndpoint{
+				PublicURL: cfg.Telegram.WebhookURL,
+			},
+			// requests without the matching X-Telegram-Bot-Api-Secret-Token header are dropped
+			SecretToken:    cfg.Telegram.WebhookSecret,
+			AllowedUpdates: allowedUpdates,
+			MaxConnections: 40,
+		}
 	}
 
 	settings := tele.Settings{
 		Token:  cfg.Telegram.Token,
-		Poller: webhook,
-		//Poller: &tele.LongPoller{Timeout: 10 * time.Second},
-		//Poller: &tele.LongPoller{
-		//	Timeout:        10 * time.Second,
-		//	AllowedUpdates: []string{"message", "callback_query"},
-		//},
-		//URL: "https://api.telegram.org",
+		Poller: poller,
 	}
 
 	b, err := tele.NewBot(settings)
@@ -53,11 +103,23 @@ func NewTelegramBot(cfg *config.Config, log logger.Logger, middleware *middlewar
 	}
 
 	tb := &TelegramBot{
-		bot:        b,
-		cfg:        cfg,
-		log:        log,
-		middleware: middleware,
+		bot:             b,
+		cfg:             cfg,
+		log:             log,
+		db:              db,
+		middleware:      middleware,
+		localizer:       localizer,
+		languageService: languageService,
 	}
+	tb.sendQueue = outbound.NewQueue(&cfg.Telegram.SendQueue, b, log)
+	tb.delayedActionService = service.NewDelayedActionService(b, db, log)
+	tb.inviteLinkService = service.NewInviteLinkService(&cfg.Telegram, b, db, log, tb.delayedActionService)
+	tb.memberStatusService = service.NewMemberStatusService(&cfg.Telegram, b, db, log)
+	tb.captchaService = service.NewCaptchaService(&cfg.Telegram, b, db, log)
+	tb.moderationService = service.NewModerationService(db, log)
+	tb.announcementService = service.NewAnnouncementService(b, tb.sendQueue, db, log, tb.delayedActionService)
+	tb.commands = private.NewCommandRegistry(log, localizer, cfg.Telegram.AdminIds)
+	tb.broadcastService = service.NewBroadcastService(&cfg.Telegram, message.NewSendingMessageService(cfg, tb.sendQueue, log), db, log)
 
 	// 注册命令处理器
 	tb.registerHandlers()
@@ -65,67 +127,238 @@ func NewTelegramBot(cfg *config.Config, log logger.Logger, middleware *middlewar
 	return tb, nil
 }
 
+// SendQueue returns the queue of the outbound messages, to report its depth
+func (t *TelegramBot) SendQueue() *outbound.Queue {
+	return t.sendQueue
+}
+
+// Dispatcher returns the platform-neutral commands of the bot, with the telegram group services
+func (t *TelegramBot) Dispatcher() *command.Dispatcher {
+	return t.dispatcher
+}
+
+// WebhookHandler returns the handler of the webhook updates to mount on the admin server,
+// nil is returned when the bot polls or listens on its own port
+func (t *TelegramBot) WebhookHandler() http.Handler {
+	if t.cfg.Telegram.PollerMode() != common.BotModeWebhook || !t.cfg.Telegram.WebhookOnServer() {
+		return nil
+	}
+	return &webhookHandler{
+		bot:    t.bot,
+		secret: t.cfg.Telegram.WebhookSecret,
+		log:    t.log,
+	}
+}
+
+func newLongPoller(cfg *config.TelegramConfig) *tele.LongPoller {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultPollTimeout
+	}
+	return &tele.LongPoller{
+		Timeout:        timeout,
+		AllowedUpdates: allowedUpdates,
+	}
+}
+
+// setupPoller registers the webhook and checks telegram accepted it, dev falls back to long polling
+// when the registration fails so the bot runs without a public url
+func (t *TelegramBot) setupPoller() error {
+	webhook, ok := t.bot.Poller.(*tele.Webhook)
+	if !ok {
+		// telegram refuses getUpdates while a webhook is set
+		if err := t.bot.RemoveWebhook(); err != nil {
+			return fmt.Errorf("failed to remove webhook for long polling: %w", err)
+		}
+		return nil
+	}
+
+	err := t.bot.SetWebhook(webhook)
+	if err == nil {
+		err = t.checkWebhookStatus()
+	}
+	if err == nil {
+		return nil
+	}
+	if t.cfg.App.Environment != "dev" {
+		return fmt.Errorf("failed to register webhook: %w", err)
+	}
+
+	t.log.Error("failed to register webhook, falling back to long polling", logger.Error(err))
+	t.bot.Poller = newLongPoller(&t.cfg.Telegram)
+	if err := t.bot.RemoveWebhook(); err != nil {
+		return fmt.Errorf("failed to remove webhook for long polling: %w", err)
+	}
+	return nil
+}
+
+// checkWebhookStatus verifies telegram delivers the updates to the configured url
 func (t *TelegramBot) checkWebhookStatus() error {
 	webhook, err := t.bot.Webhook()
 	if err != nil {
 		return fmt.Errorf("failed to get webhook info: %w", err)
 	}
 
-	log.Printf("Webhook status - URL: %s, Pending updates: %d",
-		webhook.Endpoint.PublicURL, webhook.PendingUpdates)
-
+	// getWebhookInfo returns the registered url in Listen
+	t.log.Info("webhook status",
+		logger.String("url", webhook.Listen),
+		logger.Int("pending_updates", webhook.PendingUpdates),
+		logger.String("last_error", webhook.ErrorMessage))
+	if webhook.Listen != t.cfg.Telegram.WebhookURL {
+		return fmt.Errorf("webhook registered with url=%q instead of %q", webhook.Listen, t.cfg.Telegram.WebhookURL)
+	}
 	return nil
 }
 
 func (t *TelegramBot) Start(ctx context.Context) error {
+	if err := t.setupPoller(); err != nil {
+		return err
+	}
+	t.sendQueue.Start()
 	go func() {
 		t.bot.Start()
 	}()
 
+	// run the queued timed actions, warning deletion, unmute and invite link revocation, including the ones due during a restart
+	go t.delayedActionService.Watch(ctx, delayedActionInterval)
+	// revoke the expired invite links whose revocation was not queued
+	go func() {
+		if err := t.inviteLinkService.RevokeExpired(ctx); err != nil {
+			t.log.Error("failed to revoke expired invite links", logger.Error(err))
+		}
+	}()
+	// kick new members who did not pass the captcha in time, including the ones pending before a restart
+	go t.captchaService.Watch(ctx, captchaExpireInterval)
+	// reload the moderation rules so the changes made through the admin api apply without a restart
+	go t.moderationService.Watch(ctx, t.moderationReloadInterval())
+	// post the scheduled announcements, the runs missed during a restart are posted once
+	go t.announcementService.Watch(ctx, announcementInterval)
+	// send the queued broadcasts, resuming the ones interrupted by a restart
+	go t.broadcastService.Watch(ctx, broadcastInterval)
+	// keep the telegram command menu in line with the registered commands
+	go func() {
+		if err := t.commands.SyncCommands(t.bot); err != nil {
+			t.log.Error("failed to sync telegram commands", logger.Error(err))
+		}
+	}()
+
 	return nil
 }
 
+func (t *TelegramBot) moderationReloadInterval() time.Duration {
+	if t.cfg.Telegram.ModerationReloadInterval <= 0 {
+		return moderationReloadInterval
+	}
+	return t.cfg.Telegram.ModerationReloadInterval
+}
+
 // Stop the telegram bot
 func (t *TelegramBot) Stop() {
 	t.bot.Stop()
+	t.sendQueue.Stop()
 }
 
 // registerHandlers 注册命令处理器
 func (t *TelegramBot) registerHandlers() {
 	middlewareHandler := t.middleware.TelegramMiddleware
+	// the replies of the handlers go through the send queue ahead of the bulk messages
+	t.bot.Use(t.sendQueue.Middleware)
 
-	groupHandler := group.NewGroupMessageHandler(&t.cfg.Telegram, t.bot, t.log)
+	groupHandler := group.NewGroupMessageHandler(&t.cfg.Telegram, t.bot, t.log, t.localizer,
+		service.NewPenaltyService(&t.cfg.Telegram.Penalties, t.bot, t.db, t.log, t.delayedActionService), t.moderationService,
+		t.delayedActionService, t.sendQueue)
+	joinRequestHandler := group.NewJoinRequestHandler(&t.cfg.Telegram, t.bot, t.sendQueue, t.log, t.localizer, service.NewJoinService(t.db, t.log), t.inviteLinkService)
+	captchaHandler := group.NewCaptchaHandler(t.bot, t.sendQueue, t.log, t.localizer, t.captchaService)
+	memberHandler := group.NewMemberHandler(&t.cfg.Telegram, t.bot, t.sendQueue, t.log, t.localizer, t.memberStatusService, t.inviteLinkService, captchaHandler)
 
-	bitgetClient := exchange.NewBitgetClient(&t.cfg.Exchange.BitgetConfig, t.log)
-	verifyService := service.NewVerifyService(t.cfg, bitgetClient, t.log)
-	volumeService := service.NewVolumeService(&t.cfg.Database, bitgetClient, t.log)
-	checkService := service.NewStatusService(t.cfg, t.log)
-	accountService := service.NewAccountService(t.cfg, t.log)
+	// the commands shared with the other platforms, with the telegram group services
+	services := command.NewServices(t.cfg, t.db, t.log)
+	services.InviteLinks = t.inviteLinkService
+	services.Members = t.memberStatusService
+	t.dispatcher = command.NewDispatcher(t.log, t.localizer, services)
 
-	verifyCommand := private.NewVerifyCommand(t.bot, t.log, *verifyService)
-	volumeCommand := private.NewVolumeCommand(t.log, *volumeService)
-	startCommand := private.NewStartCommand(t.log)
-	checkCommand := private.NewCheckCommand(t.log, *checkService)
-	helpCommand := private.NewHelpCommand(t.log)
-	accountCommand := private.NewAccountCommand(t.bot, t.log, *accountService)
-	onTextCommand := private.NewOnTextCommand(t.log)
+	coreCommand := private.NewCoreCommand(t.log, t.localizer, t.dispatcher)
+	startCommand := private.NewStartCommand(t.log, t.localizer)
+	helpCommand := private.NewHelpCommand(t.log, t.localizer, t.commands)
+	onTextCommand := private.NewOnTextCommand(t.log, t.localizer)
+	langCommand := private.NewLangCommand(t.log, t.localizer, t.languageService)
+	adminCommand := private.NewAdminCommand(t.bot, t.log, t.localizer, &t.cfg.Telegram, customer.NewCustomerService(t.db, t.log))
+	// the order of registration is the order of /help and of the command menu
+	t.commands.Register(&startCommand, &helpCommand, coreCommand, langCommand, adminCommand)
 
 	// processing non-command text message
 	t.bot.Handle(tele.OnText, middlewareHandler(handlerType(onTextCommand.Handle, groupHandler.Handle)))
+	// moderate media captions and messages edited after posting in the groups
+	for _, endpoint := range []string{tele.OnPhoto, tele.OnVideo, tele.OnDocument, tele.OnEdited} {
+		t.bot.Handle(endpoint, middlewareHandler(middleware.Handler{
+			SuperGroupHandler: groupHandler.Handle,
+			DefaultHandler:    groupHandler.Handle,
+		}))
+	}
 
-	// register /start command
-	t.bot.Handle(common.StartCommandName, middlewareHandler(handlerType(startCommand.Handle, groupHandler.Handle)))
-	// register /help command
-	t.bot.Handle(common.HelpCommandName, middlewareHandler(handlerType(helpCommand.Handle, groupHandler.Handle)))
-	// register /verify command
-	t.bot.Handle(common.VerifyCommandName, middlewareHandler(handlerType(verifyCommand.Handle, groupHandler.Handle)))
-	// register /volume command
-	t.bot.Handle(common.VolumeCommandName, middlewareHandler(handlerType(volumeCommand.Handle, groupHandler.Handle)))
-	// register /check command
-	t.bot.Handle(common.StatusCommandName, middlewareHandler(handlerType(checkCommand.Handle, groupHandler.Handle)))
-	// register /account command
-	t.bot.Handle(common.AccountCommandName, middlewareHandler(handlerType(accountCommand.Handle, groupHandler.Handle)))
+	// approve or decline join requests created by the invite links
+	t.bot.Handle(tele.OnChatJoinRequest, middlewareHandler(middleware.Handler{
+		SuperGroupHandler: joinRequestHandler.Handle,
+		DefaultHandler:    joinRequestHandler.Handle,
+	}))
 
+	// sync the member status of the bound users in the managed groups
+	t.bot.Handle(tele.OnChatMember, middlewareHandler(middleware.Handler{
+		SuperGroupHandler: memberHandler.Handle,
+		DefaultHandler:    memberHandler.Handle,
+	}))
+	t.bot.Handle(tele.OnMyChatMember, middlewareHandler(middleware.Handler{
+		SuperGroupHandler: memberHandler.HandleBotMember,
+		DefaultHandler:    memberHandler.HandleBotMember,
+	}))
+
+	// new member captcha buttons
+	for _, btn := range []tele.Btn{group.CaptchaAcceptButton, group.CaptchaAnswerButton} {
+		t.bot.Handle(&btn, middlewareHandler(middleware.Handler{
+			SuperGroupHandler: captchaHandler.HandleCallback,
+			DefaultHandler:    captchaHandler.HandleCallback,
+		}))
+	}
+
+	// register the commands with the scopes they declare
+	for _, cmd := range t.commands.Commands() {
+		t.bot.Handle(cmd.Name, middlewareHandler(t.commandHandler(cmd, groupHandler.Handle)))
+	}
+
+	// register admin inline buttons, only available to the configured admin ids
+	adminOnly := t.middleware.AdminAuthorization
+	for _, btn := range []tele.Btn{
+		private.AdminLookupButton,
+		private.AdminBlacklistButton,
+		private.AdminWhitelistButton,
+		private.AdminUnbanButton,
+		private.AdminKickButton,
+	} {
+		t.bot.Handle(&btn, middlewareHandler(handlerType(adminOnly(adminCommand.HandleCallback))))
+	}
+
+}
+
+// commandHandler routes the command in the scopes it is offered in, the group messages of the commands not offered
+// in the groups are moderated as any other message
+func (t *TelegramBot) commandHandler(cmd *private.Command, groupHandler tele.HandlerFunc) middleware.Handler {
+	endpoint := cmd.Endpoint()
+	if cmd.Scope&private.ScopeAdmin != 0 && cmd.Scope&private.ScopePrivate == 0 {
+		endpoint = t.middleware.AdminAuthorization(endpoint)
+	}
+
+	handler := middleware.Handler{
+		SuperGroupHandler: groupHandler,
+		DefaultHandler:    groupHandler,
+	}
+	if cmd.Scope&(private.ScopePrivate|private.ScopeAdmin) != 0 {
+		handler.PrivateHandler = endpoint
+	}
+	if cmd.Scope&private.ScopeGroup != 0 {
+		handler.SuperGroupHandler = endpoint
+		handler.DefaultHandler = endpoint
+	}
+	return handler
 }
 
 func handlerType(handlerFunc ...tele.HandlerFunc) middleware.Handler {
@@ -144,6 +377,6 @@ func handlerType(handlerFunc ...tele.HandlerFunc) middleware.Handler {
 
 // SendMessage 发送消息
 func (t *TelegramBot) SendMessage(ctx context.Context, chatID int64, message string) error {
-	_, err := t.bot.Send(&tele.Chat{ID: chatID}, message)
+	_, err := t.sendQueue.Send(ctx, outbound.Interactive, &tele.Chat{ID: chatID}, message)
 	return err
 }
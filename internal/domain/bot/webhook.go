@@ -0,0 +1,44 @@
+package bot
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	tele "gopkg.in/telebot.v3"
+	"net/http"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// webhookHandler receives the webhook updates on the admin http server and queues them
+// to the bot, the bot processes them once started
+type webhookHandler struct {
+	bot    *tele.Bot
+	secret string
+	log    logger.Logger
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get(secretTokenHeader)
+	if h.secret != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.secret)) != 1 {
+		h.log.Warn("rejected webhook request with invalid secret token",
+			logger.String("ip", r.RemoteAddr))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var update tele.Update
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		h.log.Error("failed to decode webhook update", logger.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case h.bot.Updates <- update:
+		w.WriteHeader(http.StatusOK)
+	case <-r.Context().Done():
+		// telegram retries the update later
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
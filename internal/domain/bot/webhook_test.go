@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"github.com/stretchr/testify/assert"
+	tele "gopkg.in/telebot.v3"
+	"net/http"
+	"net/http/httptest"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strings"
+	"testing"
+)
+
+func newTestWebhookHandler() *webhookHandler {
+	return &webhookHandler{
+		bot:    &tele.Bot{Updates: make(chan tele.Update, 1)},
+		secret: "s3cret",
+		log:    logger.NewLogger(),
+	}
+}
+
+func TestWebhookHandler_QueuesUpdate(t *testing.T) {
+	handler := newTestWebhookHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(`{"update_id": 42}`))
+	req.Header.Set(secretTokenHeader, "s3cret")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	update := <-handler.bot.Updates
+	assert.Equal(t, 42, update.ID)
+}
+
+func TestWebhookHandler_RejectsInvalidSecret(t *testing.T) {
+	handler := newTestWebhookHandler()
+
+	for _, token := range []string{"", "wrong"} {
+		req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(`{"update_id": 42}`))
+		req.Header.Set(secretTokenHeader, token)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+	assert.Empty(t, handler.bot.Updates)
+}
+
+func TestWebhookHandler_RejectsInvalidBody(t *testing.T) {
+	handler := newTestWebhookHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(`not json`))
+	req.Header.Set(secretTokenHeader, "s3cret")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, handler.bot.Updates)
+}
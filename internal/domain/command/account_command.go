@@ -0,0 +1,46 @@
+package command
+
+import (
+	"context"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+// accountCommand moves the binding of the uid to the account of the sender, the telegram users get new invite links
+type accountCommand struct {
+	log       logger.Logger
+	localizer *i18n.Localizer
+	services  Services
+}
+
+func newAccountCommand(log logger.Logger, localizer *i18n.Localizer, services Services) *accountCommand {
+	return &accountCommand{log: log, localizer: localizer, services: services}
+}
+
+func (h *accountCommand) Command() *Command {
+	return &Command{
+		Name:        common.AccountCommandName,
+		Args:        "<uid>",
+		Description: i18n.MsgCommandAccount,
+		Validator:   &Validator{2, 2, IsNumeric},
+		Handler:     h.Handle,
+	}
+}
+
+func (h *accountCommand) Handle(ctx context.Context, req *Request) (*Reply, error) {
+	uid := req.Args[0]
+	if err := h.services.Account.HandleUpdateCommandService(ctx, uid, req.userInfo(uid, common.Member)); err != nil {
+		return nil, err
+	}
+
+	inviteLinks := h.services.inviteLinks(req)
+	if inviteLinks == nil {
+		return &Reply{Messages: []Message{render(h.localizer, req, i18n.MsgAccountMemberInfoUpdate, i18n.TemplateData{"UID": uid})}}, nil
+	}
+	links, err := inviteLinks.Reissue(ctx, uid, req.User.Id)
+	if err != nil {
+		return nil, inviteLinkError(h.log, uid, err)
+	}
+	return &Reply{Links: links}, nil
+}
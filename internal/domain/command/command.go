@@ -0,0 +1,108 @@
+// Package command platform-neutral commands, the telegram bot, the LINE bot, the admin api and the command line
+// runner are adapters turning their input into a Request and sending the Reply back
+package command
+
+import (
+	"context"
+	"errors"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"slices"
+	"strings"
+)
+
+var ErrUnknownCommand = errors.New("unknown command")
+
+// User the sender of a command on its platform
+type User struct {
+	// Id the id of the user on the platform, the chat id on telegram and the user id on LINE
+	Id        string
+	Username  string
+	Firstname string
+	Lastname  string
+}
+
+// Request a command sent from any front end
+type Request struct {
+	Platform common.SocialPlatformType
+	User     User
+	// Lang the language of the reply, empty for the fallback language
+	Lang i18n.Lang
+	Name string
+	// Args the arguments following the command name
+	Args []string
+	// Progress sends a message before the reply, e.g. while a slow command runs, nil on the platforms answering once
+	Progress func(ctx context.Context, msg Message) error
+}
+
+// Message a text with the parse mode it must be sent with
+type Message struct {
+	Text string `json:"text"`
+	// ParseMode the telegram parse mode of the text, empty on the other platforms
+	ParseMode string `json:"parse_mode,omitempty"`
+}
+
+// Reply the answer of a command, the links are sent one per message after the messages
+type Reply struct {
+	Messages []Message `json:"messages"`
+	Links    []string  `json:"links,omitempty"`
+}
+
+// Texts returns the texts of the messages followed by the links
+func (r *Reply) Texts() []string {
+	texts := make([]string, 0, len(r.Messages)+len(r.Links))
+	for _, msg := range r.Messages {
+		texts = append(texts, msg.Text)
+	}
+	return append(texts, r.Links...)
+}
+
+type Handler func(ctx context.Context, req *Request) (*Reply, error)
+
+// Command self description of a platform-neutral command
+type Command struct {
+	Name        string
+	Args        string
+	Description i18n.Key
+	// Validator checks the arguments before Handler runs
+	Validator *Validator
+	// Platforms the platforms the command is offered on, nil for every platform
+	Platforms []common.SocialPlatformType
+	Handler   Handler
+}
+
+// OfferedOn reports whether the command is offered on the platform
+func (c *Command) OfferedOn(platform common.SocialPlatformType) bool {
+	return c.Platforms == nil || slices.Contains(c.Platforms, platform)
+}
+
+// ParseText splits a text into the command name and its arguments, a telegram command may carry the bot username
+// e.g. /verify@bot 123
+func ParseText(text string) (string, []string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	name, _, _ := strings.Cut(fields[0], "@")
+	return name, fields[1:]
+}
+
+func (r *Request) progress(ctx context.Context, msg Message) error {
+	if r.Progress == nil {
+		return nil
+	}
+	return r.Progress(ctx, msg)
+}
+
+// userInfo the account of the sender bound to the uid
+func (r *Request) userInfo(uid string, memberStatus common.MemberStatus) *common.UserInfo {
+	return &common.UserInfo{
+		UID:            uid,
+		UserId:         r.User.Id,
+		Firstname:      r.User.Firstname,
+		Lastname:       r.User.Lastname,
+		Username:       r.User.Username,
+		MemberStatus:   memberStatus,
+		SocialPlatform: r.Platform,
+	}
+}
@@ -0,0 +1,147 @@
+package command
+
+import (
+	"context"
+	"errors"
+	tele "gopkg.in/telebot.v3"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/pkg/exception"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"ohmycontrolcenter.tech/omcc/util"
+)
+
+// Dispatcher runs the commands the services support, in registration order
+type Dispatcher struct {
+	log          logger.Logger
+	localizer    *i18n.Localizer
+	errorHandler *exception.ErrorHandler
+	commands     []*Command
+	names        map[string]*Command
+}
+
+func NewDispatcher(log logger.Logger, localizer *i18n.Localizer, services Services) *Dispatcher {
+	d := &Dispatcher{
+		log:          log,
+		localizer:    localizer,
+		errorHandler: exception.NewErrorHandler(log),
+		names:        make(map[string]*Command),
+	}
+	d.register(newStatusCommand(log, localizer, services).Command())
+	d.register(newVerifyCommand(log, localizer, services).Command())
+	d.register(newVolumeCommand(localizer, services).Command())
+	d.register(newAccountCommand(log, localizer, services).Command())
+	if services.InviteLinks != nil {
+		d.register(newJoinCommand(log, localizer, services).Command())
+	}
+	return d
+}
+
+func (d *Dispatcher) register(cmd *Command) {
+	d.commands = append(d.commands, cmd)
+	d.names[cmd.Name] = cmd
+}
+
+// Commands returns the registered commands
+func (d *Dispatcher) Commands() []*Command {
+	return d.commands
+}
+
+// Execute runs the command of the request, the errors are returned as a CommandError for the caller to render
+func (d *Dispatcher) Execute(ctx context.Context, req *Request) (*Reply, error) {
+	cmd, ok := d.names[req.Name]
+	if !ok || !cmd.OfferedOn(req.Platform) {
+		return nil, ErrUnknownCommand
+	}
+	if cmd.Validator != nil {
+		if err := cmd.Validator.Validate(cmd.Name, req.Args); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := cmd.Handler(ctx, req)
+	d.logResponse(req, err)
+	if err == nil {
+		return reply, nil
+	}
+	var cmdErr *exception.CommandError
+	if errors.As(err, &cmdErr) {
+		return nil, cmdErr
+	}
+	return nil, d.errorHandler.HandleServiceError(err, map[string]interface{}{
+		"command":  req.Name,
+		"args":     req.Args,
+		"platform": req.Platform.Name(),
+		"user_id":  req.User.Id,
+	})
+}
+
+// Dispatch runs the command of the request and renders its errors, the texts that are no command get the on text
+// reply
+func (d *Dispatcher) Dispatch(ctx context.Context, req *Request) *Reply {
+	reply, err := d.Execute(ctx, req)
+	if errors.Is(err, ErrUnknownCommand) {
+		return &Reply{Messages: []Message{render(d.localizer, req, i18n.MsgOnText, nil)}}
+	}
+	var cmdErr *exception.CommandError
+	if errors.As(err, &cmdErr) {
+		return &Reply{Messages: []Message{{Text: d.Localize(req.Lang, cmdErr)}}}
+	}
+	return reply
+}
+
+// Localize returns the message of the command error in lang
+func (d *Dispatcher) Localize(lang i18n.Lang, cmdErr *exception.CommandError) string {
+	return d.localizer.T(lang, cmdErr.Key, cmdErr.Args...)
+}
+
+func (d *Dispatcher) logResponse(req *Request, err error) {
+	fields := []logger.Field{
+		logger.String("command", req.Name),
+		logger.Any("args", req.Args),
+		logger.String("platform", req.Platform.Name()),
+		logger.String("user_id", req.User.Id),
+	}
+	if err != nil {
+		d.log.Info("Command execution failed with error", append(fields, logger.Error(err))...)
+	} else {
+		d.log.Info("Command execution succeeded with args", fields...)
+	}
+}
+
+// render renders an editable message in the language of the request, the username of the sender is available to
+// every template. Only telegram formats MarkdownV2, the other platforms get the plain text
+func render(localizer *i18n.Localizer, req *Request, key i18n.Key, data i18n.TemplateData, args ...interface{}) Message {
+	if data == nil {
+		data = i18n.TemplateData{}
+	}
+	data["Username"] = req.User.Username
+	rendered := localizer.Render(req.Lang, key, data, args...)
+	if req.Platform != common.Telegram && rendered.ParseMode == tele.ModeMarkdownV2 {
+		return Message{Text: util.StripMarkdownV2(rendered.Text)}
+	}
+	return Message{Text: rendered.Text, ParseMode: rendered.ParseMode}
+}
+
+func text(localizer *i18n.Localizer, req *Request, key i18n.Key, args ...interface{}) Message {
+	return Message{Text: localizer.T(req.Lang, key, args...)}
+}
+
+// inviteLinkError reports a failure to issue the invite links with the way to retry
+func inviteLinkError(log logger.Logger, uid string, err error) *exception.CommandError {
+	log.Error("failed to issue invite links",
+		logger.String("uid", uid),
+		logger.Error(err))
+	return &exception.CommandError{
+		Key:  i18n.MsgInviteLinkFailed,
+		Args: []interface{}{uid},
+		Type: exception.ErrServiceUnavailable,
+	}
+}
+
+func serverError() *exception.CommandError {
+	return &exception.CommandError{
+		Key:  i18n.MsgServerError,
+		Type: exception.ErrServiceUnavailable,
+	}
+}
@@ -0,0 +1,255 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v3"
+	"math/big"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/exception"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strings"
+	"testing"
+)
+
+type fakeServices struct {
+	verified    *common.UserInfo
+	verifyErr   error
+	reissueErr  error
+	reissuedFor string
+	resolved    int64
+	report      *service.StatusReport
+}
+
+func (f *fakeServices) HandleVerification(_ context.Context, _ string, userInfo *common.UserInfo) error {
+	f.verified = userInfo
+	return f.verifyErr
+}
+
+func (f *fakeServices) HandleVolumeCheck(context.Context, string) (*big.Float, error) {
+	return big.NewFloat(1234.5), nil
+}
+
+func (f *fakeServices) Report(_ context.Context, uid string, platform common.SocialPlatformType, userId string) (*service.StatusReport, error) {
+	if f.report == nil {
+		return nil, repository.ErrRecordNotFound
+	}
+	// like the service, the owner of a uid bound to another account stays private
+	if platform != common.Telegram || userId != "42" {
+		return &service.StatusReport{UID: uid}, nil
+	}
+	report := *f.report
+	report.UID = uid
+	report.BoundToSender = true
+	return &report, nil
+}
+
+func (f *fakeServices) HandleUpdateCommandService(context.Context, string, *common.UserInfo) error {
+	return nil
+}
+
+func (f *fakeServices) Issue(_ context.Context, uid string) ([]string, error) {
+	return []string{"https://t.me/+" + uid}, nil
+}
+
+func (f *fakeServices) Reissue(_ context.Context, uid string, userId string) ([]string, error) {
+	f.reissuedFor = userId
+	return []string{"https://t.me/+" + uid}, f.reissueErr
+}
+
+func (f *fakeServices) Resolve(userId int64) common.MemberStatus {
+	f.resolved = userId
+	return common.Member
+}
+
+var localizer = i18n.NewLocalizer(i18n.En)
+
+func newTestDispatcher() (*Dispatcher, *fakeServices) {
+	f := &fakeServices{}
+	return NewDispatcher(logger.NewLogger(), localizer, Services{
+		Verify:      f,
+		Volume:      f,
+		Status:      f,
+		Account:     f,
+		InviteLinks: f,
+		Members:     f,
+	}), f
+}
+
+func newRequest(platform common.SocialPlatformType, text string) *Request {
+	name, args := ParseText(text)
+	return &Request{
+		Platform: platform,
+		User:     User{Id: "42", Username: "alice"},
+		Lang:     i18n.En,
+		Name:     name,
+		Args:     args,
+	}
+}
+
+func TestParseText(t *testing.T) {
+	name, args := ParseText(" /verify@omcc_bot  123 ")
+	assert.Equal(t, common.VerifyCommandName, name)
+	assert.Equal(t, []string{"123"}, args)
+
+	name, args = ParseText("")
+	assert.Empty(t, name)
+	assert.Empty(t, args)
+}
+
+func TestDispatcher_VerifyTelegram(t *testing.T) {
+	d, f := newTestDispatcher()
+	req := newRequest(common.Telegram, "/verify 123")
+	var progress []string
+	req.Progress = func(_ context.Context, msg Message) error {
+		progress = append(progress, msg.Text)
+		return nil
+	}
+
+	reply, err := d.Execute(context.Background(), req)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{localizer.T(i18n.En, i18n.MsgProcessing)}, progress)
+	assert.Equal(t, int64(42), f.resolved)
+	assert.Equal(t, common.Telegram, f.verified.SocialPlatform)
+	assert.Equal(t, common.MemberStatus(common.Member), f.verified.MemberStatus)
+	assert.Equal(t, []string{"https://t.me/+123"}, reply.Links)
+}
+
+func TestDispatcher_VerifyLine(t *testing.T) {
+	d, f := newTestDispatcher()
+
+	reply, err := d.Execute(context.Background(), newRequest(common.Line, "/verify 123"))
+
+	require.NoError(t, err)
+	assert.Zero(t, f.resolved)
+	assert.Equal(t, common.Line, f.verified.SocialPlatform)
+	assert.Equal(t, common.MemberStatus(common.Left), f.verified.MemberStatus)
+	assert.Equal(t, []string{localizer.T(i18n.En, i18n.MsgVerifySuccessLine)}, reply.Texts())
+}
+
+func TestDispatcher_Errors(t *testing.T) {
+	d, f := newTestDispatcher()
+
+	_, err := d.Execute(context.Background(), newRequest(common.Telegram, "/verify abc"))
+	var cmdErr *exception.CommandError
+	require.True(t, errors.As(err, &cmdErr))
+	assert.Equal(t, i18n.MsgInvalidUIDFormat, cmdErr.Key)
+	assert.Nil(t, f.verified)
+
+	f.verifyErr = repository.ErrTradingBindingExists
+	_, err = d.Execute(context.Background(), newRequest(common.Telegram, "/verify 123"))
+	require.True(t, errors.As(err, &cmdErr))
+	assert.Equal(t, i18n.MsgVerifyExistsUID, cmdErr.Key)
+
+	f.reissueErr = service.ErrInviteLinkNotOwner
+	_, err = d.Execute(context.Background(), newRequest(common.Telegram, "/join 123"))
+	require.True(t, errors.As(err, &cmdErr))
+	assert.Equal(t, i18n.MsgInviteLinkNotOwner, cmdErr.Key)
+	assert.Equal(t, "42", f.reissuedFor)
+}
+
+func TestDispatcher_PlatformCommands(t *testing.T) {
+	d, _ := newTestDispatcher()
+
+	_, err := d.Execute(context.Background(), newRequest(common.Line, "/join 123"))
+	assert.ErrorIs(t, err, ErrUnknownCommand)
+
+	reply := d.Dispatch(context.Background(), newRequest(common.Line, "/join 123"))
+	assert.Equal(t, []string{localizer.T(i18n.En, i18n.MsgOnText)}, reply.Texts())
+
+	reply = d.Dispatch(context.Background(), newRequest(common.Line, "/volume"))
+	assert.Equal(t, []string{localizer.T(i18n.En, i18n.MsgInvalidCommandFormat, "/volume", "/volume")}, reply.Texts())
+}
+
+func TestDispatcher_MarkdownTemplate(t *testing.T) {
+	f := &fakeServices{}
+	markdownLocalizer := i18n.NewLocalizer(i18n.En)
+	tmpl, err := i18n.CompileTemplate(i18n.MsgVolumeSuccess, i18n.En, 1, "*{{.UID}}* volume {{.Volume}}", tele.ModeMarkdownV2)
+	require.NoError(t, err)
+	markdownLocalizer.SetTemplates([]*i18n.Template{tmpl})
+	d := NewDispatcher(logger.NewLogger(), markdownLocalizer, Services{Volume: f})
+
+	reply, err := d.Execute(context.Background(), newRequest(common.Telegram, "/volume 123"))
+	require.NoError(t, err)
+	assert.Equal(t, []Message{{Text: `*123* volume 1234\.50`, ParseMode: tele.ModeMarkdownV2}}, reply.Messages)
+
+	reply, err = d.Execute(context.Background(), newRequest(common.Line, "/volume 123"))
+	require.NoError(t, err)
+	assert.Equal(t, []Message{{Text: "123 volume 1234.50"}}, reply.Messages)
+}
+
+func TestDispatcher_Status(t *testing.T) {
+	d, f := newTestDispatcher()
+	f.report = &service.StatusReport{
+		IsActive:        true,
+		Status:          common.Whitelisted,
+		MemberStatus:    common.Member,
+		Volume:          big.NewFloat(1234.5),
+		VolumeThreshold: 1000,
+		Groups:          []*model.GroupMembership{{GroupID: -100, GroupTitle: "VIP", MemberStatus: common.Member}},
+	}
+
+	reply, err := d.Execute(context.Background(), newRequest(common.Telegram, "/status 123"))
+
+	require.NoError(t, err)
+	require.Len(t, reply.Messages, 2)
+	assert.Contains(t, reply.Messages[0].Text, "123")
+	assert.Equal(t, strings.Join([]string{
+		localizer.T(i18n.En, i18n.MsgStatusBoundToSender, "123"),
+		localizer.T(i18n.En, i18n.MsgStatusAccount, localizer.T(i18n.En, i18n.MsgAdminActive)),
+		localizer.T(i18n.En, i18n.MsgStatusList, localizer.T(i18n.En, i18n.MsgStatusWhitelisted)),
+		localizer.T(i18n.En, i18n.MsgStatusVolumeMet, big.NewFloat(1234.5), 1000.0),
+		localizer.T(i18n.En, i18n.MsgStatusGroupsHeader),
+		localizer.T(i18n.En, i18n.MsgStatusGroupLine, "VIP", localizer.T(i18n.En, i18n.MsgMemberStatusMember)),
+	}, "\n"), reply.Messages[1].Text)
+}
+
+func TestDispatcher_StatusOtherAccount(t *testing.T) {
+	d, f := newTestDispatcher()
+	f.report = &service.StatusReport{
+		IsActive:        true,
+		Status:          common.Whitelisted,
+		MemberStatus:    common.Member,
+		Volume:          big.NewFloat(1234.5),
+		VolumeThreshold: 1000,
+		Groups:          []*model.GroupMembership{{GroupID: -100, GroupTitle: "VIP", MemberStatus: common.Member}},
+	}
+
+	reply, err := d.Execute(context.Background(), newRequest(common.Line, "/status 123"))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{localizer.T(i18n.En, i18n.MsgStatusBoundToOther, "123", "123")}, reply.Texts())
+	assert.NotContains(t, reply.Texts()[0], "1234.50")
+	assert.NotContains(t, reply.Texts()[0], "VIP")
+}
+
+func TestDispatcher_StatusVolumeUnavailable(t *testing.T) {
+	d, f := newTestDispatcher()
+	f.report = &service.StatusReport{Status: common.Blacklisted, MemberStatus: common.Left, VolumeThreshold: 1000}
+
+	reply, err := d.Execute(context.Background(), newRequest(common.Telegram, "/status 123"))
+
+	require.NoError(t, err)
+	require.Len(t, reply.Messages, 2)
+	details := reply.Messages[1].Text
+	assert.Contains(t, details, localizer.T(i18n.En, i18n.MsgAdminInactive))
+	assert.Contains(t, details, localizer.T(i18n.En, i18n.MsgStatusVolumeUnavailable, "123"))
+	assert.Contains(t, details, localizer.T(i18n.En, i18n.MsgStatusGroupsEmpty))
+}
+
+func TestDispatcher_StatusUnknownUid(t *testing.T) {
+	d, _ := newTestDispatcher()
+
+	_, err := d.Execute(context.Background(), newRequest(common.Telegram, "/status 123"))
+
+	var cmdErr *exception.CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Equal(t, i18n.MsgVerifyInvalidUID, cmdErr.Key)
+}
@@ -0,0 +1,60 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/exception"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+// joinCommand re-issues the group invite links of a verified customer
+type joinCommand struct {
+	log       logger.Logger
+	localizer *i18n.Localizer
+	services  Services
+}
+
+func newJoinCommand(log logger.Logger, localizer *i18n.Localizer, services Services) *joinCommand {
+	return &joinCommand{log: log, localizer: localizer, services: services}
+}
+
+func (h *joinCommand) Command() *Command {
+	return &Command{
+		Name:        common.JoinCommandName,
+		Args:        "<uid>",
+		Description: i18n.MsgCommandJoin,
+		Validator:   &Validator{2, 2, IsNumeric},
+		Platforms:   []common.SocialPlatformType{common.Telegram},
+		Handler:     h.Handle,
+	}
+}
+
+func (h *joinCommand) Handle(ctx context.Context, req *Request) (*Reply, error) {
+	uid := req.Args[0]
+	links, err := h.services.InviteLinks.Reissue(ctx, uid, req.User.Id)
+	switch {
+	case err == nil:
+	case errors.Is(err, service.ErrInviteLinkNotOwner):
+		return nil, &exception.CommandError{
+			Key:  i18n.MsgInviteLinkNotOwner,
+			Args: []interface{}{uid},
+			Type: exception.ErrInvalidFormat,
+		}
+	case errors.Is(err, service.ErrInviteLinkNotAllowed):
+		return nil, &exception.CommandError{
+			Key:  i18n.MsgInviteLinkNotAllowed,
+			Args: []interface{}{uid},
+			Type: exception.ErrInvalidFormat,
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, repository.ErrRecordNotFound
+	default:
+		return nil, inviteLinkError(h.log, uid, err)
+	}
+	return &Reply{Messages: []Message{text(h.localizer, req, i18n.MsgInviteLinkIssued)}, Links: links}, nil
+}
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"math/big"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/exchange"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+type VerifyService interface {
+	HandleVerification(ctx context.Context, uid string, userInfo *common.UserInfo) error
+}
+
+type VolumeService interface {
+	HandleVolumeCheck(ctx context.Context, uid string) (*big.Float, error)
+}
+
+type StatusService interface {
+	Report(ctx context.Context, uid string, platform common.SocialPlatformType, userId string) (*service.StatusReport, error)
+}
+
+type AccountService interface {
+	HandleUpdateCommandService(ctx context.Context, uid string, userInfo *common.UserInfo) error
+}
+
+// InviteLinkIssuer issues the invite links of the telegram groups
+type InviteLinkIssuer interface {
+	Issue(ctx context.Context, uid string) ([]string, error)
+	Reissue(ctx context.Context, uid string, userId string) ([]string, error)
+}
+
+// MemberStatusResolver the status of a telegram user in the telegram groups
+type MemberStatusResolver interface {
+	Resolve(userId int64) common.MemberStatus
+}
+
+// Services the services behind the commands, the group services are nil on the platforms without telegram groups
+type Services struct {
+	Verify  VerifyService
+	Volume  VolumeService
+	Status  StatusService
+	Account AccountService
+
+	InviteLinks InviteLinkIssuer
+	Members     MemberStatusResolver
+}
+
+// inviteLinks the invite links of the telegram groups, only the telegram users can join them
+func (s Services) inviteLinks(req *Request) InviteLinkIssuer {
+	if req.Platform != common.Telegram {
+		return nil
+	}
+	return s.InviteLinks
+}
+
+// NewServices returns the services of the commands available on every platform
+func NewServices(cfg *config.Config, db *gorm.DB, log logger.Logger) Services {
+	bitgetClient := exchange.NewBitgetClient(&cfg.Exchange.BitgetConfig, log)
+	return Services{
+		Verify:  service.NewVerifyService(cfg, bitgetClient, db, log),
+		Volume:  service.NewVolumeService(bitgetClient, db, log),
+		Status:  service.NewStatusService(cfg, bitgetClient, db, log),
+		Account: service.NewAccountService(cfg, db, log),
+	}
+}
@@ -0,0 +1,91 @@
+package command
+
+import (
+	"context"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+	"strings"
+)
+
+// statusCommand the member status of a uid bound to the sender followed by its compliance and the groups it is in
+type statusCommand struct {
+	log       logger.Logger
+	localizer *i18n.Localizer
+	services  Services
+}
+
+func newStatusCommand(log logger.Logger, localizer *i18n.Localizer, services Services) *statusCommand {
+	return &statusCommand{log: log, localizer: localizer, services: services}
+}
+
+func (h *statusCommand) Command() *Command {
+	return &Command{
+		Name:        common.StatusCommandName,
+		Args:        "<uid>",
+		Description: i18n.MsgCommandStatus,
+		Validator:   &Validator{2, 2, IsNumeric},
+		Handler:     h.Handle,
+	}
+}
+
+func (h *statusCommand) Handle(ctx context.Context, req *Request) (*Reply, error) {
+	uid := req.Args[0]
+	report, err := h.services.Status.Report(ctx, uid, req.Platform, req.User.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !report.BoundToSender {
+		return &Reply{Messages: []Message{text(h.localizer, req, i18n.MsgStatusBoundToOther, uid, uid)}}, nil
+	}
+	status := h.localizer.T(req.Lang, i18n.MemberStatusKey(report.MemberStatus))
+	return &Reply{Messages: []Message{
+		render(h.localizer, req, i18n.MsgStatusMemberStatus, i18n.TemplateData{
+			"UID":    uid,
+			"Status": status,
+		}, uid, status),
+		{Text: h.details(req, report)},
+	}}, nil
+}
+
+// details the binding, account, list status, monthly volume and groups of a uid bound to the sender, one per line
+func (h *statusCommand) details(req *Request, report *service.StatusReport) string {
+	t := func(key i18n.Key, args ...interface{}) string {
+		return h.localizer.T(req.Lang, key, args...)
+	}
+
+	lines := make([]string, 0, len(report.Groups)+6)
+	lines = append(lines, t(i18n.MsgStatusBoundToSender, report.UID))
+
+	active := i18n.MsgAdminInactive
+	if report.IsActive {
+		active = i18n.MsgAdminActive
+	}
+	lines = append(lines, t(i18n.MsgStatusAccount, t(active)), t(i18n.MsgStatusList, t(i18n.StatusKey(report.Status))))
+
+	switch {
+	case report.Volume == nil:
+		lines = append(lines, t(i18n.MsgStatusVolumeUnavailable, report.UID))
+	case report.VolumeThreshold <= 0:
+		lines = append(lines, t(i18n.MsgStatusVolume, report.Volume))
+	case report.MeetsThreshold():
+		lines = append(lines, t(i18n.MsgStatusVolumeMet, report.Volume, report.VolumeThreshold))
+	default:
+		lines = append(lines, t(i18n.MsgStatusVolumeBelow, report.Volume, report.VolumeThreshold))
+	}
+
+	if len(report.Groups) == 0 {
+		return strings.Join(append(lines, t(i18n.MsgStatusGroupsEmpty)), "\n")
+	}
+	lines = append(lines, t(i18n.MsgStatusGroupsHeader))
+	for _, membership := range report.Groups {
+		title := membership.GroupTitle
+		if title == "" {
+			title = strconv.FormatInt(membership.GroupID, 10)
+		}
+		lines = append(lines, t(i18n.MsgStatusGroupLine, title, t(i18n.MemberStatusKey(membership.MemberStatus))))
+	}
+	return strings.Join(lines, "\n")
+}
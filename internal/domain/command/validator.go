@@ -0,0 +1,56 @@
+package command
+
+import (
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/pkg/exception"
+	"strconv"
+	"strings"
+)
+
+// Validator checks the arguments of a command, the counts include the command name
+type Validator struct {
+	MinArgs     int
+	MaxArgs     int
+	ValidateArg func(string) bool
+}
+
+func (v *Validator) ValidateGeneralCommand(text string, commandName string) ([]string, error) {
+	args := strings.Fields(text)
+	if len(args) == 0 {
+		args = []string{commandName}
+	}
+	if err := v.Validate(commandName, args[1:]); err != nil {
+		return nil, err
+	}
+	return args[1:], nil
+}
+
+// Validate checks the arguments following the command name
+func (v *Validator) Validate(commandName string, args []string) error {
+	if len(args)+1 < v.MinArgs || (v.MaxArgs > 0 && len(args)+1 > v.MaxArgs) {
+		return &exception.CommandError{
+			Key:  i18n.MsgInvalidCommandFormat,
+			Args: []interface{}{commandName, commandName},
+			Type: exception.ErrInvalidFormat,
+		}
+	}
+
+	// if has args validator then execute func ValidateArg
+	if v.ValidateArg != nil {
+		for _, arg := range args {
+			if !v.ValidateArg(arg) {
+				return &exception.CommandError{
+					Key:  i18n.MsgInvalidUIDFormat,
+					Args: []interface{}{commandName},
+					Type: exception.ErrInvalidFormat,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func IsNumeric(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
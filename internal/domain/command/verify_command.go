@@ -0,0 +1,69 @@
+package command
+
+import (
+	"context"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+)
+
+// verifyCommand binds the uid to the account of the sender, the telegram users get the invite links of the groups
+type verifyCommand struct {
+	log       logger.Logger
+	localizer *i18n.Localizer
+	services  Services
+}
+
+func newVerifyCommand(log logger.Logger, localizer *i18n.Localizer, services Services) *verifyCommand {
+	return &verifyCommand{log: log, localizer: localizer, services: services}
+}
+
+func (h *verifyCommand) Command() *Command {
+	return &Command{
+		Name:        common.VerifyCommandName,
+		Args:        "<uid>",
+		Description: i18n.MsgCommandVerify,
+		Validator:   &Validator{2, 2, IsNumeric},
+		Handler:     h.Handle,
+	}
+}
+
+func (h *verifyCommand) Handle(ctx context.Context, req *Request) (*Reply, error) {
+	uid := req.Args[0]
+	userInfo := req.userInfo(uid, h.memberStatus(req))
+	if err := req.progress(ctx, text(h.localizer, req, i18n.MsgProcessing)); err != nil {
+		return nil, serverError()
+	}
+	if err := h.services.Verify.HandleVerification(ctx, uid, userInfo); err != nil {
+		return nil, err
+	}
+
+	inviteLinks := h.services.inviteLinks(req)
+	if inviteLinks == nil {
+		return &Reply{Messages: []Message{render(h.localizer, req, i18n.MsgVerifySuccessLine, i18n.TemplateData{"UID": uid})}}, nil
+	}
+	reply := &Reply{Messages: []Message{render(h.localizer, req, i18n.MsgVerifySuccess, i18n.TemplateData{"UID": uid})}}
+	links, err := inviteLinks.Issue(ctx, uid)
+	if err != nil {
+		// the uid is verified, the links are issued again with /join
+		cmdErr := inviteLinkError(h.log, uid, err)
+		reply.Messages = append(reply.Messages, text(h.localizer, req, cmdErr.Key, cmdErr.Args...))
+		return reply, nil
+	}
+	reply.Links = links
+	return reply, nil
+}
+
+// memberStatus the status in the telegram groups, later transitions are synced from the chat_member updates; the
+// users of the other platforms are in none of them
+func (h *verifyCommand) memberStatus(req *Request) common.MemberStatus {
+	if h.services.Members == nil || req.Platform != common.Telegram {
+		return common.Left
+	}
+	userId, err := strconv.ParseInt(req.User.Id, 10, 64)
+	if err != nil {
+		return common.Left
+	}
+	return h.services.Members.Resolve(userId)
+}
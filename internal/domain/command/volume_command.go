@@ -0,0 +1,40 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+)
+
+// volumeCommand the trading volume of the uid this month
+type volumeCommand struct {
+	localizer *i18n.Localizer
+	services  Services
+}
+
+func newVolumeCommand(localizer *i18n.Localizer, services Services) *volumeCommand {
+	return &volumeCommand{localizer: localizer, services: services}
+}
+
+func (h *volumeCommand) Command() *Command {
+	return &Command{
+		Name:        common.VolumeCommandName,
+		Args:        "<uid>",
+		Description: i18n.MsgCommandVolume,
+		Validator:   &Validator{2, 2, IsNumeric},
+		Handler:     h.Handle,
+	}
+}
+
+func (h *volumeCommand) Handle(ctx context.Context, req *Request) (*Reply, error) {
+	uid := req.Args[0]
+	volume, err := h.services.Volume.HandleVolumeCheck(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return &Reply{Messages: []Message{render(h.localizer, req, i18n.MsgVolumeSuccess, i18n.TemplateData{
+		"UID":    uid,
+		"Volume": fmt.Sprintf("%.2f", volume),
+	}, volume)}}, nil
+}
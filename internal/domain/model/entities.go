@@ -9,6 +9,7 @@ import (
 
 type Customer struct {
 	Id        string    `gorm:"primary_key;type:varchar(36)" json:"id"`
+	TenantID  string    `gorm:"type:varchar(64);index" json:"-"`
 	Username  string    `gorm:"type:varchar(50)" json:"username"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -28,6 +29,7 @@ type TradingPlatform struct {
 
 type CustomerSocialBinding struct {
 	ID            int64               `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID      string              `gorm:"type:varchar(64);index" json:"-"`
 	CustomerID    string              `gorm:"type:varchar(36)" json:"customer_id"`
 	SocialID      int                 `gorm:"type:int" json:"social_id"`
 	UserID        string              `gorm:"type:varchar(50)" json:"user_id"`
@@ -46,6 +48,7 @@ type CustomerSocialBinding struct {
 
 type CustomerTradingBinding struct {
 	ID           int64            `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID     string           `gorm:"type:varchar(64);index" json:"-"`
 	CustomerID   string           `gorm:"type:varchar(36)" json:"customer_id"`
 	TradingID    int              `json:"trading_id"`
 	UID          string           `gorm:"type:varchar(50)" json:"uid"`
@@ -58,6 +61,7 @@ type CustomerTradingBinding struct {
 
 type TradingHistory struct {
 	ID             int64                   `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID       string                  `gorm:"type:varchar(64);index" json:"-"`
 	BindingID      int64                   `json:"binding_id"`
 	Volume         float64                 `gorm:"type:decimal(16,2)" json:"volume"`
 	TimePeriod     string                  `gorm:"type:enum('daily','weekly','monthly')" json:"time_period"`
@@ -65,7 +69,247 @@ type TradingHistory struct {
 	TradingBinding *CustomerTradingBinding `gorm:"foreignKey:BindingID" json:"-"`
 }
 
+type SocialUserLanguage struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID  string    `gorm:"type:varchar(64);uniqueIndex:uk_social_user" json:"-"`
+	SocialID  int       `gorm:"type:int;uniqueIndex:uk_social_user" json:"social_id"`
+	UserID    string    `gorm:"type:varchar(50);uniqueIndex:uk_social_user" json:"user_id"`
+	Language  string    `gorm:"type:varchar(10)" json:"language"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type MessageTemplate struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID    string    `gorm:"type:varchar(64);uniqueIndex:uk_template_version" json:"-"`
+	TemplateKey string    `gorm:"type:varchar(50);uniqueIndex:uk_template_version" json:"template_key"`
+	Language    string    `gorm:"type:varchar(10);uniqueIndex:uk_template_version" json:"language"`
+	Version     int       `gorm:"uniqueIndex:uk_template_version" json:"version"`
+	Content     string    `gorm:"type:text" json:"content"`
+	ParseMode   string    `gorm:"type:varchar(20)" json:"parse_mode"`
+	UpdatedBy   string    `gorm:"type:varchar(50)" json:"updated_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type InviteLink struct {
+	ID         int64                   `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID   string                  `gorm:"type:varchar(64);index" json:"-"`
+	CustomerID string                  `gorm:"type:varchar(36)" json:"customer_id"`
+	GroupID    int64                   `json:"group_id"`
+	InviteLink string                  `gorm:"type:varchar(255);uniqueIndex:uk_invite_link" json:"invite_link"`
+	Status     common.InviteLinkStatus `gorm:"type:enum('unused','used','revoked')" json:"status"`
+	ExpireAt   time.Time               `json:"expire_at"`
+	UsedBy     string                  `gorm:"type:varchar(50)" json:"used_by,omitempty"`
+	UsedAt     *time.Time              `json:"used_at,omitempty"`
+	RevokedAt  *time.Time              `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time               `json:"created_at"`
+	UpdatedAt  time.Time               `json:"updated_at"`
+}
+
+// ModerationRule rule checked against the messages of a group, TopicID 0 applies to every topic
+type ModerationRule struct {
+	ID           int64                `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID     string               `gorm:"type:varchar(64);index" json:"-"`
+	GroupID      int64                `json:"group_id"`
+	TopicID      int                  `json:"topic_id"`
+	Name         string               `gorm:"type:varchar(100)" json:"name"`
+	MatchType    common.RuleMatchType `gorm:"type:enum('regex','keyword','domain_allowlist','channel_forward')" json:"match_type"`
+	Pattern      string               `gorm:"type:text" json:"pattern"`
+	Action       common.RuleAction    `gorm:"type:enum('delete','warn','mute','ignore')" json:"action"`
+	MuteDuration int                  `json:"mute_duration"`
+	Priority     int                  `json:"priority"`
+	Enabled      bool                 `json:"enabled"`
+	Shadow       bool                 `json:"shadow"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+// TrustedUser user bypassing the moderation rules of a group
+type TrustedUser struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID  string    `gorm:"type:varchar(64);uniqueIndex:uk_trusted_member" json:"-"`
+	GroupID   int64     `gorm:"uniqueIndex:uk_trusted_member" json:"group_id"`
+	UserID    int64     `gorm:"uniqueIndex:uk_trusted_member" json:"user_id"`
+	Note      string    `gorm:"type:varchar(255)" json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserViolation moderation strikes of a user in a group, strikes decay while the user behaves
+type UserViolation struct {
+	ID              int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID        string    `gorm:"type:varchar(64);uniqueIndex:uk_violation_member" json:"-"`
+	GroupID         int64     `gorm:"uniqueIndex:uk_violation_member" json:"group_id"`
+	UserID          int64     `gorm:"uniqueIndex:uk_violation_member" json:"user_id"`
+	Username        string    `gorm:"type:varchar(50)" json:"username"`
+	Strikes         int       `json:"strikes"`
+	LastViolationAt time.Time `json:"last_violation_at"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// CaptchaChallenge a new member restricted until the challenge is answered
+type CaptchaChallenge struct {
+	ID        int64                `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID  string               `gorm:"type:varchar(64);uniqueIndex:uk_captcha_member" json:"-"`
+	GroupID   int64                `gorm:"uniqueIndex:uk_captcha_member" json:"group_id"`
+	UserID    int64                `gorm:"uniqueIndex:uk_captcha_member" json:"user_id"`
+	MessageID int                  `json:"message_id"`
+	Answer    int                  `json:"-"`
+	Status    common.CaptchaStatus `gorm:"type:enum('pending','passed','failed')" json:"status"`
+	ExpireAt  time.Time            `json:"expire_at"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// GroupMembership the latest telegram membership of a bound user in a managed group
+type GroupMembership struct {
+	ID           int64               `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID     string              `gorm:"type:varchar(64);uniqueIndex:uk_group_member" json:"-"`
+	CustomerID   string              `gorm:"type:varchar(36)" json:"customer_id"`
+	SocialID     int                 `gorm:"type:int;uniqueIndex:uk_group_member" json:"social_id"`
+	UserID       string              `gorm:"type:varchar(50);uniqueIndex:uk_group_member" json:"user_id"`
+	GroupID      int64               `gorm:"uniqueIndex:uk_group_member" json:"group_id"`
+	GroupTitle   string              `gorm:"type:varchar(255)" json:"group_title"`
+	MemberStatus common.MemberStatus `gorm:"type:enum('creator', 'administrator', 'member', 'restricted', 'left', 'kicked')" json:"member_status"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+// LinkSharingIncident a user joining a group through the invite link issued to another customer
+type LinkSharingIncident struct {
+	ID               int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID         string    `gorm:"type:varchar(64);index" json:"-"`
+	CustomerID       string    `gorm:"type:varchar(36)" json:"customer_id"`
+	InviteLinkID     int64     `json:"invite_link_id"`
+	GroupID          int64     `json:"group_id"`
+	OwnerUserID      string    `gorm:"type:varchar(50)" json:"owner_user_id"`
+	IntruderUserID   string    `gorm:"type:varchar(50)" json:"intruder_user_id"`
+	IntruderUsername string    `gorm:"type:varchar(50)" json:"intruder_username"`
+	Removed          bool      `json:"removed"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type MemberStatusHistory struct {
+	ID         int64               `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID   string              `gorm:"type:varchar(64);index" json:"-"`
+	CustomerID string              `gorm:"type:varchar(36)" json:"customer_id"`
+	SocialID   int                 `gorm:"type:int" json:"social_id"`
+	UserID     string              `gorm:"type:varchar(50)" json:"user_id"`
+	GroupID    int64               `json:"group_id"`
+	OldStatus  common.MemberStatus `gorm:"type:enum('creator', 'administrator', 'member', 'restricted', 'left', 'kicked')" json:"old_status"`
+	NewStatus  common.MemberStatus `gorm:"type:enum('creator', 'administrator', 'member', 'restricted', 'left', 'kicked')" json:"new_status"`
+	InviteLink string              `gorm:"type:varchar(255)" json:"invite_link,omitempty"`
+	ChangedAt  time.Time           `json:"changed_at"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
 func (c *Customer) BeforeCreate(tx *gorm.DB) error {
 	c.Id = uuid.New().String()
 	return nil
 }
+
+// ModerationLog a moderation action taken on a group message, Penalty is empty when no strike was added
+type ModerationLog struct {
+	ID            int64                `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID      string               `gorm:"type:varchar(64);index" json:"-"`
+	GroupID       int64                `json:"group_id"`
+	TopicID       int                  `json:"topic_id"`
+	UserID        int64                `json:"user_id"`
+	Username      string               `gorm:"type:varchar(50)" json:"username"`
+	MessageID     int                  `json:"message_id"`
+	Text          string               `gorm:"type:text" json:"text"`
+	RuleID        *int64               `json:"rule_id"`
+	RuleName      string               `gorm:"type:varchar(100)" json:"rule_name"`
+	Action        common.RuleAction    `gorm:"type:enum('delete','warn','mute','ignore')" json:"action"`
+	Penalty       common.PenaltyAction `gorm:"type:varchar(10)" json:"penalty"`
+	Strikes       int                  `json:"strikes"`
+	Shadow        bool                 `json:"shadow"`
+	FalsePositive bool                 `json:"false_positive"`
+	ReviewedAt    *time.Time           `json:"reviewed_at"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+// DelayedAction a bot action to run at RunAt, Payload carries the data the chat, message and user ids do not
+type DelayedAction struct {
+	ID        int64                      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID  string                     `gorm:"type:varchar(64);index" json:"-"`
+	Type      common.DelayedActionType   `gorm:"type:enum('delete_message','unmute','revoke_invite_link')" json:"type"`
+	ChatID    int64                      `json:"chat_id"`
+	MessageID int                        `json:"message_id"`
+	UserID    int64                      `json:"user_id"`
+	Payload   string                     `gorm:"type:varchar(255)" json:"payload"`
+	RunAt     time.Time                  `json:"run_at"`
+	Attempts  int                        `json:"attempts"`
+	Status    common.DelayedActionStatus `gorm:"type:enum('pending','done','failed')" json:"status"`
+	LastError string                     `gorm:"type:varchar(255)" json:"last_error"`
+	CreatedAt time.Time                  `json:"created_at"`
+	UpdatedAt time.Time                  `json:"updated_at"`
+}
+
+// Announcement a message posted to a group, or a topic of it, on a cron schedule or once at RunAt,
+// NextRunAt is nil once there is nothing left to run
+type Announcement struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID    string     `gorm:"type:varchar(64);index" json:"-"`
+	GroupID     int64      `json:"group_id"`
+	ThreadID    int        `json:"thread_id"`
+	Text        string     `gorm:"type:text" json:"text"`
+	ParseMode   string     `gorm:"type:varchar(20)" json:"parse_mode"`
+	ImageURL    string     `gorm:"type:varchar(512)" json:"image_url"`
+	Cron        string     `gorm:"type:varchar(100)" json:"cron"`
+	RunAt       *time.Time `json:"run_at"`
+	NextRunAt   *time.Time `json:"next_run_at"`
+	Pin         bool       `json:"pin"`
+	DeleteAfter int        `json:"delete_after"`
+	Enabled     bool       `json:"enabled"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// AnnouncementRun the delivery result of one run of an announcement
+type AnnouncementRun struct {
+	ID             int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID       string    `gorm:"type:varchar(64);index" json:"-"`
+	AnnouncementID int64     `json:"announcement_id"`
+	GroupID        int64     `json:"group_id"`
+	ThreadID       int       `json:"thread_id"`
+	MessageID      int       `json:"message_id"`
+	Success        bool      `json:"success"`
+	Pinned         bool      `json:"pinned"`
+	Error          string    `gorm:"type:varchar(255)" json:"error"`
+	ScheduledAt    time.Time `json:"scheduled_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// BroadcastJob a message sent by the admins to the telegram users of an audience, Filter keeps the criteria
+// the recipients were selected with
+type BroadcastJob struct {
+	ID         int64                    `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID   string                   `gorm:"type:varchar(64);index" json:"-"`
+	Audience   common.BroadcastAudience `gorm:"type:enum('all_active','deactivated','whitelisted','tier','custom')" json:"audience"`
+	Filter     string                   `gorm:"type:text" json:"filter"`
+	Text       string                   `gorm:"type:text" json:"text"`
+	ParseMode  string                   `gorm:"type:varchar(20)" json:"parse_mode"`
+	Status     common.BroadcastStatus   `gorm:"type:enum('pending','running','completed','cancelled')" json:"status"`
+	Total      int                      `json:"total"`
+	CreatedBy  string                   `gorm:"type:varchar(50)" json:"created_by"`
+	FinishedAt *time.Time               `json:"finished_at"`
+	CreatedAt  time.Time                `json:"created_at"`
+	UpdatedAt  time.Time                `json:"updated_at"`
+}
+
+// BroadcastRecipient the delivery of a broadcast to one telegram user, NextAttemptAt is when the worker may try it again
+type BroadcastRecipient struct {
+	ID            int64                           `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID      string                          `gorm:"type:varchar(64);index" json:"-"`
+	JobID         int64                           `json:"job_id"`
+	CustomerID    string                          `gorm:"type:varchar(36)" json:"customer_id"`
+	UserID        string                          `gorm:"type:varchar(50)" json:"user_id"`
+	Status        common.BroadcastRecipientStatus `gorm:"type:enum('pending','sent','failed','skipped')" json:"status"`
+	Attempts      int                             `json:"attempts"`
+	NextAttemptAt time.Time                       `json:"next_attempt_at"`
+	LastError     string                          `gorm:"type:varchar(255)" json:"last_error"`
+	SentAt        *time.Time                      `json:"sent_at"`
+	CreatedAt     time.Time                       `json:"created_at"`
+	UpdatedAt     time.Time                       `json:"updated_at"`
+}
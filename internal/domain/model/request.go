@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type UpdateCustomerStatusRequest struct {
 	CustomerId   string  `json:"customer_id" binding:"required"`
 	SocialId     string  `json:"social_id" binding:"required"`
@@ -7,6 +9,118 @@ type UpdateCustomerStatusRequest struct {
 	MemberStatus *string `json:"member_status" binding:"omitempty"`
 }
 
+type ModerationRuleRequest struct {
+	Id           int64  `json:"id" binding:"omitempty"`
+	GroupId      int64  `json:"group_id" binding:"required"`
+	TopicId      int    `json:"topic_id" binding:"omitempty,min=0"`
+	Name         string `json:"name" binding:"required,max=100"`
+	MatchType    string `json:"match_type" binding:"required,oneof=regex keyword domain_allowlist channel_forward"`
+	Pattern      string `json:"pattern" binding:"omitempty"`
+	Action       string `json:"action" binding:"required,oneof=delete warn mute ignore"`
+	MuteDuration int    `json:"mute_duration" binding:"omitempty,min=30"`
+	Priority     int    `json:"priority" binding:"omitempty"`
+	Enabled      *bool  `json:"enabled" binding:"omitempty"`
+	Shadow       bool   `json:"shadow" binding:"omitempty"`
+}
+
+type DeleteModerationRuleRequest struct {
+	Id int64 `json:"id" binding:"required"`
+}
+
+type TrustedUserRequest struct {
+	GroupId int64  `json:"group_id" binding:"required"`
+	UserId  int64  `json:"user_id" binding:"required"`
+	Note    string `json:"note" binding:"omitempty,max=255"`
+}
+
+// ModerationLogFilter zero values match everything
+type ModerationLogFilter struct {
+	GroupId       int64
+	TopicId       int
+	UserId        int64
+	RuleId        int64
+	Action        string
+	Shadow        *bool
+	FalsePositive *bool
+	From          time.Time
+	To            time.Time
+}
+
+type MarkFalsePositiveRequest struct {
+	Id int64 `json:"id" binding:"required"`
+}
+
+type ClearViolationsRequest struct {
+	GroupId int64 `json:"group_id" binding:"required"`
+	UserId  int64 `json:"user_id" binding:"required"`
+}
+
 type DeleteCustomerRequest struct {
 	IdList []string `json:"id_list" binding:"required"`
 }
+
+type UpdateTemplateRequest struct {
+	Key       string `json:"key" binding:"required"`
+	Language  string `json:"language" binding:"required"`
+	Content   string `json:"content" binding:"required"`
+	ParseMode string `json:"parse_mode" binding:"omitempty,oneof=MarkdownV2"`
+	UpdatedBy string `json:"updated_by" binding:"omitempty"`
+}
+
+type PreviewTemplateRequest struct {
+	Key       string            `json:"key" binding:"required"`
+	Language  string            `json:"language" binding:"required"`
+	Content   string            `json:"content" binding:"required"`
+	ParseMode string            `json:"parse_mode" binding:"omitempty,oneof=MarkdownV2"`
+	Data      map[string]string `json:"data" binding:"omitempty"`
+}
+
+// AnnouncementRequest runs on the cron schedule, or once at RunAt when Cron is empty
+type AnnouncementRequest struct {
+	Id          int64      `json:"id" binding:"omitempty"`
+	GroupId     int64      `json:"group_id" binding:"required"`
+	ThreadId    int        `json:"thread_id" binding:"omitempty,min=0"`
+	Text        string     `json:"text" binding:"required,max=4096"`
+	ParseMode   string     `json:"parse_mode" binding:"omitempty,oneof=MarkdownV2"`
+	ImageURL    string     `json:"image_url" binding:"omitempty,url,max=512"`
+	Cron        string     `json:"cron" binding:"omitempty,max=100"`
+	RunAt       *time.Time `json:"run_at" binding:"omitempty"`
+	Pin         bool       `json:"pin" binding:"omitempty"`
+	DeleteAfter int        `json:"delete_after" binding:"omitempty,min=0"`
+	Enabled     *bool      `json:"enabled" binding:"omitempty"`
+}
+
+type DeleteAnnouncementRequest struct {
+	Id int64 `json:"id" binding:"required"`
+}
+
+// AudienceFilter selects the telegram users of a broadcast, MinVolume and MaxVolume bound the latest monthly
+// trading volume of the tier audience, a zero MaxVolume has no upper bound, CustomerIds lists the custom audience
+type AudienceFilter struct {
+	Audience    string   `json:"audience" binding:"required,oneof=all_active deactivated whitelisted tier custom"`
+	MinVolume   float64  `json:"min_volume,omitempty" binding:"omitempty,min=0"`
+	MaxVolume   float64  `json:"max_volume,omitempty" binding:"omitempty,min=0"`
+	CustomerIds []string `json:"customer_ids,omitempty" binding:"omitempty,max=10000"`
+}
+
+type BroadcastRequest struct {
+	AudienceFilter
+	Text      string `json:"text" binding:"required,max=4096"`
+	ParseMode string `json:"parse_mode" binding:"omitempty,oneof=MarkdownV2"`
+	CreatedBy string `json:"created_by" binding:"omitempty,max=50"`
+}
+
+type CancelBroadcastRequest struct {
+	Id int64 `json:"id" binding:"required"`
+}
+
+// CommandRequest runs a bot command on behalf of the user, as if sent from the platform, TELEGRAM by default
+type CommandRequest struct {
+	Platform  string `json:"platform" binding:"omitempty,max=20"`
+	UserId    string `json:"user_id" binding:"required,max=50"`
+	Username  string `json:"username" binding:"omitempty,max=255"`
+	Firstname string `json:"firstname" binding:"omitempty,max=255"`
+	Lastname  string `json:"lastname" binding:"omitempty,max=255"`
+	Language  string `json:"language" binding:"omitempty"`
+	Text      string `json:"text" binding:"required,max=4096"`
+}
@@ -67,6 +67,50 @@ type CustomerTradingInfo struct {
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+type CustomerStats struct {
+	Total       int64 `gorm:"column:total" json:"total"`
+	Active      int64 `gorm:"column:active" json:"active"`
+	Inactive    int64 `gorm:"column:inactive" json:"inactive"`
+	Normal      int64 `gorm:"column:normal" json:"normal"`
+	Whitelisted int64 `gorm:"column:whitelisted" json:"whitelisted"`
+	Blacklisted int64 `gorm:"column:blacklisted" json:"blacklisted"`
+}
+
+type MessageTemplateInfo struct {
+	Key       string     `json:"key"`
+	Language  string     `json:"language"`
+	Version   int        `json:"version"`
+	Content   string     `json:"content"`
+	ParseMode string     `json:"parse_mode"`
+	Variables []string   `json:"variables"`
+	IsDefault bool       `json:"is_default"`
+	UpdatedBy string     `json:"updated_by,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type PreviewTemplateResponse struct {
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode"`
+}
+
+// RuleHitReport hits of a moderation rule over a time window, RuleID is nil for the configured command patterns
+type RuleHitReport struct {
+	RuleID   *int64           `gorm:"column:rule_id" json:"rule_id"`
+	RuleName string           `gorm:"column:rule_name" json:"rule_name"`
+	Action   string           `gorm:"column:action" json:"action"`
+	Hits     int64            `gorm:"column:hits" json:"hits"`
+	Samples  []*ModerationLog `gorm:"-" json:"samples"`
+}
+
+// BroadcastProgress the recipients of a broadcast job by delivery status
+type BroadcastProgress struct {
+	Job     *BroadcastJob `json:"job"`
+	Pending int64         `json:"pending"`
+	Sent    int64         `json:"sent"`
+	Failed  int64         `json:"failed"`
+	Skipped int64         `json:"skipped"`
+}
+
 func NewPaginatedResponse[T any](data []T, total int64, page, limit int) *PaginatedResponse[T] {
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
 	return &PaginatedResponse[T]{
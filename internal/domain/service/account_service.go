@@ -5,7 +5,6 @@ import (
 	"gorm.io/gorm"
 	"ohmycontrolcenter.tech/omcc/internal/common"
 	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
-	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
 	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
 )
@@ -16,8 +15,7 @@ type AccountCommandService struct {
 	customerSocialBindingRepo repository.CustomerSocialBindingRepository
 }
 
-func NewAccountService(cfg *config.Config, log logger.Logger) *AccountCommandService {
-	db, _ := database.NewMySqlClient(&cfg.Database, log)
+func NewAccountService(cfg *config.Config, db *gorm.DB, log logger.Logger) *AccountCommandService {
 	return &AccountCommandService{
 		db:                        db,
 		Cfg:                       cfg,
@@ -36,5 +34,5 @@ func (u *AccountCommandService) HandleUpdateCommandService(ctx context.Context,
 		"firstname": userInfo.Firstname,
 		"lastname":  userInfo.Lastname,
 	}
-	return u.customerSocialBindingRepo.UpdateUserByUid(ctx, u.db, userInfo.UID, update)
+	return u.customerSocialBindingRepo.UpdateUserByUid(ctx, u.db, userInfo.UID, userInfo.SocialPlatform, update)
 }
@@ -0,0 +1,162 @@
+package announcement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common/schedule"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+	"unicode/utf8"
+)
+
+// maxCaptionLength telegram limit of a photo caption, the text of an announcement with an image
+const maxCaptionLength = 1024
+
+var (
+	ErrAnnouncementNotFound = errors.New("announcement not found")
+	ErrInvalidAnnouncement  = errors.New("invalid announcement")
+)
+
+type AnnouncementServiceInterface interface {
+	GetAnnouncements(ctx context.Context, groupId int64) ([]*model.Announcement, error)
+	CreateAnnouncement(ctx context.Context, req *model.AnnouncementRequest) (*model.Announcement, error)
+	UpdateAnnouncement(ctx context.Context, req *model.AnnouncementRequest) (*model.Announcement, error)
+	DeleteAnnouncement(ctx context.Context, id int64) error
+	GetRuns(ctx context.Context, announcementId int64, page, limit int) (*model.PaginatedResponse[*model.AnnouncementRun], error)
+}
+
+// AnnouncementService manages the scheduled announcements, the bot posts them once due
+type AnnouncementService struct {
+	annRepo repository.AnnouncementRepository
+	runRepo repository.AnnouncementRunRepository
+	db      *gorm.DB
+	Log     logger.Logger
+}
+
+func NewAnnouncementService(db *gorm.DB, log logger.Logger) *AnnouncementService {
+	return &AnnouncementService{
+		annRepo: repository.NewAnnouncementRepository(db, log),
+		runRepo: repository.NewAnnouncementRunRepository(db, log),
+		db:      db,
+		Log:     log,
+	}
+}
+
+// GetAnnouncements returns the announcements, filtered by group when given
+func (s *AnnouncementService) GetAnnouncements(ctx context.Context, groupId int64) ([]*model.Announcement, error) {
+	announcements, err := s.annRepo.FindAll(ctx, s.db, groupId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get announcements: %w", err)
+	}
+	if announcements == nil {
+		announcements = []*model.Announcement{}
+	}
+	return announcements, nil
+}
+
+// CreateAnnouncement validates and schedules a new announcement, a one-off in the past is posted right away
+func (s *AnnouncementService) CreateAnnouncement(ctx context.Context, req *model.AnnouncementRequest) (*model.Announcement, error) {
+	announcement, err := toAnnouncement(req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.annRepo.Create(ctx, s.db, announcement); err != nil {
+		return nil, err
+	}
+	return announcement, nil
+}
+
+// UpdateAnnouncement validates and replaces the announcement with the given id, its schedule restarts from now
+func (s *AnnouncementService) UpdateAnnouncement(ctx context.Context, req *model.AnnouncementRequest) (*model.Announcement, error) {
+	existing, err := s.annRepo.FindById(ctx, s.db, req.Id)
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, ErrAnnouncementNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	announcement, err := toAnnouncement(req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	announcement.ID = existing.ID
+	announcement.CreatedAt = existing.CreatedAt
+	if err := s.annRepo.Update(ctx, s.db, announcement); err != nil {
+		return nil, err
+	}
+	return announcement, nil
+}
+
+// DeleteAnnouncement removes the announcement, its runs are kept as history
+func (s *AnnouncementService) DeleteAnnouncement(ctx context.Context, id int64) error {
+	err := s.annRepo.Delete(ctx, s.db, id)
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		return ErrAnnouncementNotFound
+	}
+	return err
+}
+
+// GetRuns returns a page of the delivery results, newest first, of every announcement when announcementId is 0
+func (s *AnnouncementService) GetRuns(ctx context.Context, announcementId int64, page, limit int) (*model.PaginatedResponse[*model.AnnouncementRun], error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	runs, total, err := s.runRepo.FindAll(ctx, s.db, announcementId, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get announcement runs: %w", err)
+	}
+	if runs == nil {
+		runs = []*model.AnnouncementRun{}
+	}
+	return model.NewPaginatedResponse(runs, total, page, limit), nil
+}
+
+// toAnnouncement checks the request has exactly one of a cron and a run time and computes the first run after now
+func toAnnouncement(req *model.AnnouncementRequest, now time.Time) (*model.Announcement, error) {
+	if (req.Cron == "") == (req.RunAt == nil) {
+		return nil, fmt.Errorf("%w: exactly one of cron and run_at is required", ErrInvalidAnnouncement)
+	}
+	if req.ImageURL != "" && utf8.RuneCountInString(req.Text) > maxCaptionLength {
+		return nil, fmt.Errorf("%w: text is limited to %d characters with an image", ErrInvalidAnnouncement, maxCaptionLength)
+	}
+
+	nextRunAt := req.RunAt
+	if req.Cron != "" {
+		cron, err := schedule.ParseCron(req.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAnnouncement, err)
+		}
+		next := cron.Next(now)
+		if next.IsZero() {
+			return nil, fmt.Errorf("%w: cron %q never runs", ErrInvalidAnnouncement, req.Cron)
+		}
+		nextRunAt = &next
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	return &model.Announcement{
+		GroupID:     req.GroupId,
+		ThreadID:    req.ThreadId,
+		Text:        req.Text,
+		ParseMode:   req.ParseMode,
+		ImageURL:    req.ImageURL,
+		Cron:        req.Cron,
+		RunAt:       req.RunAt,
+		NextRunAt:   nextRunAt,
+		Pin:         req.Pin,
+		DeleteAfter: req.DeleteAfter,
+		Enabled:     enabled,
+	}, nil
+}
@@ -15,6 +15,9 @@ type CustomerServiceInterface interface {
 	GetAllCustomers(ctx context.Context, page, limit int) (*model.PaginatedResponse[*model.CustomerInfoResponse], error)
 	UpdateCustomerStatus(ctx context.Context, req *model.UpdateCustomerStatusRequest) error
 	DeleteCustomer(ctx context.Context, req *model.DeleteCustomerRequest) ([]string, error)
+	GetCustomerInfoBySocialUsername(ctx context.Context, username string) (*model.CustomerInfoResponse, error)
+	UpdateCustomerStatusByUid(ctx context.Context, uid string, status common.Status, memberStatus common.MemberStatus) (*model.CustomerInfoResponse, error)
+	GetCustomerStats(ctx context.Context) (*model.CustomerStats, error)
 }
 
 // CustomerService struct
@@ -115,3 +118,53 @@ func (c *CustomerService) DeleteCustomer(ctx context.Context, req *model.DeleteC
 	}
 	return ids, nil
 }
+
+func (c *CustomerService) GetCustomerInfoBySocialUsername(ctx context.Context, username string) (*model.CustomerInfoResponse, error) {
+	customerInfo, err := c.tradingBindingRepo.FindTradingBindingBySocialUsername(ctx, c.db, username)
+	if err != nil {
+		c.Log.Error("failed to get customer info",
+			logger.String("username", username),
+			logger.Error(err))
+		return nil, err
+	}
+	return customerInfo, nil
+}
+
+// UpdateCustomerStatusByUid updates the list status of the customer bound to uid,
+// an empty status or memberStatus leaves that column unchanged
+func (c *CustomerService) UpdateCustomerStatusByUid(ctx context.Context, uid string, status common.Status, memberStatus common.MemberStatus) (*model.CustomerInfoResponse, error) {
+	customerInfo, err := c.GetCustomerInfoByUid(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if status != "" {
+		updates["status"] = string(status)
+	}
+	if memberStatus != "" {
+		updates["member_status"] = string(memberStatus)
+	}
+	if len(updates) == 0 {
+		return customerInfo, nil
+	}
+
+	if err := c.socialBindingRepo.UpdateStatusByCustomerId(ctx, c.db, customerInfo.Customer.ID, updates); err != nil {
+		return nil, fmt.Errorf("failed to update status: %w", err)
+	}
+	if status != "" {
+		customerInfo.SocialAccountInfo.Status = string(status)
+	}
+	if memberStatus != "" {
+		customerInfo.SocialAccountInfo.MemberStatus = string(memberStatus)
+	}
+	return customerInfo, nil
+}
+
+func (c *CustomerService) GetCustomerStats(ctx context.Context) (*model.CustomerStats, error) {
+	stats, err := c.customerRepo.CountCustomerStats(ctx, c.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer stats: %w", err)
+	}
+	return stats, nil
+}
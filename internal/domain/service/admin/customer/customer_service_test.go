@@ -32,6 +32,22 @@ func (m *MockCustomerRepository) FindAllCustomers(ctx context.Context, tx *gorm.
 	return args.Get(0).([]*model.CustomerWithBindings), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockCustomerRepository) DeleteCustomer(ctx context.Context, tx *gorm.DB, ids []string) ([]string, error) {
+	args := m.Called(ctx, tx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockCustomerRepository) CountCustomerStats(ctx context.Context, tx *gorm.DB) (*model.CustomerStats, error) {
+	args := m.Called(ctx, tx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CustomerStats), args.Error(1)
+}
+
 type MockCustomerTradingBindingRepository struct {
 	mock.Mock
 }
@@ -66,6 +82,14 @@ func (m *MockCustomerTradingBindingRepository) FindTradingBindingByUid(ctx conte
 	return args.Get(0).(*model.CustomerInfoResponse), args.Error(1)
 }
 
+func (m *MockCustomerTradingBindingRepository) FindTradingBindingBySocialUsername(ctx context.Context, tx *gorm.DB, username string) (*model.CustomerInfoResponse, error) {
+	args := m.Called(ctx, tx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CustomerInfoResponse), args.Error(1)
+}
+
 func TestCustomerService_GetAllCustomers(t *testing.T) {
 	// Create test time
 	now := time.Now()
@@ -246,3 +270,52 @@ func TestCustomerService_GetCustomerInfoByUid(t *testing.T) {
 		})
 	}
 }
+
+func TestCustomerService_GetCustomerStats(t *testing.T) {
+	stats := &model.CustomerStats{Total: 3, Active: 2, Inactive: 1, Normal: 1, Whitelisted: 1, Blacklisted: 1}
+
+	tests := []struct {
+		name        string
+		setupMocks  func(*MockCustomerRepository)
+		expectError bool
+	}{
+		{
+			name: "success case",
+			setupMocks: func(customerRepo *MockCustomerRepository) {
+				customerRepo.On("CountCustomerStats", mock.Anything, mock.AnythingOfType("*gorm.DB")).
+					Return(stats, nil)
+			},
+		},
+		{
+			name: "repository error",
+			setupMocks: func(customerRepo *MockCustomerRepository) {
+				customerRepo.On("CountCustomerStats", mock.Anything, mock.AnythingOfType("*gorm.DB")).
+					Return(nil, fmt.Errorf("db error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCustomerRepo := new(MockCustomerRepository)
+			tt.setupMocks(mockCustomerRepo)
+
+			service := &CustomerService{
+				customerRepo: mockCustomerRepo,
+				db:           &gorm.DB{},
+				Log:          logger.NewLogger(),
+			}
+
+			result, err := service.GetCustomerStats(context.Background())
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, stats, result)
+			}
+			mockCustomerRepo.AssertExpectations(t)
+		})
+	}
+}
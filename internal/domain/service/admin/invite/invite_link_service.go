@@ -0,0 +1,40 @@
+package invite
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+type InviteLinkServiceInterface interface {
+	GetOutstandingLinks(ctx context.Context, customerId string) ([]*model.InviteLink, error)
+}
+
+type InviteLinkService struct {
+	inviteLinkRepo repository.InviteLinkRepository
+	db             *gorm.DB
+	Log            logger.Logger
+}
+
+func NewInviteLinkService(db *gorm.DB, log logger.Logger) *InviteLinkService {
+	return &InviteLinkService{
+		inviteLinkRepo: repository.NewInviteLinkRepository(db, log),
+		db:             db,
+		Log:            log,
+	}
+}
+
+// GetOutstandingLinks returns the unused invite links of the customer
+func (s *InviteLinkService) GetOutstandingLinks(ctx context.Context, customerId string) ([]*model.InviteLink, error) {
+	links, err := s.inviteLinkRepo.FindOutstandingByCustomerId(ctx, s.db, customerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outstanding invite links: %w", err)
+	}
+	if links == nil {
+		links = []*model.InviteLink{}
+	}
+	return links, nil
+}
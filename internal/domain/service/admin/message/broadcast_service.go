@@ -0,0 +1,191 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+const (
+	recipientBatchSize = 500
+	// tierVolumeWindow the monthly trading volumes recorded within this window select the tier audience
+	tierVolumeWindow = 31 * 24 * time.Hour
+)
+
+var (
+	ErrBroadcastNotFound = errors.New("broadcast not found")
+	ErrBroadcastFinished = errors.New("broadcast already finished")
+	ErrInvalidBroadcast  = errors.New("invalid broadcast")
+	ErrEmptyAudience     = errors.New("no telegram user matches the broadcast audience")
+)
+
+type BroadcastServiceInterface interface {
+	CreateBroadcast(ctx context.Context, req *model.BroadcastRequest) (*model.BroadcastJob, error)
+	GetBroadcasts(ctx context.Context, page, limit int) (*model.PaginatedResponse[*model.BroadcastJob], error)
+	GetProgress(ctx context.Context, id int64) (*model.BroadcastProgress, error)
+	CancelBroadcast(ctx context.Context, id int64) error
+}
+
+// BroadcastService queues the broadcasts of the admins, the bot sends them to the recipients at the configured rate
+type BroadcastService struct {
+	jobRepo       repository.BroadcastJobRepository
+	recipientRepo repository.BroadcastRecipientRepository
+	socialRepo    repository.CustomerSocialBindingRepository
+	db            *gorm.DB
+	Log           logger.Logger
+}
+
+func NewBroadcastService(db *gorm.DB, log logger.Logger) *BroadcastService {
+	return &BroadcastService{
+		jobRepo:       repository.NewBroadcastJobRepository(db, log),
+		recipientRepo: repository.NewBroadcastRecipientRepository(db, log),
+		socialRepo:    repository.NewCustomerSocialRepository(db, log),
+		db:            db,
+		Log:           log,
+	}
+}
+
+// CreateBroadcast selects the recipients of the audience and queues the job, a telegram user bound to several
+// customers receives the message once
+func (s *BroadcastService) CreateBroadcast(ctx context.Context, req *model.BroadcastRequest) (*model.BroadcastJob, error) {
+	filter := &req.AudienceFilter
+	if err := validateAudience(filter); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	bindings, err := s.socialRepo.FindAudience(ctx, s.db, filter, now.Add(-tierVolumeWindow))
+	if err != nil {
+		return nil, err
+	}
+	recipients := make([]*model.BroadcastRecipient, 0, len(bindings))
+	seen := make(map[string]bool, len(bindings))
+	for _, binding := range bindings {
+		if seen[binding.UserID] {
+			continue
+		}
+		seen[binding.UserID] = true
+		recipients = append(recipients, &model.BroadcastRecipient{
+			CustomerID:    binding.CustomerID,
+			UserID:        binding.UserID,
+			Status:        common.RecipientPending,
+			NextAttemptAt: now,
+		})
+	}
+	if len(recipients) == 0 {
+		return nil, ErrEmptyAudience
+	}
+
+	criteria, err := json.Marshal(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal broadcast filter: %w", err)
+	}
+	job := &model.BroadcastJob{
+		Audience:  common.BroadcastAudience(filter.Audience),
+		Filter:    string(criteria),
+		Text:      req.Text,
+		ParseMode: req.ParseMode,
+		Status:    common.BroadcastPending,
+		Total:     len(recipients),
+		CreatedBy: req.CreatedBy,
+	}
+	err = database.WithTransaction(s.db, func(tx *gorm.DB) error {
+		if err := s.jobRepo.Create(ctx, tx, job); err != nil {
+			return err
+		}
+		for _, recipient := range recipients {
+			recipient.JobID = job.ID
+		}
+		return s.recipientRepo.CreateInBatches(ctx, tx, recipientBatchSize, recipients)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetBroadcasts returns a page of the broadcast jobs, newest first
+func (s *BroadcastService) GetBroadcasts(ctx context.Context, page, limit int) (*model.PaginatedResponse[*model.BroadcastJob], error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	jobs, total, err := s.jobRepo.FindAll(ctx, s.db, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broadcast jobs: %w", err)
+	}
+	if jobs == nil {
+		jobs = []*model.BroadcastJob{}
+	}
+	return model.NewPaginatedResponse(jobs, total, page, limit), nil
+}
+
+// GetProgress returns the job with its recipients counted by delivery status
+func (s *BroadcastService) GetProgress(ctx context.Context, id int64) (*model.BroadcastProgress, error) {
+	job, err := s.jobRepo.FindById(ctx, s.db, id)
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, ErrBroadcastNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.recipientRepo.CountByStatus(ctx, s.db, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broadcast progress: %w", err)
+	}
+	return &model.BroadcastProgress{
+		Job:     job,
+		Pending: counts[common.RecipientPending],
+		Sent:    counts[common.RecipientSent],
+		Failed:  counts[common.RecipientFailed],
+		Skipped: counts[common.RecipientSkipped],
+	}, nil
+}
+
+// CancelBroadcast stops the job, the recipients not sent yet are skipped, a message being sent may still go out
+func (s *BroadcastService) CancelBroadcast(ctx context.Context, id int64) error {
+	return database.WithTransaction(s.db, func(tx *gorm.DB) error {
+		job, err := s.jobRepo.FindForUpdate(ctx, tx, id)
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrBroadcastNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if job.Status == common.BroadcastCompleted || job.Status == common.BroadcastCancelled {
+			return ErrBroadcastFinished
+		}
+
+		now := time.Now()
+		if err := s.jobRepo.UpdateStatus(ctx, tx, id, common.BroadcastCancelled, &now); err != nil {
+			return err
+		}
+		return s.recipientRepo.SkipPending(ctx, tx, id)
+	})
+}
+
+func validateAudience(filter *model.AudienceFilter) error {
+	switch common.BroadcastAudience(filter.Audience) {
+	case common.BroadcastCustom:
+		if len(filter.CustomerIds) == 0 {
+			return fmt.Errorf("%w: customer_ids is required by the custom audience", ErrInvalidBroadcast)
+		}
+	case common.BroadcastTier:
+		if filter.MaxVolume > 0 && filter.MaxVolume <= filter.MinVolume {
+			return fmt.Errorf("%w: max_volume must be greater than min_volume", ErrInvalidBroadcast)
+		}
+	}
+	return nil
+}
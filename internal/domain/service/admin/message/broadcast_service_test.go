@@ -0,0 +1,229 @@
+package message
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database/databasetest"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"testing"
+	"time"
+)
+
+type MockBroadcastJobRepository struct {
+	mock.Mock
+}
+
+func (m *MockBroadcastJobRepository) Create(ctx context.Context, tx *gorm.DB, job *model.BroadcastJob) error {
+	args := m.Called(ctx, tx, job)
+	return args.Error(0)
+}
+
+func (m *MockBroadcastJobRepository) FindById(ctx context.Context, tx *gorm.DB, id int64) (*model.BroadcastJob, error) {
+	args := m.Called(ctx, tx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BroadcastJob), args.Error(1)
+}
+
+func (m *MockBroadcastJobRepository) FindForUpdate(ctx context.Context, tx *gorm.DB, id int64) (*model.BroadcastJob, error) {
+	args := m.Called(ctx, tx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BroadcastJob), args.Error(1)
+}
+
+func (m *MockBroadcastJobRepository) FindAll(ctx context.Context, tx *gorm.DB, page, limit int) ([]*model.BroadcastJob, int64, error) {
+	args := m.Called(ctx, tx, page, limit)
+	return args.Get(0).([]*model.BroadcastJob), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockBroadcastJobRepository) FindActive(ctx context.Context, tx *gorm.DB) ([]*model.BroadcastJob, error) {
+	args := m.Called(ctx, tx)
+	return args.Get(0).([]*model.BroadcastJob), args.Error(1)
+}
+
+func (m *MockBroadcastJobRepository) UpdateStatus(ctx context.Context, tx *gorm.DB, id int64, status common.BroadcastStatus, finishedAt *time.Time) error {
+	args := m.Called(ctx, tx, id, status, finishedAt)
+	return args.Error(0)
+}
+
+type MockBroadcastRecipientRepository struct {
+	mock.Mock
+}
+
+func (m *MockBroadcastRecipientRepository) CreateInBatches(ctx context.Context, tx *gorm.DB, batchSize int, recipients []*model.BroadcastRecipient) error {
+	args := m.Called(ctx, tx, batchSize, recipients)
+	return args.Error(0)
+}
+
+func (m *MockBroadcastRecipientRepository) FindDue(ctx context.Context, tx *gorm.DB, jobId int64, now time.Time, limit int) ([]*model.BroadcastRecipient, error) {
+	args := m.Called(ctx, tx, jobId, now, limit)
+	return args.Get(0).([]*model.BroadcastRecipient), args.Error(1)
+}
+
+func (m *MockBroadcastRecipientRepository) Postpone(ctx context.Context, tx *gorm.DB, ids []int64, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, tx, ids, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockBroadcastRecipientRepository) Finish(ctx context.Context, tx *gorm.DB, id int64, status common.BroadcastRecipientStatus, lastError string, sentAt *time.Time) error {
+	args := m.Called(ctx, tx, id, status, lastError, sentAt)
+	return args.Error(0)
+}
+
+func (m *MockBroadcastRecipientRepository) SkipPending(ctx context.Context, tx *gorm.DB, jobId int64) error {
+	args := m.Called(ctx, tx, jobId)
+	return args.Error(0)
+}
+
+func (m *MockBroadcastRecipientRepository) CountByStatus(ctx context.Context, tx *gorm.DB, jobId int64) (map[common.BroadcastRecipientStatus]int64, error) {
+	args := m.Called(ctx, tx, jobId)
+	return args.Get(0).(map[common.BroadcastRecipientStatus]int64), args.Error(1)
+}
+
+// MockCustomerSocialBindingRepository only the audience is looked up by the broadcasts
+type MockCustomerSocialBindingRepository struct {
+	mock.Mock
+	repository.CustomerSocialBindingRepository
+}
+
+func (m *MockCustomerSocialBindingRepository) FindAudience(ctx context.Context, tx *gorm.DB, filter *model.AudienceFilter, since time.Time) ([]*model.CustomerSocialBinding, error) {
+	args := m.Called(ctx, tx, filter, since)
+	return args.Get(0).([]*model.CustomerSocialBinding), args.Error(1)
+}
+
+func newTestBroadcastService(t *testing.T) (*BroadcastService, *MockBroadcastJobRepository, *MockBroadcastRecipientRepository, *MockCustomerSocialBindingRepository) {
+	jobRepo := new(MockBroadcastJobRepository)
+	recipientRepo := new(MockBroadcastRecipientRepository)
+	socialRepo := new(MockCustomerSocialBindingRepository)
+	return &BroadcastService{
+		jobRepo:       jobRepo,
+		recipientRepo: recipientRepo,
+		socialRepo:    socialRepo,
+		db:            databasetest.NewTxDB(t),
+		Log:           logger.NewLogger(),
+	}, jobRepo, recipientRepo, socialRepo
+}
+
+func TestBroadcastService_CreateBroadcast(t *testing.T) {
+	service, jobRepo, recipientRepo, socialRepo := newTestBroadcastService(t)
+	req := &model.BroadcastRequest{
+		AudienceFilter: model.AudienceFilter{Audience: string(common.BroadcastAllActive)},
+		Text:           "hello",
+		CreatedBy:      "admin",
+	}
+	// the telegram user 101 is bound to two customers
+	socialRepo.On("FindAudience", mock.Anything, mock.Anything, &req.AudienceFilter, mock.Anything).
+		Return([]*model.CustomerSocialBinding{
+			{CustomerID: "c1", UserID: "101"},
+			{CustomerID: "c2", UserID: "102"},
+			{CustomerID: "c3", UserID: "101"},
+		}, nil)
+	jobRepo.On("Create", mock.Anything, mock.Anything, mock.AnythingOfType("*model.BroadcastJob")).
+		Run(func(args mock.Arguments) { args.Get(2).(*model.BroadcastJob).ID = 7 }).
+		Return(nil)
+	var created []*model.BroadcastRecipient
+	recipientRepo.On("CreateInBatches", mock.Anything, mock.Anything, recipientBatchSize, mock.Anything).
+		Run(func(args mock.Arguments) { created = args.Get(3).([]*model.BroadcastRecipient) }).
+		Return(nil)
+
+	job, err := service.CreateBroadcast(context.Background(), req)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), job.ID)
+	assert.Equal(t, 2, job.Total)
+	assert.Equal(t, common.BroadcastPending, job.Status)
+	assert.JSONEq(t, `{"audience":"all_active"}`, job.Filter)
+	require.Len(t, created, 2)
+	assert.Equal(t, "c1", created[0].CustomerID)
+	assert.Equal(t, "101", created[0].UserID)
+	assert.Equal(t, "102", created[1].UserID)
+	for _, recipient := range created {
+		assert.Equal(t, int64(7), recipient.JobID)
+		assert.Equal(t, common.RecipientPending, recipient.Status)
+	}
+}
+
+func TestBroadcastService_CreateBroadcastTierWindow(t *testing.T) {
+	service, _, _, socialRepo := newTestBroadcastService(t)
+	req := &model.BroadcastRequest{
+		AudienceFilter: model.AudienceFilter{Audience: string(common.BroadcastTier), MinVolume: 1000, MaxVolume: 5000},
+		Text:           "hello",
+	}
+	socialRepo.On("FindAudience", mock.Anything, mock.Anything, &req.AudienceFilter, mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since).Round(time.Hour) == tierVolumeWindow
+	})).Return([]*model.CustomerSocialBinding{}, nil)
+
+	_, err := service.CreateBroadcast(context.Background(), req)
+
+	assert.ErrorIs(t, err, ErrEmptyAudience)
+	socialRepo.AssertExpectations(t)
+}
+
+func TestBroadcastService_CreateBroadcastInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter model.AudienceFilter
+	}{
+		{name: "custom without customers", filter: model.AudienceFilter{Audience: string(common.BroadcastCustom)}},
+		{name: "tier max below min", filter: model.AudienceFilter{Audience: string(common.BroadcastTier), MinVolume: 5000, MaxVolume: 1000}},
+		{name: "tier max equal to min", filter: model.AudienceFilter{Audience: string(common.BroadcastTier), MinVolume: 5000, MaxVolume: 5000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, _, socialRepo := newTestBroadcastService(t)
+
+			_, err := service.CreateBroadcast(context.Background(), &model.BroadcastRequest{AudienceFilter: tt.filter, Text: "hello"})
+
+			assert.ErrorIs(t, err, ErrInvalidBroadcast)
+			socialRepo.AssertNotCalled(t, "FindAudience")
+		})
+	}
+}
+
+func TestBroadcastService_CancelBroadcast(t *testing.T) {
+	tests := []struct {
+		name      string
+		job       *model.BroadcastJob
+		findErr   error
+		expectErr error
+	}{
+		{name: "running job skips the pending recipients", job: &model.BroadcastJob{ID: 7, Status: common.BroadcastRunning}},
+		{name: "pending job skips the pending recipients", job: &model.BroadcastJob{ID: 7, Status: common.BroadcastPending}},
+		{name: "completed job", job: &model.BroadcastJob{ID: 7, Status: common.BroadcastCompleted}, expectErr: ErrBroadcastFinished},
+		{name: "cancelled job", job: &model.BroadcastJob{ID: 7, Status: common.BroadcastCancelled}, expectErr: ErrBroadcastFinished},
+		{name: "unknown job", findErr: repository.ErrRecordNotFound, expectErr: ErrBroadcastNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, jobRepo, recipientRepo, _ := newTestBroadcastService(t)
+			if tt.job != nil {
+				jobRepo.On("FindForUpdate", mock.Anything, mock.Anything, int64(7)).Return(tt.job, nil)
+			} else {
+				jobRepo.On("FindForUpdate", mock.Anything, mock.Anything, int64(7)).Return(nil, tt.findErr)
+			}
+			jobRepo.On("UpdateStatus", mock.Anything, mock.Anything, int64(7), common.BroadcastCancelled, mock.Anything).Return(nil)
+			recipientRepo.On("SkipPending", mock.Anything, mock.Anything, int64(7)).Return(nil)
+
+			err := service.CancelBroadcast(context.Background(), 7)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				jobRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				recipientRepo.AssertNotCalled(t, "SkipPending", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			jobRepo.AssertExpectations(t)
+			recipientRepo.AssertExpectations(t)
+		})
+	}
+}
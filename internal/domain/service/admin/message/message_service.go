@@ -5,27 +5,30 @@ import (
 	"fmt"
 	tele "gopkg.in/telebot.v3"
 	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/outbound"
 	"ohmycontrolcenter.tech/omcc/pkg/exception"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
 	"sync"
 )
 
+// SendingMessageService sends the admin messages through the send queue, behind the interactive replies of the bot
 type SendingMessageService struct {
-	bot *tele.Bot
-	cfg *config.Config
-	log logger.Logger
+	sender *outbound.Queue
+	cfg    *config.Config
+	log    logger.Logger
 }
 
-func NewSendingMessageService(cfg *config.Config, bot *tele.Bot, log logger.Logger) *SendingMessageService {
+func NewSendingMessageService(cfg *config.Config, sender *outbound.Queue, log logger.Logger) *SendingMessageService {
 	return &SendingMessageService{
-		bot: bot,
-		cfg: cfg,
-		log: log,
+		sender: sender,
+		cfg:    cfg,
+		log:    log,
 	}
 }
 
-func (m *SendingMessageService) SendMessage(ctx context.Context, chatID int64, message string) error {
-	_, err := m.bot.Send(&tele.Chat{ID: chatID}, message)
+// SendMessage sends the message to the chat, opts are the telebot send options such as the parse mode
+func (m *SendingMessageService) SendMessage(ctx context.Context, chatID int64, message string, opts ...interface{}) error {
+	_, err := m.sender.Send(ctx, outbound.Bulk, &tele.Chat{ID: chatID}, message, opts...)
 	if err != nil {
 		return err
 	}
@@ -46,7 +49,7 @@ func (m *SendingMessageService) SendMessage2MultipleCustomers(ctx context.Contex
 			defer wg.Done()
 
 			// 发送消息
-			_, err := m.bot.Send(&tele.Chat{ID: id}, message)
+			_, err := m.sender.Send(ctx, outbound.Bulk, &tele.Chat{ID: id}, message)
 			if err != nil {
 				m.log.Info(fmt.Sprintf("Failed to send to this telegram user with userId=%d", id))
 				errChan <- id
@@ -79,16 +82,28 @@ func (m *SendingMessageService) SendMessage2Groups(ctx context.Context, groupIDs
 	for _, groupID := range groupIDs {
 		wg.Add(1)
 		go func(id int64) {
-			_, err := m.bot.Send(&tele.Chat{ID: groupID}, message)
+			defer wg.Done()
+
+			_, err := m.sender.Send(ctx, outbound.Bulk, &tele.Chat{ID: id}, message)
 			if err != nil {
-				m.log.Info(fmt.Sprintf("Failed to send to this telegram group with groupId=%d", groupID))
+				m.log.Info(fmt.Sprintf("Failed to send to this telegram group with groupId=%d", id))
+				errChan <- id
 			}
 		}(groupID)
-
 	}
+
 	go func() {
 		wg.Wait()
 		close(errChan)
 	}()
+
+	var errorIds []int64
+	for id := range errChan {
+		errorIds = append(errorIds, id)
+	}
+
+	if len(errorIds) > 0 {
+		return errorIds, exception.ErrSendingMessage
+	}
 	return nil, nil
 }
@@ -0,0 +1,285 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/moderation"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+const (
+	defaultReportWindow  = 7 * 24 * time.Hour
+	defaultReportSamples = 3
+	maxReportSamples     = 20
+)
+
+var (
+	ErrViolationNotFound   = errors.New("user has no strikes in the group")
+	ErrRuleNotFound        = errors.New("moderation rule not found")
+	ErrTrustedUserNotFound = errors.New("user is not trusted in the group")
+	ErrLogNotFound         = errors.New("moderation log not found")
+	ErrInvalidRule         = moderation.ErrInvalidRule
+)
+
+type ModerationServiceInterface interface {
+	GetViolations(ctx context.Context, groupId int64, userId int64) ([]*model.UserViolation, error)
+	ClearViolations(ctx context.Context, req *model.ClearViolationsRequest) error
+	GetRules(ctx context.Context, groupId int64) ([]*model.ModerationRule, error)
+	CreateRule(ctx context.Context, req *model.ModerationRuleRequest) (*model.ModerationRule, error)
+	UpdateRule(ctx context.Context, req *model.ModerationRuleRequest) (*model.ModerationRule, error)
+	DeleteRule(ctx context.Context, id int64) error
+	GetTrustedUsers(ctx context.Context, groupId int64) ([]*model.TrustedUser, error)
+	AddTrustedUser(ctx context.Context, req *model.TrustedUserRequest) error
+	RemoveTrustedUser(ctx context.Context, req *model.TrustedUserRequest) error
+	GetLogs(ctx context.Context, filter *model.ModerationLogFilter, page, limit int) (*model.PaginatedResponse[*model.ModerationLog], error)
+	MarkFalsePositive(ctx context.Context, id int64) error
+	GetShadowReport(ctx context.Context, groupId int64, from, to time.Time, samples int) ([]*model.RuleHitReport, error)
+}
+
+// ModerationService manages the strikes, the moderation rules, the trusted users and the moderation log,
+// the bot picks up the rule changes on its next reload
+type ModerationService struct {
+	violationRepo repository.UserViolationRepository
+	ruleRepo      repository.ModerationRuleRepository
+	trustedRepo   repository.TrustedUserRepository
+	logRepo       repository.ModerationLogRepository
+	// penalties escalation config of every tenant, the strikes decay with it
+	penalties map[string]*config.PenaltyConfig
+	db        *gorm.DB
+	Log       logger.Logger
+}
+
+func NewModerationService(db *gorm.DB, penalties map[string]*config.PenaltyConfig, log logger.Logger) *ModerationService {
+	return &ModerationService{
+		violationRepo: repository.NewUserViolationRepository(db, log),
+		ruleRepo:      repository.NewModerationRuleRepository(db, log),
+		trustedRepo:   repository.NewTrustedUserRepository(db, log),
+		penalties:     penalties,
+		logRepo:       repository.NewModerationLogRepository(db, log),
+		db:            db,
+		Log:           log,
+	}
+}
+
+// GetViolations returns the users with strikes, filtered by group and user when given.
+// The strikes are decayed as the next violation would count them, the users with none left are skipped
+func (s *ModerationService) GetViolations(ctx context.Context, groupId int64, userId int64) ([]*model.UserViolation, error) {
+	violations, err := s.violationRepo.FindAll(ctx, s.db, groupId, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user violations: %w", err)
+	}
+
+	tenantId, _ := database.TenantFromContext(ctx)
+	penalties, ok := s.penalties[tenantId]
+	if !ok {
+		penalties = &config.PenaltyConfig{}
+	}
+	now := time.Now()
+	decayed := make([]*model.UserViolation, 0, len(violations))
+	for _, violation := range violations {
+		violation.Strikes = penalties.Decayed(violation.Strikes, violation.LastViolationAt, now)
+		if violation.Strikes > 0 {
+			decayed = append(decayed, violation)
+		}
+	}
+	return decayed, nil
+}
+
+// ClearViolations resets the strikes of the user in the group
+func (s *ModerationService) ClearViolations(ctx context.Context, req *model.ClearViolationsRequest) error {
+	err := s.violationRepo.Clear(ctx, s.db, req.GroupId, req.UserId)
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		return ErrViolationNotFound
+	}
+	return err
+}
+
+// GetRules returns the moderation rules, filtered by group when given
+func (s *ModerationService) GetRules(ctx context.Context, groupId int64) ([]*model.ModerationRule, error) {
+	rules, err := s.ruleRepo.FindAll(ctx, s.db, groupId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderation rules: %w", err)
+	}
+	if rules == nil {
+		rules = []*model.ModerationRule{}
+	}
+	return rules, nil
+}
+
+// CreateRule validates and stores a new rule, rules are enabled unless told otherwise
+func (s *ModerationService) CreateRule(ctx context.Context, req *model.ModerationRuleRequest) (*model.ModerationRule, error) {
+	rule, err := toModerationRule(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.Create(ctx, s.db, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule validates and replaces the rule with the given id
+func (s *ModerationService) UpdateRule(ctx context.Context, req *model.ModerationRuleRequest) (*model.ModerationRule, error) {
+	existing, err := s.ruleRepo.FindById(ctx, s.db, req.Id)
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, ErrRuleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := toModerationRule(req)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	if err := s.ruleRepo.Update(ctx, s.db, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *ModerationService) DeleteRule(ctx context.Context, id int64) error {
+	err := s.ruleRepo.Delete(ctx, s.db, id)
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		return ErrRuleNotFound
+	}
+	return err
+}
+
+// GetTrustedUsers returns the users bypassing the rules, filtered by group when given
+func (s *ModerationService) GetTrustedUsers(ctx context.Context, groupId int64) ([]*model.TrustedUser, error) {
+	users, err := s.trustedRepo.FindAll(ctx, s.db, groupId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trusted users: %w", err)
+	}
+	if users == nil {
+		users = []*model.TrustedUser{}
+	}
+	return users, nil
+}
+
+func (s *ModerationService) AddTrustedUser(ctx context.Context, req *model.TrustedUserRequest) error {
+	return s.trustedRepo.Upsert(ctx, s.db, &model.TrustedUser{
+		GroupID: req.GroupId,
+		UserID:  req.UserId,
+		Note:    req.Note,
+	})
+}
+
+func (s *ModerationService) RemoveTrustedUser(ctx context.Context, req *model.TrustedUserRequest) error {
+	err := s.trustedRepo.Delete(ctx, s.db, req.GroupId, req.UserId)
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		return ErrTrustedUserNotFound
+	}
+	return err
+}
+
+// GetLogs returns a page of the moderation actions matching the filter, newest first
+func (s *ModerationService) GetLogs(ctx context.Context, filter *model.ModerationLogFilter, page, limit int) (*model.PaginatedResponse[*model.ModerationLog], error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	entries, total, err := s.logRepo.FindAll(ctx, s.db, filter, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderation logs: %w", err)
+	}
+	if entries == nil {
+		entries = []*model.ModerationLog{}
+	}
+	return model.NewPaginatedResponse(entries, total, page, limit), nil
+}
+
+// MarkFalsePositive flags the moderation action as a mistake and takes back the strike it added,
+// marking an entry twice does not forgive a second strike
+func (s *ModerationService) MarkFalsePositive(ctx context.Context, id int64) error {
+	return database.WithTransaction(s.db, func(tx *gorm.DB) error {
+		entry, err := s.logRepo.FindForUpdate(ctx, tx, id)
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrLogNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if entry.FalsePositive {
+			return nil
+		}
+
+		if err := s.logRepo.MarkFalsePositive(ctx, tx, id, time.Now()); err != nil {
+			return err
+		}
+		if entry.Penalty == "" {
+			return nil
+		}
+		return s.violationRepo.Forgive(ctx, tx, entry.GroupID, entry.UserID)
+	})
+}
+
+// GetShadowReport summarizes the hits of the shadow rules within [from, to) with the latest sample messages
+// of every rule, the window defaults to the last 7 days
+func (s *ModerationService) GetShadowReport(ctx context.Context, groupId int64, from, to time.Time, samples int) ([]*model.RuleHitReport, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultReportWindow)
+	}
+	if samples < 1 || samples > maxReportSamples {
+		samples = defaultReportSamples
+	}
+
+	reports, err := s.logRepo.CountByRule(ctx, s.db, groupId, true, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shadow report: %w", err)
+	}
+	for _, report := range reports {
+		report.Samples, err = s.logRepo.FindSamples(ctx, s.db, groupId, report.RuleID, common.RuleAction(report.Action), true, from, to, samples)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get shadow report: %w", err)
+		}
+	}
+	if reports == nil {
+		reports = []*model.RuleHitReport{}
+	}
+	return reports, nil
+}
+
+func toModerationRule(req *model.ModerationRuleRequest) (*model.ModerationRule, error) {
+	matchType := common.RuleMatchType(req.MatchType)
+	if _, err := moderation.Compile(matchType, req.Pattern); err != nil {
+		return nil, err
+	}
+	// 0 mutes for the default duration
+	if req.MuteDuration != 0 && req.MuteDuration < common.MinMuteSeconds {
+		return nil, fmt.Errorf("%w: mute duration is shorter than %d seconds", ErrInvalidRule, common.MinMuteSeconds)
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	return &model.ModerationRule{
+		GroupID:      req.GroupId,
+		TopicID:      req.TopicId,
+		Name:         req.Name,
+		MatchType:    matchType,
+		Pattern:      req.Pattern,
+		Action:       common.RuleAction(req.Action),
+		MuteDuration: req.MuteDuration,
+		Priority:     req.Priority,
+		Enabled:      enabled,
+		Shadow:       req.Shadow,
+	}, nil
+}
@@ -0,0 +1,161 @@
+package moderation
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"testing"
+	"time"
+)
+
+// MockUserViolationRepository only the strikes are listed by GetViolations
+type MockUserViolationRepository struct {
+	mock.Mock
+	repository.UserViolationRepository
+}
+
+func (m *MockUserViolationRepository) FindAll(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) ([]*model.UserViolation, error) {
+	args := m.Called(ctx, tx, groupId, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.UserViolation), args.Error(1)
+}
+
+// MockModerationLogRepository only the hits and samples are read by the shadow report
+type MockModerationLogRepository struct {
+	mock.Mock
+	repository.ModerationLogRepository
+}
+
+func (m *MockModerationLogRepository) CountByRule(ctx context.Context, tx *gorm.DB, groupId int64, shadow bool, from, to time.Time) ([]*model.RuleHitReport, error) {
+	args := m.Called(ctx, tx, groupId, shadow, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.RuleHitReport), args.Error(1)
+}
+
+func (m *MockModerationLogRepository) FindSamples(ctx context.Context, tx *gorm.DB, groupId int64, ruleId *int64, action common.RuleAction, shadow bool, from, to time.Time, limit int) ([]*model.ModerationLog, error) {
+	args := m.Called(ctx, tx, groupId, ruleId, action, shadow, from, to, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ModerationLog), args.Error(1)
+}
+
+func TestModerationService_GetShadowReport(t *testing.T) {
+	to := time.Now()
+	from := to.Add(-time.Hour)
+	ruleId := int64(7)
+	logRepo := new(MockModerationLogRepository)
+	// the rule was changed from warn to delete within the window
+	logRepo.On("CountByRule", mock.Anything, mock.Anything, int64(-100), true, from, to).Return([]*model.RuleHitReport{
+		{RuleID: &ruleId, RuleName: "links", Action: string(common.RuleActionDelete), Hits: 5},
+		{RuleID: &ruleId, RuleName: "links", Action: string(common.RuleActionWarn), Hits: 2},
+	}, nil)
+	logRepo.On("FindSamples", mock.Anything, mock.Anything, int64(-100), &ruleId, common.RuleActionDelete, true, from, to, 2).
+		Return([]*model.ModerationLog{{ID: 1, Action: common.RuleActionDelete}}, nil)
+	logRepo.On("FindSamples", mock.Anything, mock.Anything, int64(-100), &ruleId, common.RuleActionWarn, true, from, to, 2).
+		Return([]*model.ModerationLog{{ID: 2, Action: common.RuleActionWarn}}, nil)
+	service := &ModerationService{logRepo: logRepo, Log: logger.NewLogger()}
+
+	reports, err := service.GetShadowReport(context.Background(), -100, from, to, 2)
+
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	for _, report := range reports {
+		require.Len(t, report.Samples, 1)
+		assert.Equal(t, report.Action, string(report.Samples[0].Action))
+	}
+	logRepo.AssertExpectations(t)
+}
+
+func TestModerationService_GetViolations(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		tenantId string
+		expected map[int64]int
+	}{
+		{
+			name:     "strikes decay with the penalties of the tenant",
+			tenantId: "alpha",
+			expected: map[int64]int{1: 3, 2: 1},
+		},
+		{
+			name:     "strikes never decay without a decay period",
+			tenantId: "beta",
+			expected: map[int64]int{1: 3, 2: 3, 3: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violationRepo := new(MockUserViolationRepository)
+			violationRepo.On("FindAll", mock.Anything, mock.Anything, int64(-100), int64(0)).Return([]*model.UserViolation{
+				{UserID: 1, Strikes: 3, LastViolationAt: now.Add(-time.Hour)},
+				{UserID: 2, Strikes: 3, LastViolationAt: now.Add(-49 * time.Hour)},
+				{UserID: 3, Strikes: 1, LastViolationAt: now.Add(-25 * time.Hour)},
+			}, nil)
+			service := &ModerationService{
+				violationRepo: violationRepo,
+				penalties: map[string]*config.PenaltyConfig{
+					"alpha": {Decay: 24 * time.Hour},
+					"beta":  {},
+				},
+				Log: logger.NewLogger(),
+			}
+
+			violations, err := service.GetViolations(database.WithTenant(context.Background(), tt.tenantId), -100, 0)
+
+			require.NoError(t, err)
+			strikes := make(map[int64]int, len(violations))
+			for _, violation := range violations {
+				strikes[violation.UserID] = violation.Strikes
+			}
+			assert.Equal(t, tt.expected, strikes)
+		})
+	}
+}
+
+func TestToModerationRule_MuteDuration(t *testing.T) {
+	tests := []struct {
+		name         string
+		muteDuration int
+		valid        bool
+	}{
+		{name: "default duration", muteDuration: 0, valid: true},
+		{name: "telegram minimum", muteDuration: 30, valid: true},
+		{name: "under a minute", muteDuration: 45, valid: true},
+		{name: "shorter than telegram allows", muteDuration: 29, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := toModerationRule(&model.ModerationRuleRequest{
+				GroupId:      -100,
+				Name:         "links",
+				MatchType:    string(common.RuleMatchKeyword),
+				Pattern:      "spam",
+				Action:       string(common.RuleActionMute),
+				MuteDuration: tt.muteDuration,
+			})
+
+			if !tt.valid {
+				assert.ErrorIs(t, err, ErrInvalidRule)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.muteDuration, rule.MuteDuration)
+		})
+	}
+}
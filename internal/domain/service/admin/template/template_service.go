@@ -0,0 +1,217 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+var ErrInvalidTemplate = errors.New("invalid message template")
+
+type TemplateServiceInterface interface {
+	GetAllTemplates(ctx context.Context) ([]*model.MessageTemplateInfo, error)
+	GetTemplateVersions(ctx context.Context, key string, language string) ([]*model.MessageTemplateInfo, error)
+	PreviewTemplate(ctx context.Context, req *model.PreviewTemplateRequest) (*model.PreviewTemplateResponse, error)
+	UpdateTemplate(ctx context.Context, req *model.UpdateTemplateRequest) (*model.MessageTemplateInfo, error)
+}
+
+// TemplateService manages the runtime message templates and keeps the localizer up to date
+type TemplateService struct {
+	templateRepo repository.MessageTemplateRepository
+	localizer    *i18n.Localizer
+	db           *gorm.DB
+	Log          logger.Logger
+}
+
+func NewTemplateService(db *gorm.DB, localizer *i18n.Localizer, log logger.Logger) *TemplateService {
+	return &TemplateService{
+		templateRepo: repository.NewMessageTemplateRepository(db, log),
+		localizer:    localizer,
+		db:           db,
+		Log:          log,
+	}
+}
+
+func (t *TemplateService) GetAllTemplates(ctx context.Context) ([]*model.MessageTemplateInfo, error) {
+	templates, err := t.templateRepo.FindActive(ctx, t.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message templates: %w", err)
+	}
+	active := make(map[string]*model.MessageTemplate, len(templates))
+	for _, tmpl := range templates {
+		active[tmpl.TemplateKey+"/"+tmpl.Language] = tmpl
+	}
+
+	var infos []*model.MessageTemplateInfo
+	for _, key := range i18n.TemplateKeys() {
+		for _, lang := range i18n.Supported() {
+			if tmpl, ok := active[string(key)+"/"+string(lang)]; ok {
+				infos = append(infos, toTemplateInfo(tmpl))
+				continue
+			}
+			infos = append(infos, defaultTemplateInfo(key, lang))
+		}
+	}
+	return infos, nil
+}
+
+func (t *TemplateService) GetTemplateVersions(ctx context.Context, key string, language string) ([]*model.MessageTemplateInfo, error) {
+	templateKey, lang, err := parseTemplateKey(key, language)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := t.templateRepo.FindVersions(ctx, t.db, string(templateKey), string(lang))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message template versions: %w", err)
+	}
+
+	infos := make([]*model.MessageTemplateInfo, 0, len(templates)+1)
+	for _, tmpl := range templates {
+		infos = append(infos, toTemplateInfo(tmpl))
+	}
+	// version 0 is the built-in default
+	return append(infos, defaultTemplateInfo(templateKey, lang)), nil
+}
+
+func (t *TemplateService) PreviewTemplate(ctx context.Context, req *model.PreviewTemplateRequest) (*model.PreviewTemplateResponse, error) {
+	key, lang, err := parseTemplateKey(req.Key, req.Language)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := i18n.CompileTemplate(key, lang, 0, req.Content, req.ParseMode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	data := i18n.SampleData(key)
+	for name, value := range req.Data {
+		data[name] = value
+	}
+	text, err := compiled.Execute(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return &model.PreviewTemplateResponse{
+		Text:      text,
+		ParseMode: compiled.ParseMode,
+	}, nil
+}
+
+func (t *TemplateService) UpdateTemplate(ctx context.Context, req *model.UpdateTemplateRequest) (*model.MessageTemplateInfo, error) {
+	key, lang, err := parseTemplateKey(req.Key, req.Language)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := i18n.CompileTemplate(key, lang, 0, req.Content, req.ParseMode); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	created, err := t.templateRepo.CreateVersion(ctx, t.db, &model.MessageTemplate{
+		TemplateKey: string(key),
+		Language:    string(lang),
+		Content:     req.Content,
+		ParseMode:   req.ParseMode,
+		UpdatedBy:   req.UpdatedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update message template: %w", err)
+	}
+
+	if err := t.Reload(ctx); err != nil {
+		t.Log.Error("failed to reload message templates after update",
+			logger.String("key", string(key)),
+			logger.Error(err))
+	}
+	return toTemplateInfo(created), nil
+}
+
+// Reload compiles the active templates from database into the localizer
+func (t *TemplateService) Reload(ctx context.Context) error {
+	templates, err := t.templateRepo.FindActive(ctx, t.db)
+	if err != nil {
+		return err
+	}
+
+	compiled := make([]*i18n.Template, 0, len(templates))
+	for _, tmpl := range templates {
+		c, err := i18n.CompileTemplate(i18n.Key(tmpl.TemplateKey), i18n.Lang(tmpl.Language), tmpl.Version, tmpl.Content, tmpl.ParseMode)
+		if err != nil {
+			// keep serving the built-in message rather than a broken template
+			t.Log.Error("skipped invalid message template",
+				logger.String("key", tmpl.TemplateKey),
+				logger.String("language", tmpl.Language),
+				logger.Int("version", tmpl.Version),
+				logger.Error(err))
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+	t.localizer.SetTemplates(compiled)
+	return nil
+}
+
+// Watch reloads the templates every interval until ctx is done,
+// it picks up changes made by other instances sharing the database
+func (t *TemplateService) Watch(ctx context.Context, interval time.Duration) {
+	if err := t.Reload(ctx); err != nil {
+		t.Log.Error("failed to load message templates", logger.Error(err))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.Reload(ctx); err != nil {
+				t.Log.Error("failed to reload message templates", logger.Error(err))
+			}
+		}
+	}
+}
+
+func parseTemplateKey(key string, language string) (i18n.Key, i18n.Lang, error) {
+	templateKey := i18n.Key(key)
+	if _, ok := i18n.TemplateVariables(templateKey); !ok {
+		return "", "", fmt.Errorf("%w: %w: %s", ErrInvalidTemplate, i18n.ErrTemplateKeyNotEditable, key)
+	}
+	lang, ok := i18n.ParseLang(language)
+	if !ok {
+		return "", "", fmt.Errorf("%w: %w: %s", ErrInvalidTemplate, i18n.ErrUnsupportedLanguage, language)
+	}
+	return templateKey, lang, nil
+}
+
+func toTemplateInfo(tmpl *model.MessageTemplate) *model.MessageTemplateInfo {
+	variables, _ := i18n.TemplateVariables(i18n.Key(tmpl.TemplateKey))
+	return &model.MessageTemplateInfo{
+		Key:       tmpl.TemplateKey,
+		Language:  tmpl.Language,
+		Version:   tmpl.Version,
+		Content:   tmpl.Content,
+		ParseMode: tmpl.ParseMode,
+		Variables: variables,
+		UpdatedBy: tmpl.UpdatedBy,
+		UpdatedAt: &tmpl.CreatedAt,
+	}
+}
+
+func defaultTemplateInfo(key i18n.Key, lang i18n.Lang) *model.MessageTemplateInfo {
+	variables, _ := i18n.TemplateVariables(key)
+	msg, _ := i18n.CatalogMessage(lang, key)
+	return &model.MessageTemplateInfo{
+		Key:       string(key),
+		Language:  string(lang),
+		Content:   msg.Other,
+		ParseMode: msg.Mode,
+		Variables: variables,
+		IsDefault: true,
+	}
+}
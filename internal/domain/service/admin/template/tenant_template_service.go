@@ -0,0 +1,56 @@
+package template
+
+import (
+	"context"
+	"fmt"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+)
+
+// TenantTemplateServices routes the template api to the template service of the tenant of the request, every tenant
+// localizes its bot with its own templates
+type TenantTemplateServices map[string]*TemplateService
+
+func (s TenantTemplateServices) GetAllTemplates(ctx context.Context) ([]*model.MessageTemplateInfo, error) {
+	service, err := s.service(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return service.GetAllTemplates(ctx)
+}
+
+func (s TenantTemplateServices) GetTemplateVersions(ctx context.Context, key string, language string) ([]*model.MessageTemplateInfo, error) {
+	service, err := s.service(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return service.GetTemplateVersions(ctx, key, language)
+}
+
+func (s TenantTemplateServices) PreviewTemplate(ctx context.Context, req *model.PreviewTemplateRequest) (*model.PreviewTemplateResponse, error) {
+	service, err := s.service(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return service.PreviewTemplate(ctx, req)
+}
+
+func (s TenantTemplateServices) UpdateTemplate(ctx context.Context, req *model.UpdateTemplateRequest) (*model.MessageTemplateInfo, error) {
+	service, err := s.service(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return service.UpdateTemplate(ctx, req)
+}
+
+func (s TenantTemplateServices) service(ctx context.Context) (*TemplateService, error) {
+	tenantId, ok := database.TenantFromContext(ctx)
+	if !ok {
+		return nil, database.ErrMissingTenant
+	}
+	service, ok := s[tenantId]
+	if !ok {
+		return nil, fmt.Errorf("no template service for tenant %q", tenantId)
+	}
+	return service, nil
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common/schedule"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/outbound"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+const announcementBatchSize = 20
+
+// AnnouncementService posts the announcements created through the admin api once due and records every run
+type AnnouncementService struct {
+	log     logger.Logger
+	db      *gorm.DB
+	bot     *tele.Bot
+	sender  *outbound.Queue
+	queue   *DelayedActionService
+	annRepo repository.AnnouncementRepository
+	runRepo repository.AnnouncementRunRepository
+}
+
+func NewAnnouncementService(bot *tele.Bot, sender *outbound.Queue, db *gorm.DB, log logger.Logger, queue *DelayedActionService) *AnnouncementService {
+	return &AnnouncementService{
+		log:     log,
+		db:      db,
+		bot:     bot,
+		sender:  sender,
+		queue:   queue,
+		annRepo: repository.NewAnnouncementRepository(db, log),
+		runRepo: repository.NewAnnouncementRunRepository(db, log),
+	}
+}
+
+// RunDue posts the announcements due now, each one is moved to its next run before being posted,
+// so a failed or interrupted post is recorded and skipped rather than repeated in the group
+func (s *AnnouncementService) RunDue(ctx context.Context) error {
+	var announcements []*model.Announcement
+	now := time.Now()
+	err := database.WithTransaction(s.db, func(tx *gorm.DB) error {
+		var err error
+		announcements, err = s.annRepo.FindDue(ctx, tx, now, announcementBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, announcement := range announcements {
+			if err := s.annRepo.Reschedule(ctx, tx, announcement.ID, s.nextRun(announcement, now)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, announcement := range announcements {
+		s.post(ctx, announcement)
+	}
+	return nil
+}
+
+// Watch posts the due announcements every interval until ctx is done, the runs missed during a downtime are posted once
+func (s *AnnouncementService) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RunDue(ctx); err != nil {
+				s.log.Error("failed to run announcements", logger.Error(err))
+			}
+		}
+	}
+}
+
+// nextRun returns the run after now of a cron announcement, nil for a one-off or a cron not matching anymore
+func (s *AnnouncementService) nextRun(announcement *model.Announcement, now time.Time) *time.Time {
+	if announcement.Cron == "" {
+		return nil
+	}
+	cron, err := schedule.ParseCron(announcement.Cron)
+	if err != nil {
+		s.log.Error("invalid announcement cron, stopping it",
+			logger.Int64("id", announcement.ID),
+			logger.String("cron", announcement.Cron),
+			logger.Error(err))
+		return nil
+	}
+	next := cron.Next(now)
+	if next.IsZero() {
+		return nil
+	}
+	return &next
+}
+
+func (s *AnnouncementService) post(ctx context.Context, announcement *model.Announcement) {
+	run := &model.AnnouncementRun{
+		AnnouncementID: announcement.ID,
+		GroupID:        announcement.GroupID,
+		ThreadID:       announcement.ThreadID,
+		ScheduledAt:    *announcement.NextRunAt,
+	}
+
+	msg, err := s.send(ctx, announcement)
+	if err != nil {
+		s.log.Error("failed to post announcement",
+			logger.Int64("id", announcement.ID),
+			logger.Int64("group_id", announcement.GroupID),
+			logger.Error(err))
+		run.Error = truncate(err.Error(), maxLastErrorLength)
+		s.record(ctx, run)
+		return
+	}
+	run.Success = true
+	run.MessageID = msg.ID
+
+	if announcement.Pin {
+		if err := s.bot.Pin(msg, tele.Silent); err != nil {
+			s.log.Error("failed to pin announcement",
+				logger.Int64("id", announcement.ID),
+				logger.Int("message_id", msg.ID),
+				logger.Error(err))
+			run.Error = truncate(fmt.Sprintf("failed to pin: %v", err), maxLastErrorLength)
+		} else {
+			run.Pinned = true
+		}
+	}
+	if announcement.DeleteAfter > 0 {
+		if err := s.queue.ScheduleDeletion(ctx, msg, time.Duration(announcement.DeleteAfter)*time.Second); err != nil {
+			s.log.Error("failed to schedule announcement deletion",
+				logger.Int64("id", announcement.ID),
+				logger.Int("message_id", msg.ID),
+				logger.Error(err))
+		}
+	}
+	s.record(ctx, run)
+}
+
+// send posts the text, as the caption of the image when the announcement has one
+func (s *AnnouncementService) send(ctx context.Context, announcement *model.Announcement) (*tele.Message, error) {
+	var what interface{} = announcement.Text
+	if announcement.ImageURL != "" {
+		what = &tele.Photo{File: tele.FromURL(announcement.ImageURL), Caption: announcement.Text}
+	}
+	return s.sender.Send(ctx, outbound.Bulk, &tele.Chat{ID: announcement.GroupID}, what, &tele.SendOptions{
+		ThreadID:  announcement.ThreadID,
+		ParseMode: tele.ParseMode(announcement.ParseMode),
+	})
+}
+
+func (s *AnnouncementService) record(ctx context.Context, run *model.AnnouncementRun) {
+	if err := s.runRepo.Create(ctx, s.db, run); err != nil {
+		s.log.Error("failed to record announcement run",
+			logger.Int64("announcement_id", run.AnnouncementID),
+			logger.Error(err))
+	}
+}
+
+func truncate(value string, length int) string {
+	if len(value) > length {
+		return value[:length]
+	}
+	return value
+}
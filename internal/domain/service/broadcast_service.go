@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultBroadcastRate = 20
+	broadcastMaxAttempts = 3
+	// broadcastLease time a recipient is held by a worker, the backoff before a failed send is retried
+	broadcastLease = time.Minute
+)
+
+// MessageSender sends a text message to a chat
+type MessageSender interface {
+	SendMessage(ctx context.Context, chatID int64, message string, opts ...interface{}) error
+}
+
+// BroadcastService sends the broadcast jobs queued through the admin api, one recipient at a time at the configured rate
+type BroadcastService struct {
+	log           logger.Logger
+	db            *gorm.DB
+	sender        MessageSender
+	rate          int
+	jobRepo       repository.BroadcastJobRepository
+	recipientRepo repository.BroadcastRecipientRepository
+}
+
+func NewBroadcastService(cfg *config.TelegramConfig, sender MessageSender, db *gorm.DB, log logger.Logger) *BroadcastService {
+	rate := cfg.BroadcastRate
+	if rate <= 0 {
+		rate = defaultBroadcastRate
+	}
+	return &BroadcastService{
+		log:           log,
+		db:            db,
+		sender:        sender,
+		rate:          rate,
+		jobRepo:       repository.NewBroadcastJobRepository(db, log),
+		recipientRepo: repository.NewBroadcastRecipientRepository(db, log),
+	}
+}
+
+// RunDue sends the due recipients of the active jobs, oldest job first
+func (s *BroadcastService) RunDue(ctx context.Context) error {
+	jobs, err := s.jobRepo.FindActive(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	throttle := time.NewTicker(time.Second / time.Duration(s.rate))
+	defer throttle.Stop()
+	for _, job := range jobs {
+		if err := s.runJob(ctx, job.ID, throttle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Watch sends the broadcasts every interval until ctx is done, including the ones interrupted by a restart
+func (s *BroadcastService) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RunDue(ctx); err != nil {
+				s.log.Error("failed to run broadcasts", logger.Error(err))
+			}
+		}
+	}
+}
+
+// runJob sends the job in batches of one second of sending, the job is reloaded before every batch
+// so a cancellation stops it within a second
+func (s *BroadcastService) runJob(ctx context.Context, jobId int64, throttle *time.Ticker) error {
+	for {
+		job, err := s.jobRepo.FindById(ctx, s.db, jobId)
+		if err != nil {
+			return err
+		}
+		if job.Status != common.BroadcastPending && job.Status != common.BroadcastRunning {
+			return nil
+		}
+		if job.Status == common.BroadcastPending {
+			if err := s.jobRepo.UpdateStatus(ctx, s.db, job.ID, common.BroadcastRunning, nil); err != nil {
+				return err
+			}
+		}
+
+		recipients, err := s.claim(ctx, job.ID)
+		if err != nil {
+			return err
+		}
+		if len(recipients) == 0 {
+			return s.complete(ctx, job.ID)
+		}
+
+		for _, recipient := range recipients {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-throttle.C:
+			}
+			s.send(ctx, job, recipient)
+		}
+	}
+}
+
+// claim holds the due recipients of the job so a crash in the middle of the batch retries them after the lease
+func (s *BroadcastService) claim(ctx context.Context, jobId int64) ([]*model.BroadcastRecipient, error) {
+	var recipients []*model.BroadcastRecipient
+	now := time.Now()
+	err := database.WithTransaction(s.db, func(tx *gorm.DB) error {
+		var err error
+		recipients, err = s.recipientRepo.FindDue(ctx, tx, jobId, now, s.rate)
+		if err != nil {
+			return err
+		}
+		ids := make([]int64, 0, len(recipients))
+		for _, recipient := range recipients {
+			ids = append(ids, recipient.ID)
+		}
+		return s.recipientRepo.Postpone(ctx, tx, ids, now.Add(broadcastLease))
+	})
+	return recipients, err
+}
+
+// complete finishes the job once no recipient is left pending, recipients waiting for a retry keep it running
+func (s *BroadcastService) complete(ctx context.Context, jobId int64) error {
+	counts, err := s.recipientRepo.CountByStatus(ctx, s.db, jobId)
+	if err != nil {
+		return err
+	}
+	if counts[common.RecipientPending] > 0 {
+		return nil
+	}
+	now := time.Now()
+	return s.jobRepo.UpdateStatus(ctx, s.db, jobId, common.BroadcastCompleted, &now)
+}
+
+func (s *BroadcastService) send(ctx context.Context, job *model.BroadcastJob, recipient *model.BroadcastRecipient) {
+	chatId, err := strconv.ParseInt(recipient.UserID, 10, 64)
+	if err == nil {
+		err = s.sender.SendMessage(ctx, chatId, job.Text, &tele.SendOptions{ParseMode: tele.ParseMode(job.ParseMode)})
+	}
+	if err == nil {
+		now := time.Now()
+		s.finish(ctx, recipient, common.RecipientSent, nil, &now)
+		return
+	}
+
+	s.log.Error("failed to send broadcast",
+		logger.Int64("job_id", job.ID),
+		logger.String("user_id", recipient.UserID),
+		logger.Int("attempt", recipient.Attempts+1),
+		logger.Error(err))
+	var flood tele.FloodError
+	if errors.As(err, &flood) {
+		// every send fails until telegram lifts the limit, the recipient is retried after the lease
+		s.wait(ctx, time.Duration(flood.RetryAfter)*time.Second)
+		return
+	}
+	if permanentSendError(err) || recipient.Attempts+1 >= broadcastMaxAttempts {
+		s.finish(ctx, recipient, common.RecipientFailed, err, nil)
+	}
+}
+
+func (s *BroadcastService) finish(ctx context.Context, recipient *model.BroadcastRecipient, status common.BroadcastRecipientStatus, cause error, sentAt *time.Time) {
+	var lastError string
+	if cause != nil {
+		lastError = truncate(cause.Error(), maxLastErrorLength)
+	}
+	if err := s.recipientRepo.Finish(ctx, s.db, recipient.ID, status, lastError, sentAt); err != nil {
+		s.log.Error("failed to finish broadcast recipient",
+			logger.Int64("id", recipient.ID),
+			logger.String("status", string(status)),
+			logger.Error(err))
+	}
+}
+
+func (s *BroadcastService) wait(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// permanentSendError reports whether retrying the send cannot succeed
+func permanentSendError(err error) bool {
+	var numErr *strconv.NumError
+	return errors.As(err, &numErr) ||
+		errors.Is(err, tele.ErrBlockedByUser) ||
+		errors.Is(err, tele.ErrUserIsDeactivated) ||
+		errors.Is(err, tele.ErrNotStartedByUser) ||
+		errors.Is(err, tele.ErrChatNotFound)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type fakeSender struct {
+	chatIds []int64
+	err     error
+}
+
+func (f *fakeSender) SendMessage(_ context.Context, chatID int64, _ string, _ ...interface{}) error {
+	f.chatIds = append(f.chatIds, chatID)
+	return f.err
+}
+
+// MockBroadcastRecipientRepository only the delivery results are written by send
+type MockBroadcastRecipientRepository struct {
+	mock.Mock
+	repository.BroadcastRecipientRepository
+}
+
+func (m *MockBroadcastRecipientRepository) Finish(ctx context.Context, tx *gorm.DB, id int64, status common.BroadcastRecipientStatus, lastError string, sentAt *time.Time) error {
+	args := m.Called(ctx, tx, id, status, lastError, sentAt)
+	return args.Error(0)
+}
+
+// floodError the error telebot returns when telegram answers with 429
+func floodError(t *testing.T, retryAfter int) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after ` + strconv.Itoa(retryAfter) +
+			`","parameters":{"retry_after":` + strconv.Itoa(retryAfter) + `}}`))
+	}))
+	defer server.Close()
+
+	bot, err := tele.NewBot(tele.Settings{URL: server.URL, Token: "token", Offline: true})
+	require.NoError(t, err)
+	_, err = bot.Raw("sendMessage", map[string]string{"chat_id": "1", "text": "hello"})
+	var flood tele.FloodError
+	require.ErrorAs(t, err, &flood)
+	return err
+}
+
+func TestBroadcastService_Send(t *testing.T) {
+	temporary := errors.New("connection reset")
+	tests := []struct {
+		name     string
+		userId   string
+		attempts int
+		sendErr  error
+		// status the recipient is finished with, empty when it stays pending for a retry
+		status common.BroadcastRecipientStatus
+	}{
+		{name: "sent", userId: "101", status: common.RecipientSent},
+		{name: "temporary error is retried", userId: "101", sendErr: temporary},
+		{name: "temporary error on the last attempt fails", userId: "101", attempts: broadcastMaxAttempts - 1, sendErr: temporary, status: common.RecipientFailed},
+		{name: "blocked by the user fails at once", userId: "101", sendErr: tele.ErrBlockedByUser, status: common.RecipientFailed},
+		{name: "chat not found fails at once", userId: "101", sendErr: tele.ErrChatNotFound, status: common.RecipientFailed},
+		{name: "invalid user id fails without sending", userId: "U101", status: common.RecipientFailed},
+		{name: "flood waits and is retried", userId: "101", attempts: broadcastMaxAttempts - 1, sendErr: floodError(t, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &fakeSender{err: tt.sendErr}
+			recipientRepo := new(MockBroadcastRecipientRepository)
+			recipientRepo.On("Finish", mock.Anything, mock.Anything, int64(3), mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			service := &BroadcastService{
+				log:           logger.NewLogger(),
+				sender:        sender,
+				rate:          defaultBroadcastRate,
+				recipientRepo: recipientRepo,
+			}
+			job := &model.BroadcastJob{ID: 7, Text: "hello"}
+			recipient := &model.BroadcastRecipient{ID: 3, JobID: 7, UserID: tt.userId, Attempts: tt.attempts}
+
+			service.send(context.Background(), job, recipient)
+
+			if tt.userId == "101" {
+				assert.Equal(t, []int64{101}, sender.chatIds)
+			} else {
+				assert.Empty(t, sender.chatIds)
+			}
+			if tt.status == "" {
+				recipientRepo.AssertNotCalled(t, "Finish", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.Len(t, recipientRepo.Calls, 1)
+			args := recipientRepo.Calls[0].Arguments
+			assert.Equal(t, tt.status, args.Get(3))
+			if tt.status == common.RecipientSent {
+				assert.Empty(t, args.String(4))
+				assert.NotNil(t, args.Get(5))
+			} else {
+				assert.NotEmpty(t, args.String(4))
+				assert.Nil(t, args.Get(5))
+			}
+		})
+	}
+}
+
+func TestPermanentSendError(t *testing.T) {
+	assert.True(t, permanentSendError(tele.ErrBlockedByUser))
+	assert.True(t, permanentSendError(tele.ErrUserIsDeactivated))
+	assert.True(t, permanentSendError(tele.ErrNotStartedByUser))
+	assert.True(t, permanentSendError(tele.ErrChatNotFound))
+	assert.False(t, permanentSendError(errors.New("connection reset")))
+	assert.False(t, permanentSendError(tele.ErrTooLarge))
+}
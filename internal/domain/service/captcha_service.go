@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+	"math/rand"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultCaptchaTimeout = 2 * time.Minute
+	captchaBatchSize      = 100
+	captchaOptionCount    = 4
+)
+
+var ErrCaptchaNotFound = errors.New("no pending captcha challenge")
+
+// CaptchaQuestion the sum a new member has to solve, Options holds the answer and the distractors
+type CaptchaQuestion struct {
+	A, B    int
+	Options []int
+}
+
+// CaptchaService restricts the new members of the monitored groups until they answer the challenge,
+// challenges are persisted so members restricted before a restart are still released or kicked
+type CaptchaService struct {
+	log         logger.Logger
+	db          *gorm.DB
+	bot         *tele.Bot
+	cfg         *config.TelegramConfig
+	captchaRepo repository.CaptchaChallengeRepository
+}
+
+func NewCaptchaService(cfg *config.TelegramConfig, bot *tele.Bot, db *gorm.DB, log logger.Logger) *CaptchaService {
+	return &CaptchaService{
+		log:         log,
+		db:          db,
+		bot:         bot,
+		cfg:         cfg,
+		captchaRepo: repository.NewCaptchaChallengeRepository(db, log),
+	}
+}
+
+// Enabled reports whether new members of the group have to pass the captcha
+func (s *CaptchaService) Enabled(groupId int64) bool {
+	return s.cfg.CaptchaEnabled && slices.Contains(s.cfg.MonitoredGroups, groupId)
+}
+
+// MathMode reports whether the challenge is a sum instead of the rules button
+func (s *CaptchaService) MathMode() bool {
+	return s.cfg.CaptchaMode == common.CaptchaModeMath
+}
+
+func (s *CaptchaService) Timeout() time.Duration {
+	if s.cfg.CaptchaTimeout <= 0 {
+		return defaultCaptchaTimeout
+	}
+	return s.cfg.CaptchaTimeout
+}
+
+// NewQuestion creates a sum of two digits with shuffled options
+func (s *CaptchaService) NewQuestion() *CaptchaQuestion {
+	question := &CaptchaQuestion{A: rand.Intn(9) + 1, B: rand.Intn(9) + 1}
+	answer := question.A + question.B
+	question.Options = []int{answer}
+	for len(question.Options) < captchaOptionCount {
+		option := rand.Intn(18) + 1
+		if !slices.Contains(question.Options, option) {
+			question.Options = append(question.Options, option)
+		}
+	}
+	rand.Shuffle(len(question.Options), func(i, j int) {
+		question.Options[i], question.Options[j] = question.Options[j], question.Options[i]
+	})
+	return question
+}
+
+// Restrict mutes the new member until the challenge is answered
+func (s *CaptchaService) Restrict(chat *tele.Chat, user *tele.User) error {
+	return s.bot.Restrict(chat, &tele.ChatMember{
+		User:            user,
+		Rights:          tele.NoRights(),
+		RestrictedUntil: tele.Forever(),
+	})
+}
+
+// Begin persists the challenge sent in message, answer is 0 for the rules button
+func (s *CaptchaService) Begin(ctx context.Context, chat *tele.Chat, user *tele.User, message *tele.Message, answer int) error {
+	return s.captchaRepo.Upsert(ctx, s.db, &model.CaptchaChallenge{
+		GroupID:   chat.ID,
+		UserID:    user.ID,
+		MessageID: message.ID,
+		Answer:    answer,
+		Status:    common.CaptchaPending,
+		ExpireAt:  time.Now().Add(s.Timeout()),
+	})
+}
+
+// Answer releases the member when the answer is right and kicks them otherwise,
+// ErrCaptchaNotFound is returned when there is no pending challenge for the member
+func (s *CaptchaService) Answer(ctx context.Context, chat *tele.Chat, user *tele.User, answer int) (bool, error) {
+	challenge, err := s.captchaRepo.FindPending(ctx, s.db, chat.ID, user.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return false, ErrCaptchaNotFound
+		}
+		return false, err
+	}
+
+	passed := challenge.Answer == answer && challenge.ExpireAt.After(time.Now())
+	if passed {
+		return true, s.resolve(ctx, challenge, common.CaptchaPassed)
+	}
+	return false, s.resolve(ctx, challenge, common.CaptchaFailed)
+}
+
+// Cancel drops the pending challenge of a member who left before answering
+func (s *CaptchaService) Cancel(ctx context.Context, chat *tele.Chat, user *tele.User) error {
+	challenge, err := s.captchaRepo.FindPending(ctx, s.db, chat.ID, user.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := s.captchaRepo.Resolve(ctx, s.db, challenge.ID, common.CaptchaFailed); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	s.deleteMessage(challenge)
+	return nil
+}
+
+// KickExpired kicks the members whose challenge timed out
+func (s *CaptchaService) KickExpired(ctx context.Context) error {
+	challenges, err := s.captchaRepo.FindExpired(ctx, s.db, time.Now(), captchaBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, challenge := range challenges {
+		// a member answering meanwhile resolved the challenge already
+		if err := s.resolve(ctx, challenge, common.CaptchaFailed); err != nil && !errors.Is(err, ErrCaptchaNotFound) {
+			s.log.Error("failed to kick member with expired captcha",
+				logger.Int64("group_id", challenge.GroupID),
+				logger.Int64("user_id", challenge.UserID),
+				logger.Error(err))
+		}
+	}
+	return nil
+}
+
+// Watch kicks the members with expired challenges every interval until ctx is done
+func (s *CaptchaService) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.KickExpired(ctx); err != nil {
+				s.log.Error("failed to kick members with expired captcha", logger.Error(err))
+			}
+		}
+	}
+}
+
+// resolve claims the pending challenge before acting on telegram, so the answer
+// and the expiry worker never both act on the same member
+func (s *CaptchaService) resolve(ctx context.Context, challenge *model.CaptchaChallenge, status common.CaptchaStatus) error {
+	if err := s.captchaRepo.Resolve(ctx, s.db, challenge.ID, status); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrCaptchaNotFound
+		}
+		return err
+	}
+	s.deleteMessage(challenge)
+
+	chat := &tele.Chat{ID: challenge.GroupID}
+	user := &tele.User{ID: challenge.UserID}
+	if status == common.CaptchaPassed {
+		if err := s.bot.Restrict(chat, &tele.ChatMember{User: user, Rights: tele.NoRestrictions()}); err != nil {
+			return fmt.Errorf("failed to lift captcha restriction: %w", err)
+		}
+		return nil
+	}
+
+	// kick without leaving a ban, the member can join again and retry
+	if err := s.bot.Ban(chat, &tele.ChatMember{User: user}); err != nil {
+		return fmt.Errorf("failed to kick member failing captcha: %w", err)
+	}
+	return s.bot.Unban(chat, user, true)
+}
+
+func (s *CaptchaService) deleteMessage(challenge *model.CaptchaChallenge) {
+	if challenge.MessageID == 0 {
+		return
+	}
+	err := s.bot.Delete(&tele.StoredMessage{
+		MessageID: strconv.Itoa(challenge.MessageID),
+		ChatID:    challenge.GroupID,
+	})
+	if err != nil {
+		s.log.Info("failed to delete captcha message",
+			logger.Int64("group_id", challenge.GroupID),
+			logger.Int("message_id", challenge.MessageID),
+			logger.Error(err))
+	}
+}
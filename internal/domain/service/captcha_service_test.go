@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"path"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type MockCaptchaChallengeRepository struct {
+	mock.Mock
+}
+
+func (m *MockCaptchaChallengeRepository) Upsert(ctx context.Context, tx *gorm.DB, challenge *model.CaptchaChallenge) error {
+	args := m.Called(ctx, tx, challenge)
+	return args.Error(0)
+}
+
+func (m *MockCaptchaChallengeRepository) FindPending(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) (*model.CaptchaChallenge, error) {
+	args := m.Called(ctx, tx, groupId, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CaptchaChallenge), args.Error(1)
+}
+
+func (m *MockCaptchaChallengeRepository) FindExpired(ctx context.Context, tx *gorm.DB, before time.Time, limit int) ([]*model.CaptchaChallenge, error) {
+	args := m.Called(ctx, tx, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.CaptchaChallenge), args.Error(1)
+}
+
+func (m *MockCaptchaChallengeRepository) Resolve(ctx context.Context, tx *gorm.DB, id int64, status common.CaptchaStatus) error {
+	args := m.Called(ctx, tx, id, status)
+	return args.Error(0)
+}
+
+// telegramStub records the bot api methods called and answers them with their result, true by default
+type telegramStub struct {
+	mu      sync.Mutex
+	methods []string
+}
+
+func (s *telegramStub) called() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.methods)
+}
+
+func newTestBot(t *testing.T, results map[string]string) (*tele.Bot, *telegramStub) {
+	stub := &telegramStub{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := path.Base(r.URL.Path)
+		stub.mu.Lock()
+		stub.methods = append(stub.methods, method)
+		stub.mu.Unlock()
+		result, ok := results[method]
+		if !ok {
+			result = "true"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"result":` + result + `}`))
+	}))
+	t.Cleanup(server.Close)
+
+	bot, err := tele.NewBot(tele.Settings{URL: server.URL, Token: "token", Offline: true})
+	require.NoError(t, err)
+	return bot, stub
+}
+
+func newTestCaptchaService(t *testing.T, captchaRepo repository.CaptchaChallengeRepository) (*CaptchaService, *telegramStub) {
+	bot, stub := newTestBot(t, nil)
+	return &CaptchaService{
+		log:         logger.NewLogger(),
+		bot:         bot,
+		cfg:         &config.TelegramConfig{},
+		captchaRepo: captchaRepo,
+	}, stub
+}
+
+func TestCaptchaService_NewQuestion(t *testing.T) {
+	service := &CaptchaService{}
+	for i := 0; i < 100; i++ {
+		question := service.NewQuestion()
+
+		assert.GreaterOrEqual(t, question.A, 1)
+		assert.LessOrEqual(t, question.A, 9)
+		assert.GreaterOrEqual(t, question.B, 1)
+		assert.LessOrEqual(t, question.B, 9)
+		require.Len(t, question.Options, captchaOptionCount)
+		assert.Contains(t, question.Options, question.A+question.B)
+		for j, option := range question.Options {
+			assert.GreaterOrEqual(t, option, 1)
+			assert.LessOrEqual(t, option, 18)
+			assert.NotContains(t, question.Options[j+1:], option)
+		}
+	}
+}
+
+func TestCaptchaService_Answer(t *testing.T) {
+	chat := &tele.Chat{ID: -100}
+	user := &tele.User{ID: 42}
+	tests := []struct {
+		name      string
+		expected  int
+		answer    int
+		expireAt  time.Time
+		resolveTo common.CaptchaStatus
+		// resolveErr the challenge resolved meanwhile by the expiry worker
+		resolveErr error
+		passed     bool
+		err        error
+		methods    []string
+	}{
+		{
+			name:      "right sum releases the member",
+			expected:  7,
+			answer:    7,
+			expireAt:  time.Now().Add(time.Minute),
+			resolveTo: common.CaptchaPassed,
+			passed:    true,
+			methods:   []string{"deleteMessage", "restrictChatMember"},
+		},
+		{
+			name:      "wrong sum kicks the member",
+			expected:  7,
+			answer:    8,
+			expireAt:  time.Now().Add(time.Minute),
+			resolveTo: common.CaptchaFailed,
+			methods:   []string{"deleteMessage", "kickChatMember", "unbanChatMember"},
+		},
+		{
+			name:      "rules button answers 0",
+			answer:    0,
+			expireAt:  time.Now().Add(time.Minute),
+			resolveTo: common.CaptchaPassed,
+			passed:    true,
+			methods:   []string{"deleteMessage", "restrictChatMember"},
+		},
+		{
+			name:      "right answer after the timeout kicks the member",
+			expected:  7,
+			answer:    7,
+			expireAt:  time.Now().Add(-time.Second),
+			resolveTo: common.CaptchaFailed,
+			methods:   []string{"deleteMessage", "kickChatMember", "unbanChatMember"},
+		},
+		{
+			name:       "answer racing the expiry worker leaves the member alone",
+			expected:   7,
+			answer:     7,
+			expireAt:   time.Now().Add(time.Minute),
+			resolveTo:  common.CaptchaPassed,
+			resolveErr: repository.ErrRecordNotFound,
+			passed:     true,
+			err:        ErrCaptchaNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captchaRepo := new(MockCaptchaChallengeRepository)
+			challenge := &model.CaptchaChallenge{ID: 3, GroupID: chat.ID, UserID: user.ID, MessageID: 11, Answer: tt.expected,
+				Status: common.CaptchaPending, ExpireAt: tt.expireAt}
+			captchaRepo.On("FindPending", mock.Anything, mock.Anything, chat.ID, user.ID).Return(challenge, nil)
+			captchaRepo.On("Resolve", mock.Anything, mock.Anything, int64(3), tt.resolveTo).Return(tt.resolveErr)
+			service, stub := newTestCaptchaService(t, captchaRepo)
+
+			passed, err := service.Answer(context.Background(), chat, user, tt.answer)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.passed, passed)
+			assert.Equal(t, tt.methods, stub.called())
+			captchaRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCaptchaService_AnswerWithoutChallenge(t *testing.T) {
+	dbErr := errors.New("connection reset")
+	for _, findErr := range []error{repository.ErrRecordNotFound, dbErr} {
+		captchaRepo := new(MockCaptchaChallengeRepository)
+		captchaRepo.On("FindPending", mock.Anything, mock.Anything, int64(-100), int64(42)).Return(nil, findErr)
+		service, stub := newTestCaptchaService(t, captchaRepo)
+
+		passed, err := service.Answer(context.Background(), &tele.Chat{ID: -100}, &tele.User{ID: 42}, 7)
+
+		assert.False(t, passed)
+		if errors.Is(findErr, repository.ErrRecordNotFound) {
+			assert.ErrorIs(t, err, ErrCaptchaNotFound)
+		} else {
+			assert.ErrorIs(t, err, dbErr)
+		}
+		assert.Empty(t, stub.called())
+		captchaRepo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestCaptchaService_KickExpired(t *testing.T) {
+	captchaRepo := new(MockCaptchaChallengeRepository)
+	captchaRepo.On("FindExpired", mock.Anything, mock.Anything, mock.Anything, captchaBatchSize).Return([]*model.CaptchaChallenge{
+		{ID: 1, GroupID: -100, UserID: 41},
+		{ID: 2, GroupID: -100, UserID: 42},
+	}, nil)
+	captchaRepo.On("Resolve", mock.Anything, mock.Anything, int64(1), common.CaptchaFailed).Return(nil)
+	// the member answered between the lookup and the kick
+	captchaRepo.On("Resolve", mock.Anything, mock.Anything, int64(2), common.CaptchaFailed).Return(repository.ErrRecordNotFound)
+	service, stub := newTestCaptchaService(t, captchaRepo)
+
+	require.NoError(t, service.KickExpired(context.Background()))
+
+	assert.Equal(t, []string{"kickChatMember", "unbanChatMember"}, stub.called())
+	captchaRepo.AssertExpectations(t)
+}
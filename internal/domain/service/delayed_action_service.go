@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+	"time"
+)
+
+const (
+	delayedActionBatchSize   = 100
+	delayedActionMaxAttempts = 5
+	// delayedActionLease time an action is held by a worker before another run may retry it
+	delayedActionLease = time.Minute
+	maxLastErrorLength = 255
+)
+
+// DelayedActionHandler runs a due action, an error retries it until the attempts are exhausted
+type DelayedActionHandler func(ctx context.Context, action *model.DelayedAction) error
+
+// DelayedActionService queues the timed bot actions in database so they survive a restart,
+// the worker runs them once due with the handler registered for their type
+type DelayedActionService struct {
+	log        logger.Logger
+	db         *gorm.DB
+	bot        *tele.Bot
+	actionRepo repository.DelayedActionRepository
+	handlers   map[common.DelayedActionType]DelayedActionHandler
+}
+
+func NewDelayedActionService(bot *tele.Bot, db *gorm.DB, log logger.Logger) *DelayedActionService {
+	s := &DelayedActionService{
+		log:        log,
+		db:         db,
+		bot:        bot,
+		actionRepo: repository.NewDelayedActionRepository(db, log),
+		handlers:   make(map[common.DelayedActionType]DelayedActionHandler),
+	}
+	s.Register(common.DelayedDeleteMessage, s.deleteMessage)
+	s.Register(common.DelayedUnmute, s.unmute)
+	return s
+}
+
+// Register sets the handler of the action type, it has to be called before the worker starts
+func (s *DelayedActionService) Register(actionType common.DelayedActionType, handler DelayedActionHandler) {
+	s.handlers[actionType] = handler
+}
+
+// ScheduleDeletion deletes the message after delay
+func (s *DelayedActionService) ScheduleDeletion(ctx context.Context, msg *tele.Message, delay time.Duration) error {
+	return s.schedule(ctx, &model.DelayedAction{
+		Type:      common.DelayedDeleteMessage,
+		ChatID:    msg.Chat.ID,
+		MessageID: msg.ID,
+		RunAt:     time.Now().Add(delay),
+	})
+}
+
+// ScheduleUnmute lifts at runAt the restriction of the user in the chat, restrictedUntil is the end of the
+// restriction as telegram reports it, 0 for forever. A restriction set since by someone else is left as is
+func (s *DelayedActionService) ScheduleUnmute(ctx context.Context, chatId int64, userId int64, runAt time.Time, restrictedUntil int64) error {
+	return s.schedule(ctx, &model.DelayedAction{
+		Type:    common.DelayedUnmute,
+		ChatID:  chatId,
+		UserID:  userId,
+		Payload: strconv.FormatInt(restrictedUntil, 10),
+		RunAt:   runAt,
+	})
+}
+
+// ScheduleRevocation revokes the invite link of the chat at runAt
+func (s *DelayedActionService) ScheduleRevocation(ctx context.Context, chatId int64, inviteLink string, runAt time.Time) error {
+	return s.schedule(ctx, &model.DelayedAction{
+		Type:    common.DelayedRevokeInviteLink,
+		ChatID:  chatId,
+		Payload: inviteLink,
+		RunAt:   runAt,
+	})
+}
+
+// RunDue runs the actions due now, an action failing delayedActionMaxAttempts times is given up
+func (s *DelayedActionService) RunDue(ctx context.Context) error {
+	var actions []*model.DelayedAction
+	now := time.Now()
+	err := database.WithTransaction(s.db, func(tx *gorm.DB) error {
+		var err error
+		actions, err = s.actionRepo.FindDue(ctx, tx, now, delayedActionBatchSize)
+		if err != nil {
+			return err
+		}
+		ids := make([]int64, 0, len(actions))
+		for _, action := range actions {
+			ids = append(ids, action.ID)
+		}
+		// hold the actions so a crash in the middle of the run retries them after the lease
+		return s.actionRepo.Postpone(ctx, tx, ids, now.Add(delayedActionLease))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, action := range actions {
+		s.run(ctx, action)
+	}
+	return nil
+}
+
+// Watch runs the due actions every interval until ctx is done, including the ones queued before a restart
+func (s *DelayedActionService) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RunDue(ctx); err != nil {
+				s.log.Error("failed to run delayed actions", logger.Error(err))
+			}
+		}
+	}
+}
+
+func (s *DelayedActionService) schedule(ctx context.Context, action *model.DelayedAction) error {
+	action.Status = common.DelayedActionPending
+	return s.actionRepo.Create(ctx, s.db, action)
+}
+
+func (s *DelayedActionService) run(ctx context.Context, action *model.DelayedAction) {
+	handler, ok := s.handlers[action.Type]
+	if !ok {
+		s.finish(ctx, action, common.DelayedActionFailed, fmt.Errorf("no handler for delayed action type %q", action.Type))
+		return
+	}
+
+	err := handler(ctx, action)
+	if err == nil {
+		s.finish(ctx, action, common.DelayedActionDone, nil)
+		return
+	}
+	s.log.Error("failed to run delayed action",
+		logger.Int64("id", action.ID),
+		logger.String("type", string(action.Type)),
+		logger.Int("attempt", action.Attempts+1),
+		logger.Error(err))
+	// the lease set in RunDue is the backoff of the next attempt
+	if action.Attempts+1 >= delayedActionMaxAttempts {
+		s.finish(ctx, action, common.DelayedActionFailed, err)
+	}
+}
+
+func (s *DelayedActionService) finish(ctx context.Context, action *model.DelayedAction, status common.DelayedActionStatus, cause error) {
+	var lastError string
+	if cause != nil {
+		lastError = truncate(cause.Error(), maxLastErrorLength)
+	}
+	if err := s.actionRepo.Finish(ctx, s.db, action.ID, status, lastError); err != nil {
+		s.log.Error("failed to finish delayed action",
+			logger.Int64("id", action.ID),
+			logger.String("status", string(status)),
+			logger.Error(err))
+	}
+}
+
+func (s *DelayedActionService) deleteMessage(ctx context.Context, action *model.DelayedAction) error {
+	err := s.bot.Delete(&tele.StoredMessage{
+		MessageID: strconv.Itoa(action.MessageID),
+		ChatID:    action.ChatID,
+	})
+	// deleted by someone else already
+	if errors.Is(err, tele.ErrNotFoundToDelete) {
+		return nil
+	}
+	return err
+}
+
+// unmute lifts the restriction unless the user left, was banned or was restricted again meanwhile,
+// e.g. by an admin or by the captcha after joining again
+func (s *DelayedActionService) unmute(ctx context.Context, action *model.DelayedAction) error {
+	chat := &tele.Chat{ID: action.ChatID}
+	user := &tele.User{ID: action.UserID}
+	member, err := s.bot.ChatMemberOf(chat, user)
+	if err != nil {
+		return err
+	}
+	if member.Role != tele.Restricted {
+		return nil
+	}
+	restrictedUntil, err := strconv.ParseInt(action.Payload, 10, 64)
+	if err != nil || member.RestrictedUntil != restrictedUntil {
+		s.log.Info("restriction changed since the mute, unmute skipped",
+			logger.Int64("id", action.ID),
+			logger.Int64("chat_id", action.ChatID),
+			logger.Int64("user_id", action.UserID))
+		return nil
+	}
+	return s.bot.Restrict(chat, &tele.ChatMember{
+		User:   user,
+		Rights: tele.NoRestrictions(),
+	})
+}
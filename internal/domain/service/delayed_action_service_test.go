@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database/databasetest"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"testing"
+	"time"
+)
+
+type MockDelayedActionRepository struct {
+	mock.Mock
+}
+
+func (m *MockDelayedActionRepository) Create(ctx context.Context, tx *gorm.DB, action *model.DelayedAction) error {
+	args := m.Called(ctx, tx, action)
+	return args.Error(0)
+}
+
+func (m *MockDelayedActionRepository) FindDue(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]*model.DelayedAction, error) {
+	args := m.Called(ctx, tx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.DelayedAction), args.Error(1)
+}
+
+func (m *MockDelayedActionRepository) Postpone(ctx context.Context, tx *gorm.DB, ids []int64, runAt time.Time) error {
+	args := m.Called(ctx, tx, ids, runAt)
+	return args.Error(0)
+}
+
+func (m *MockDelayedActionRepository) Finish(ctx context.Context, tx *gorm.DB, id int64, status common.DelayedActionStatus, lastError string) error {
+	args := m.Called(ctx, tx, id, status, lastError)
+	return args.Error(0)
+}
+
+func TestDelayedActionService_RunDue(t *testing.T) {
+	const (
+		succeeding common.DelayedActionType = "succeeding"
+		failing    common.DelayedActionType = "failing"
+	)
+	actionRepo := new(MockDelayedActionRepository)
+	actionRepo.On("FindDue", mock.Anything, mock.Anything, mock.Anything, delayedActionBatchSize).Return([]*model.DelayedAction{
+		{ID: 1, Type: succeeding},
+		{ID: 2, Type: failing},
+		{ID: 3, Type: failing, Attempts: delayedActionMaxAttempts - 1},
+		{ID: 4, Type: "unknown"},
+	}, nil)
+	var leasedUntil time.Time
+	actionRepo.On("Postpone", mock.Anything, mock.Anything, []int64{1, 2, 3, 4}, mock.Anything).
+		Run(func(args mock.Arguments) { leasedUntil = args.Get(3).(time.Time) }).
+		Return(nil)
+	actionRepo.On("Finish", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	service := &DelayedActionService{
+		log:        logger.NewLogger(),
+		db:         databasetest.NewTxDB(t),
+		actionRepo: actionRepo,
+		handlers:   make(map[common.DelayedActionType]DelayedActionHandler),
+	}
+	service.Register(succeeding, func(context.Context, *model.DelayedAction) error { return nil })
+	service.Register(failing, func(context.Context, *model.DelayedAction) error { return errors.New("bad request") })
+
+	before := time.Now()
+	require.NoError(t, service.RunDue(context.Background()))
+
+	// a crash before the actions finish retries them once the lease is over
+	assert.WithinDuration(t, before.Add(delayedActionLease), leasedUntil, time.Second)
+	actionRepo.AssertCalled(t, "Finish", mock.Anything, mock.Anything, int64(1), common.DelayedActionDone, "")
+	// the failing action is retried after the lease until its attempts are exhausted
+	actionRepo.AssertNotCalled(t, "Finish", mock.Anything, mock.Anything, int64(2), mock.Anything, mock.Anything)
+	actionRepo.AssertCalled(t, "Finish", mock.Anything, mock.Anything, int64(3), common.DelayedActionFailed, "bad request")
+	actionRepo.AssertCalled(t, "Finish", mock.Anything, mock.Anything, int64(4), common.DelayedActionFailed,
+		`no handler for delayed action type "unknown"`)
+}
+
+func TestDelayedActionService_RunDueError(t *testing.T) {
+	actionRepo := new(MockDelayedActionRepository)
+	actionRepo.On("FindDue", mock.Anything, mock.Anything, mock.Anything, delayedActionBatchSize).Return(nil, databasetest.ErrNoDatabase)
+	service := &DelayedActionService{
+		log:        logger.NewLogger(),
+		db:         databasetest.NewTxDB(t),
+		actionRepo: actionRepo,
+		handlers:   make(map[common.DelayedActionType]DelayedActionHandler),
+	}
+
+	assert.ErrorIs(t, service.RunDue(context.Background()), databasetest.ErrNoDatabase)
+	actionRepo.AssertNotCalled(t, "Postpone", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	actionRepo.AssertNotCalled(t, "Finish", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDelayedActionService_Unmute(t *testing.T) {
+	tests := []struct {
+		name    string
+		member  string
+		payload string
+		methods []string
+	}{
+		{
+			name:    "restriction set by the mute is lifted",
+			member:  `{"user":{"id":42},"status":"restricted","until_date":0}`,
+			payload: "0",
+			methods: []string{"getChatMember", "restrictChatMember"},
+		},
+		{
+			name:    "restriction set since by someone else is kept",
+			member:  `{"user":{"id":42},"status":"restricted","until_date":1900000000}`,
+			payload: "0",
+			methods: []string{"getChatMember"},
+		},
+		{
+			name:    "member no longer restricted",
+			member:  `{"user":{"id":42},"status":"member"}`,
+			payload: "0",
+			methods: []string{"getChatMember"},
+		},
+		{
+			name:    "unmute queued without the restriction end is skipped",
+			member:  `{"user":{"id":42},"status":"restricted","until_date":0}`,
+			methods: []string{"getChatMember"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, stub := newTestBot(t, map[string]string{"getChatMember": tt.member})
+			service := &DelayedActionService{log: logger.NewLogger(), bot: bot}
+
+			err := service.unmute(context.Background(), &model.DelayedAction{
+				ID: 1, Type: common.DelayedUnmute, ChatID: -100, UserID: 42, Payload: tt.payload,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.methods, stub.called())
+		})
+	}
+}
+
+func TestPenaltyService_ApplyQueuesLongUnmutes(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		queued   bool
+	}{
+		{name: "mute lifted by telegram", duration: 24 * time.Hour},
+		{name: "mute telegram keeps forever", duration: maxRestrictDuration + time.Hour, queued: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, stub := newTestBot(t, nil)
+			actionRepo := new(MockDelayedActionRepository)
+			actionRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			service := &PenaltyService{
+				log:   logger.NewLogger(),
+				bot:   bot,
+				queue: &DelayedActionService{log: logger.NewLogger(), actionRepo: actionRepo},
+			}
+
+			err := service.Apply(&tele.Chat{ID: -100}, &tele.User{ID: 42}, &Penalty{Action: common.PenaltyMute, Duration: tt.duration})
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"restrictChatMember"}, stub.called())
+			if !tt.queued {
+				actionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.Len(t, actionRepo.Calls, 1)
+			action := actionRepo.Calls[0].Arguments.Get(2).(*model.DelayedAction)
+			assert.Equal(t, common.DelayedUnmute, action.Type)
+			assert.Equal(t, "0", action.Payload)
+			assert.WithinDuration(t, time.Now().Add(tt.duration), action.RunAt, time.Second)
+		})
+	}
+}
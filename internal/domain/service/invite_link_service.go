@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultInviteLinkTTL = 24 * time.Hour
+	revokeBatchSize      = 100
+)
+
+var (
+	ErrInviteLinkNotOwner   = errors.New("uid is not bound to the social user")
+	ErrInviteLinkNotAllowed = errors.New("binding is not allowed to join the groups")
+)
+
+// InviteLinkService issues the group invite links and tracks them until they are used or revoked
+type InviteLinkService struct {
+	log                logger.Logger
+	db                 *gorm.DB
+	bot                *tele.Bot
+	cfg                *config.TelegramConfig
+	inviteLinkRepo     repository.InviteLinkRepository
+	tradingBindingRepo repository.CustomerTradingBindingRepository
+	socialBindingRepo  repository.CustomerSocialBindingRepository
+	incidentRepo       repository.LinkSharingIncidentRepository
+	queue              *DelayedActionService
+}
+
+// LinkSharing a join through an invite link issued to another customer
+type LinkSharing struct {
+	Link  *model.InviteLink
+	Owner *model.CustomerSocialBinding
+}
+
+func NewInviteLinkService(cfg *config.TelegramConfig, bot *tele.Bot, db *gorm.DB, log logger.Logger,
+	queue *DelayedActionService) *InviteLinkService {
+	s := &InviteLinkService{
+		log:                log,
+		db:                 db,
+		bot:                bot,
+		cfg:                cfg,
+		inviteLinkRepo:     repository.NewInviteLinkRepository(db, log),
+		tradingBindingRepo: repository.NewCustomerTradingRepository(db, log),
+		socialBindingRepo:  repository.NewCustomerSocialRepository(db, log),
+		incidentRepo:       repository.NewLinkSharingIncidentRepository(db, log),
+		queue:              queue,
+	}
+	queue.Register(common.DelayedRevokeInviteLink, s.revokeQueued)
+	return s
+}
+
+// Issue creates a join request link to every group for the customer bound to uid
+func (s *InviteLinkService) Issue(ctx context.Context, uid string) ([]string, error) {
+	info, err := s.tradingBindingRepo.FindTradingBindingByUid(ctx, s.db, uid)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, uid, info.Customer.ID)
+}
+
+// Reissue revokes the outstanding links of the customer bound to uid and issues new ones,
+// only the social user owning an active binding can request them
+func (s *InviteLinkService) Reissue(ctx context.Context, uid string, userId string) ([]string, error) {
+	info, err := s.tradingBindingRepo.FindTradingBindingByUid(ctx, s.db, uid)
+	if err != nil {
+		return nil, err
+	}
+	if info.SocialAccountInfo.UserID != userId {
+		return nil, ErrInviteLinkNotOwner
+	}
+	if !info.SocialAccountInfo.IsActive || info.SocialAccountInfo.Status == common.Blacklisted {
+		return nil, ErrInviteLinkNotAllowed
+	}
+
+	outstanding, err := s.inviteLinkRepo.FindOutstandingByCustomerId(ctx, s.db, info.Customer.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.revoke(ctx, outstanding)
+	return s.issue(ctx, uid, info.Customer.ID)
+}
+
+// MarkUsed records the user who joined through the link, links not issued by the bot are ignored
+func (s *InviteLinkService) MarkUsed(ctx context.Context, inviteLink string, userId string) error {
+	err := s.inviteLinkRepo.MarkUsed(ctx, s.db, inviteLink, userId)
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
+
+// DetectSharing matches the user joining through inviteLink against the customer the link was issued to,
+// nil is returned for the owner and for links not issued by the bot
+func (s *InviteLinkService) DetectSharing(ctx context.Context, inviteLink string, userId string) (*LinkSharing, error) {
+	link, err := s.inviteLinkRepo.FindByLink(ctx, s.db, inviteLink)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	owner, err := s.socialBindingRepo.FindSocialBindingByCustomerId(ctx, s.db, link.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	if owner.UserID == userId {
+		return nil, nil
+	}
+	return &LinkSharing{Link: link, Owner: owner}, nil
+}
+
+// RecordSharing revokes the shared link and records the incident against the link owner
+func (s *InviteLinkService) RecordSharing(ctx context.Context, sharing *LinkSharing, intruder *tele.User, removed bool) error {
+	s.revoke(ctx, []*model.InviteLink{sharing.Link})
+	return s.incidentRepo.Create(ctx, s.db, &model.LinkSharingIncident{
+		CustomerID:       sharing.Link.CustomerID,
+		InviteLinkID:     sharing.Link.ID,
+		GroupID:          sharing.Link.GroupID,
+		OwnerUserID:      sharing.Owner.UserID,
+		IntruderUserID:   strconv.FormatInt(intruder.ID, 10),
+		IntruderUsername: intruder.Username,
+		Removed:          removed,
+	})
+}
+
+// RevokeExpired revokes the unused links older than the configured ttl, it sweeps the links
+// whose revocation was not queued, the other ones are revoked by the delayed action worker
+func (s *InviteLinkService) RevokeExpired(ctx context.Context) error {
+	links, err := s.inviteLinkRepo.FindExpired(ctx, s.db, time.Now(), revokeBatchSize)
+	if err != nil {
+		return err
+	}
+	s.revoke(ctx, links)
+	return nil
+}
+
+// revokeQueued revokes the link of the delayed action unless it was used or revoked meanwhile
+func (s *InviteLinkService) revokeQueued(ctx context.Context, action *model.DelayedAction) error {
+	link, err := s.inviteLinkRepo.FindByLink(ctx, s.db, action.Payload)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if link.Status != common.InviteLinkUnused {
+		return nil
+	}
+	s.revoke(ctx, []*model.InviteLink{link})
+	return nil
+}
+
+func (s *InviteLinkService) issue(ctx context.Context, uid string, customerId string) ([]string, error) {
+	groupIds, err := s.cfg.GroupIds()
+	if err != nil {
+		return nil, err
+	}
+
+	expireAt := time.Now().Add(s.ttl())
+	links := make([]string, 0, len(groupIds))
+	for _, groupId := range groupIds {
+		// members join through a join request which is approved for verified customers only
+		link, err := s.bot.CreateInviteLink(&tele.Chat{ID: groupId}, &tele.ChatInviteLink{
+			Name:           uid,
+			ExpireUnixtime: expireAt.Unix(),
+			JoinRequest:    true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create invite link for group_id=%d: %w", groupId, err)
+		}
+		if link == nil || link.InviteLink == "" {
+			return nil, fmt.Errorf("empty invite link created for group_id=%d", groupId)
+		}
+
+		_, err = s.inviteLinkRepo.Create(ctx, s.db, &model.InviteLink{
+			CustomerID: customerId,
+			GroupID:    groupId,
+			InviteLink: link.InviteLink,
+			Status:     common.InviteLinkUnused,
+			ExpireAt:   expireAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		// the link expires on telegram anyway, a failure only leaves it unused in database until the sweep
+		if err := s.queue.ScheduleRevocation(ctx, groupId, link.InviteLink, expireAt); err != nil {
+			s.log.Error("failed to queue invite link revocation",
+				logger.Int64("group_id", groupId),
+				logger.Error(err))
+		}
+		links = append(links, link.InviteLink)
+	}
+	return links, nil
+}
+
+// revoke revokes the links on telegram, links failing to revoke are left unused
+// unless they are expired already, the revocation queued at their expiry retries them
+func (s *InviteLinkService) revoke(ctx context.Context, links []*model.InviteLink) {
+	now := time.Now()
+	revoked := make([]int64, 0, len(links))
+	for _, link := range links {
+		if _, err := s.bot.RevokeInviteLink(&tele.Chat{ID: link.GroupID}, link.InviteLink); err != nil {
+			s.log.Error("failed to revoke invite link",
+				logger.Int64("id", link.ID),
+				logger.Int64("group_id", link.GroupID),
+				logger.Error(err))
+			if link.ExpireAt.After(now) {
+				continue
+			}
+		}
+		revoked = append(revoked, link.ID)
+	}
+
+	if err := s.inviteLinkRepo.MarkRevoked(ctx, s.db, revoked); err != nil {
+		s.log.Error("failed to mark invite links revoked",
+			logger.Any("ids", revoked),
+			logger.Error(err))
+	}
+}
+
+func (s *InviteLinkService) ttl() time.Duration {
+	if s.cfg.InviteLinkTTL <= 0 {
+		return defaultInviteLinkTTL
+	}
+	return s.cfg.InviteLinkTTL
+}
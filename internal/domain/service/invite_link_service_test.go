@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"testing"
+)
+
+// MockInviteLinkRepository only the lookup of the link is used by DetectSharing
+type MockInviteLinkRepository struct {
+	mock.Mock
+	repository.InviteLinkRepository
+}
+
+func (m *MockInviteLinkRepository) FindByLink(ctx context.Context, tx *gorm.DB, inviteLink string) (*model.InviteLink, error) {
+	args := m.Called(ctx, tx, inviteLink)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.InviteLink), args.Error(1)
+}
+
+// MockCustomerSocialBindingRepository only the owner of the link is looked up by DetectSharing
+type MockCustomerSocialBindingRepository struct {
+	mock.Mock
+	repository.CustomerSocialBindingRepository
+}
+
+func (m *MockCustomerSocialBindingRepository) FindSocialBindingByCustomerId(ctx context.Context, tx *gorm.DB, customerId string) (*model.CustomerSocialBinding, error) {
+	args := m.Called(ctx, tx, customerId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CustomerSocialBinding), args.Error(1)
+}
+
+func TestInviteLinkService_DetectSharing(t *testing.T) {
+	link := &model.InviteLink{ID: 9, CustomerID: "customer-a", GroupID: -100, InviteLink: "https://t.me/+a"}
+	owner := &model.CustomerSocialBinding{CustomerID: "customer-a", UserID: "101"}
+	dbErr := errors.New("connection reset")
+	tests := []struct {
+		name     string
+		userId   string
+		link     *model.InviteLink
+		linkErr  error
+		ownerErr error
+		sharing  bool
+		err      error
+	}{
+		{name: "owner joining with their own link", userId: "101", link: link},
+		{name: "another user joining with the link", userId: "202", link: link, sharing: true},
+		{name: "link not issued by the bot", userId: "202", linkErr: repository.ErrRecordNotFound},
+		{name: "link lookup failing", userId: "202", linkErr: dbErr, err: dbErr},
+		{name: "owner lookup failing", userId: "202", link: link, ownerErr: dbErr, err: dbErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inviteLinkRepo := new(MockInviteLinkRepository)
+			socialBindingRepo := new(MockCustomerSocialBindingRepository)
+			if tt.link != nil {
+				inviteLinkRepo.On("FindByLink", mock.Anything, mock.Anything, link.InviteLink).Return(tt.link, nil)
+			} else {
+				inviteLinkRepo.On("FindByLink", mock.Anything, mock.Anything, link.InviteLink).Return(nil, tt.linkErr)
+			}
+			if tt.ownerErr != nil {
+				socialBindingRepo.On("FindSocialBindingByCustomerId", mock.Anything, mock.Anything, "customer-a").Return(nil, tt.ownerErr)
+			} else {
+				socialBindingRepo.On("FindSocialBindingByCustomerId", mock.Anything, mock.Anything, "customer-a").Return(owner, nil)
+			}
+			service := &InviteLinkService{
+				log:               logger.NewLogger(),
+				inviteLinkRepo:    inviteLinkRepo,
+				socialBindingRepo: socialBindingRepo,
+			}
+
+			sharing, err := service.DetectSharing(context.Background(), link.InviteLink, tt.userId)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Nil(t, sharing)
+				return
+			}
+			require.NoError(t, err)
+			if !tt.sharing {
+				assert.Nil(t, sharing)
+				return
+			}
+			require.NotNil(t, sharing)
+			assert.Equal(t, link, sharing.Link)
+			assert.Equal(t, owner, sharing.Owner)
+		})
+	}
+}
@@ -1,3 +1,37 @@
 package service
 
-// TODO after migrate db
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+type JoinService struct {
+	log               logger.Logger
+	db                *gorm.DB
+	socialBindingRepo repository.CustomerSocialBindingRepository
+}
+
+func NewJoinService(db *gorm.DB, log logger.Logger) *JoinService {
+	return &JoinService{
+		log:               log,
+		db:                db,
+		socialBindingRepo: repository.NewCustomerSocialRepository(db, log),
+	}
+}
+
+// CanJoin reports whether the social user has an active binding that is not blacklisted
+func (j *JoinService) CanJoin(ctx context.Context, platform common.SocialPlatformType, userId string) (bool, error) {
+	binding, err := j.socialBindingRepo.FindByUserId(ctx, j.db, platform.Value(), userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("checking join request failed with user_id=%s, error=%w", userId, err)
+	}
+	return binding.IsActive && binding.Status != common.Blacklisted, nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"sync"
+)
+
+type LanguageService struct {
+	log          logger.Logger
+	db           *gorm.DB
+	languageRepo repository.SocialUserLanguageRepository
+	// overrides caches the /lang choice per user, an empty Lang means the user has no override
+	overrides sync.Map
+}
+
+func NewLanguageService(db *gorm.DB, log logger.Logger) *LanguageService {
+	return &LanguageService{
+		log:          log,
+		db:           db,
+		languageRepo: repository.NewSocialUserLanguageRepository(db, log),
+	}
+}
+
+// Resolve returns the language of the user, the /lang override wins over the language code of the client,
+// an empty Lang is returned when neither is supported so that the localizer fallback applies
+func (l *LanguageService) Resolve(ctx context.Context, platform common.SocialPlatformType, userId string, languageCode string) i18n.Lang {
+	if lang := l.findOverride(ctx, platform, userId); lang != "" {
+		return lang
+	}
+	lang, _ := i18n.ParseLang(languageCode)
+	return lang
+}
+
+// Update stores the /lang override of the user
+func (l *LanguageService) Update(ctx context.Context, platform common.SocialPlatformType, userId string, lang i18n.Lang) error {
+	err := l.languageRepo.Upsert(ctx, l.db, &model.SocialUserLanguage{
+		SocialID: platform.Value(),
+		UserID:   userId,
+		Language: string(lang),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update language of user_id=%s, error=%w", userId, err)
+	}
+	l.overrides.Store(overrideKey(platform, userId), lang)
+	return nil
+}
+
+func (l *LanguageService) findOverride(ctx context.Context, platform common.SocialPlatformType, userId string) i18n.Lang {
+	key := overrideKey(platform, userId)
+	if lang, ok := l.overrides.Load(key); ok {
+		return lang.(i18n.Lang)
+	}
+
+	language, err := l.languageRepo.FindByUserId(ctx, l.db, platform.Value(), userId)
+	if err != nil {
+		if !errors.Is(err, repository.ErrRecordNotFound) {
+			// don't cache on database errors, retry on the next message
+			l.log.Error("failed to find user language",
+				logger.String("user_id", userId),
+				logger.Error(err))
+			return ""
+		}
+		l.overrides.Store(key, i18n.Lang(""))
+		return ""
+	}
+
+	lang, _ := i18n.ParseLang(language.Language)
+	l.overrides.Store(key, lang)
+	return lang
+}
+
+func overrideKey(platform common.SocialPlatformType, userId string) string {
+	return fmt.Sprintf("%d:%s", platform.Value(), userId)
+}
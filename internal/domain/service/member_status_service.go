@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+// MemberStatusChange a membership transition of a user in a managed group
+type MemberStatusChange struct {
+	Platform   common.SocialPlatformType
+	UserId     string
+	GroupId    int64
+	GroupTitle string
+	OldStatus  common.MemberStatus
+	NewStatus  common.MemberStatus
+	InviteLink string
+	ChangedAt  time.Time
+}
+
+// MemberStatusService keeps the group membership of the bound users in sync with telegram
+type MemberStatusService struct {
+	log                logger.Logger
+	db                 *gorm.DB
+	bot                *tele.Bot
+	cfg                *config.TelegramConfig
+	socialBindingRepo  repository.CustomerSocialBindingRepository
+	tradingBindingRepo repository.CustomerTradingBindingRepository
+	membershipRepo     repository.GroupMembershipRepository
+	historyRepo        repository.MemberStatusHistoryRepository
+}
+
+func NewMemberStatusService(cfg *config.TelegramConfig, bot *tele.Bot, db *gorm.DB, log logger.Logger) *MemberStatusService {
+	return &MemberStatusService{
+		log:                log,
+		db:                 db,
+		bot:                bot,
+		cfg:                cfg,
+		socialBindingRepo:  repository.NewCustomerSocialRepository(db, log),
+		tradingBindingRepo: repository.NewCustomerTradingRepository(db, log),
+		membershipRepo:     repository.NewGroupMembershipRepository(db, log),
+		historyRepo:        repository.NewMemberStatusHistoryRepository(db, log),
+	}
+}
+
+// Sync persists the membership change of a bound user, changes of users without a binding are ignored
+func (s *MemberStatusService) Sync(ctx context.Context, change *MemberStatusChange) error {
+	binding, err := s.socialBindingRepo.FindByUserId(ctx, s.db, change.Platform.Value(), change.UserId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("syncing member status failed with user_id=%s, error=%w", change.UserId, err)
+	}
+
+	return database.WithTransaction(s.db, func(tx *gorm.DB) error {
+		err := s.membershipRepo.Upsert(ctx, tx, &model.GroupMembership{
+			CustomerID:   binding.CustomerID,
+			SocialID:     binding.SocialID,
+			UserID:       change.UserId,
+			GroupID:      change.GroupId,
+			GroupTitle:   change.GroupTitle,
+			MemberStatus: change.NewStatus,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = s.historyRepo.Create(ctx, tx, &model.MemberStatusHistory{
+			CustomerID: binding.CustomerID,
+			SocialID:   binding.SocialID,
+			UserID:     change.UserId,
+			GroupID:    change.GroupId,
+			OldStatus:  change.OldStatus,
+			NewStatus:  change.NewStatus,
+			InviteLink: change.InviteLink,
+			ChangedAt:  change.ChangedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		// the binding keeps the status of the latest transition in any of the groups
+		return s.socialBindingRepo.UpdateStatusByCustomerId(ctx, tx, binding.CustomerID, map[string]interface{}{
+			"member_status": change.NewStatus,
+		})
+	})
+}
+
+// GetMemberships returns the membership of the customer bound to uid in every group seen by the bot
+func (s *MemberStatusService) GetMemberships(ctx context.Context, uid string) ([]*model.GroupMembership, error) {
+	info, err := s.tradingBindingRepo.FindTradingBindingByUid(ctx, s.db, uid)
+	if err != nil {
+		return nil, err
+	}
+	return s.membershipRepo.FindByCustomerId(ctx, s.db, info.Customer.ID)
+}
+
+// Resolve looks up the current status of the user in the configured groups,
+// a member of any group wins over left or kicked in the others
+func (s *MemberStatusService) Resolve(userId int64) common.MemberStatus {
+	groupIds, err := s.cfg.GroupIds()
+	if err != nil {
+		s.log.Error("failed to parse group ids", logger.Error(err))
+		return common.Left
+	}
+
+	resolved := common.MemberStatus(common.Left)
+	for _, groupId := range groupIds {
+		member, err := s.bot.ChatMemberOf(&tele.Chat{ID: groupId}, &tele.User{ID: userId})
+		if err != nil || member == nil {
+			continue
+		}
+		status := common.GetMemberStatusFromValue(member.Role)
+		switch status {
+		case common.Creator, common.Administrator, common.Member, common.Restricted:
+			return status
+		case common.Kicked:
+			resolved = status
+		}
+	}
+	return resolved
+}
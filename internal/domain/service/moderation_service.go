@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/moderation"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"sync/atomic"
+	"time"
+)
+
+// ModerationVerdict a rule a message breaks
+type ModerationVerdict struct {
+	Rule   *model.ModerationRule
+	Action common.RuleAction
+	// Shadow the action is only recorded, the message is left untouched
+	Shadow bool
+}
+
+type compiledRule struct {
+	rule    *model.ModerationRule
+	matcher moderation.Matcher
+}
+
+// ruleIndex snapshot of the enabled rules and the trusted users per group
+type ruleIndex struct {
+	rules   map[int64][]*compiledRule
+	trusted map[int64]map[int64]struct{}
+}
+
+// ModerationService evaluates the group messages against the rules stored in database,
+// rules are reloaded periodically so changes made through the admin api apply without a restart
+type ModerationService struct {
+	log         logger.Logger
+	db          *gorm.DB
+	ruleRepo    repository.ModerationRuleRepository
+	trustedRepo repository.TrustedUserRepository
+	logRepo     repository.ModerationLogRepository
+	index       atomic.Pointer[ruleIndex]
+}
+
+func NewModerationService(db *gorm.DB, log logger.Logger) *ModerationService {
+	s := &ModerationService{
+		log:         log,
+		db:          db,
+		ruleRepo:    repository.NewModerationRuleRepository(db, log),
+		trustedRepo: repository.NewTrustedUserRepository(db, log),
+		logRepo:     repository.NewModerationLogRepository(db, log),
+	}
+	s.index.Store(&ruleIndex{})
+	return s
+}
+
+// Reload compiles the enabled rules, rules failing to compile are skipped
+func (s *ModerationService) Reload(ctx context.Context) error {
+	rules, err := s.ruleRepo.FindEnabled(ctx, s.db)
+	if err != nil {
+		return err
+	}
+	trustedUsers, err := s.trustedRepo.FindAll(ctx, s.db, 0)
+	if err != nil {
+		return err
+	}
+
+	index := &ruleIndex{
+		rules:   make(map[int64][]*compiledRule),
+		trusted: make(map[int64]map[int64]struct{}),
+	}
+	for _, rule := range rules {
+		matcher, err := moderation.Compile(rule.MatchType, rule.Pattern)
+		if err != nil {
+			s.log.Error("skipping invalid moderation rule",
+				logger.Int64("id", rule.ID),
+				logger.Error(err))
+			continue
+		}
+		index.rules[rule.GroupID] = append(index.rules[rule.GroupID], &compiledRule{rule: rule, matcher: matcher})
+	}
+	for _, user := range trustedUsers {
+		if index.trusted[user.GroupID] == nil {
+			index.trusted[user.GroupID] = make(map[int64]struct{})
+		}
+		index.trusted[user.GroupID][user.UserID] = struct{}{}
+	}
+	s.index.Store(index)
+	return nil
+}
+
+// Watch reloads the rules every interval until ctx is done
+func (s *ModerationService) Watch(ctx context.Context, interval time.Duration) {
+	if err := s.Reload(ctx); err != nil {
+		s.log.Error("failed to load moderation rules", logger.Error(err))
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Reload(ctx); err != nil {
+				s.log.Error("failed to reload moderation rules", logger.Error(err))
+			}
+		}
+	}
+}
+
+// HasRules reports whether the group is moderated by database rules instead of the configured patterns
+func (s *ModerationService) HasRules(groupId int64) bool {
+	return len(s.index.Load().rules[groupId]) > 0
+}
+
+// IsTrusted reports whether the user bypasses the rules of the group
+func (s *ModerationService) IsTrusted(groupId int64, userId int64) bool {
+	_, ok := s.index.Load().trusted[groupId][userId]
+	return ok
+}
+
+// Evaluate returns the rules of the group and topic the message breaks, by priority, up to the first
+// rule not in shadow mode, so a shadow rule never hides the live rules below it.
+// Nothing is returned for trusted users and messages breaking no rule
+func (s *ModerationService) Evaluate(groupId int64, topicId int, userId int64, msg *moderation.Message) []*ModerationVerdict {
+	if s.IsTrusted(groupId, userId) {
+		return nil
+	}
+	var verdicts []*ModerationVerdict
+	for _, compiled := range s.index.Load().rules[groupId] {
+		if compiled.rule.TopicID != 0 && compiled.rule.TopicID != topicId {
+			continue
+		}
+		if !compiled.matcher.Match(msg) {
+			continue
+		}
+		verdicts = append(verdicts, &ModerationVerdict{
+			Rule:   compiled.rule,
+			Action: compiled.rule.Action,
+			Shadow: compiled.rule.Shadow,
+		})
+		if !compiled.rule.Shadow {
+			break
+		}
+	}
+	return verdicts
+}
+
+// Audit persists a moderation action so it can be reviewed through the admin api
+func (s *ModerationService) Audit(ctx context.Context, entry *model.ModerationLog) error {
+	return s.logRepo.Create(ctx, s.db, entry)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+const (
+	defaultMuteDuration = time.Hour
+	// maxRestrictDuration telegram keeps a longer restriction forever
+	maxRestrictDuration = 366 * 24 * time.Hour
+)
+
+// Penalty action escalated from the strikes of a user
+type Penalty struct {
+	Action   common.PenaltyAction
+	Duration time.Duration
+	Strikes  int
+}
+
+// PenaltyService counts the moderation violations of the users and escalates them
+// from a warning to a mute and a ban as configured
+type PenaltyService struct {
+	log           logger.Logger
+	db            *gorm.DB
+	bot           *tele.Bot
+	cfg           *config.PenaltyConfig
+	violationRepo repository.UserViolationRepository
+	queue         *DelayedActionService
+}
+
+func NewPenaltyService(cfg *config.PenaltyConfig, bot *tele.Bot, db *gorm.DB, log logger.Logger,
+	queue *DelayedActionService) *PenaltyService {
+	return &PenaltyService{
+		log:           log,
+		db:            db,
+		bot:           bot,
+		cfg:           cfg,
+		violationRepo: repository.NewUserViolationRepository(db, log),
+		queue:         queue,
+	}
+}
+
+// Record adds a strike to the user in the group and returns the penalty it escalates to
+func (s *PenaltyService) Record(ctx context.Context, groupId int64, user *tele.User) (*Penalty, error) {
+	var penalty *Penalty
+	err := database.WithTransaction(s.db, func(tx *gorm.DB) error {
+		violation, err := s.violationRepo.FindForUpdate(ctx, tx, groupId, user.ID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		violation.Strikes = s.cfg.Decayed(violation.Strikes, violation.LastViolationAt, now) + 1
+		violation.LastViolationAt = now
+		violation.Username = user.Username
+		if err := s.violationRepo.Save(ctx, tx, violation); err != nil {
+			return err
+		}
+
+		step := s.cfg.Step(violation.Strikes)
+		penalty = &Penalty{
+			Action:   common.PenaltyAction(step.Action),
+			Duration: step.Duration,
+			Strikes:  violation.Strikes,
+		}
+		if penalty.Action == common.PenaltyMute {
+			penalty = s.Mute(penalty, penalty.Duration)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("recording violation failed with group_id=%d, user_id=%d, error=%w", groupId, user.ID, err)
+	}
+	return penalty, nil
+}
+
+// Mute turns the penalty into a mute for duration, the default duration when unset
+func (s *PenaltyService) Mute(penalty *Penalty, duration time.Duration) *Penalty {
+	if duration <= 0 {
+		duration = defaultMuteDuration
+	}
+	// a shorter restriction would last forever
+	duration = max(duration, common.MinMuteSeconds*time.Second)
+	return &Penalty{Action: common.PenaltyMute, Duration: duration, Strikes: penalty.Strikes}
+}
+
+// Apply mutes or bans the user, a warning is left to the caller.
+// The unmute of a mute longer than 366 days is queued as telegram keeps it forever
+func (s *PenaltyService) Apply(chat *tele.Chat, user *tele.User, penalty *Penalty) error {
+	switch penalty.Action {
+	case common.PenaltyMute:
+		until := time.Now().Add(penalty.Duration)
+		err := s.bot.Restrict(chat, &tele.ChatMember{
+			User:            user,
+			Rights:          tele.NoRights(),
+			RestrictedUntil: until.Unix(),
+		})
+		if err != nil {
+			return err
+		}
+		if penalty.Duration <= maxRestrictDuration {
+			return nil
+		}
+		// the user is muted already, telegram reports the restriction as forever
+		if err := s.queue.ScheduleUnmute(context.TODO(), chat.ID, user.ID, until, 0); err != nil {
+			s.log.Error("failed to queue unmute",
+				logger.Int64("chat_id", chat.ID),
+				logger.Int64("user_id", user.ID),
+				logger.Error(err))
+		}
+		return nil
+	case common.PenaltyBan:
+		return s.bot.Ban(chat, &tele.ChatMember{User: user})
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database/databasetest"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"testing"
+	"time"
+)
+
+// MockUserViolationRepository only the strikes of the violating user are read and saved by Record
+type MockUserViolationRepository struct {
+	mock.Mock
+	repository.UserViolationRepository
+}
+
+func (m *MockUserViolationRepository) FindForUpdate(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) (*model.UserViolation, error) {
+	args := m.Called(ctx, tx, groupId, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserViolation), args.Error(1)
+}
+
+func (m *MockUserViolationRepository) Save(ctx context.Context, tx *gorm.DB, violation *model.UserViolation) error {
+	args := m.Called(ctx, tx, violation)
+	return args.Error(0)
+}
+
+func TestPenaltyService_Record(t *testing.T) {
+	penalties := &config.PenaltyConfig{
+		Decay: 24 * time.Hour,
+		Steps: []config.PenaltyStep{
+			{Strikes: 2, Action: "mute", Duration: 10 * time.Minute},
+			{Strikes: 3, Action: "mute", Duration: 10 * time.Second},
+			{Strikes: 4, Action: "ban"},
+		},
+	}
+	now := time.Now()
+	tests := []struct {
+		name     string
+		cfg      *config.PenaltyConfig
+		strikes  int
+		last     time.Time
+		expected Penalty
+	}{
+		{
+			name:     "first violation warns",
+			cfg:      penalties,
+			expected: Penalty{Action: common.PenaltyWarn, Strikes: 1},
+		},
+		{
+			name:     "second violation mutes",
+			cfg:      penalties,
+			strikes:  1,
+			last:     now.Add(-time.Hour),
+			expected: Penalty{Action: common.PenaltyMute, Duration: 10 * time.Minute, Strikes: 2},
+		},
+		{
+			name:     "mute shorter than telegram allows lasts the minimum",
+			cfg:      penalties,
+			strikes:  2,
+			last:     now.Add(-time.Hour),
+			expected: Penalty{Action: common.PenaltyMute, Duration: 30 * time.Second, Strikes: 3},
+		},
+		{
+			name:     "fourth violation bans",
+			cfg:      penalties,
+			strikes:  3,
+			last:     now.Add(-time.Hour),
+			expected: Penalty{Action: common.PenaltyBan, Strikes: 4},
+		},
+		{
+			name:     "one strike decays every decay period",
+			cfg:      penalties,
+			strikes:  3,
+			last:     now.Add(-49 * time.Hour),
+			expected: Penalty{Action: common.PenaltyMute, Duration: 10 * time.Minute, Strikes: 2},
+		},
+		{
+			name:     "decayed strikes never go below zero",
+			cfg:      penalties,
+			strikes:  3,
+			last:     now.AddDate(0, 0, -10),
+			expected: Penalty{Action: common.PenaltyWarn, Strikes: 1},
+		},
+		{
+			name:     "strikes never decay without a decay period",
+			cfg:      &config.PenaltyConfig{Steps: penalties.Steps},
+			strikes:  3,
+			last:     now.AddDate(0, 0, -10),
+			expected: Penalty{Action: common.PenaltyBan, Strikes: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violationRepo := new(MockUserViolationRepository)
+			violation := &model.UserViolation{ID: 5, GroupID: -100, UserID: 42, Strikes: tt.strikes, LastViolationAt: tt.last}
+			violationRepo.On("FindForUpdate", mock.Anything, mock.Anything, int64(-100), int64(42)).Return(violation, nil)
+			violationRepo.On("Save", mock.Anything, mock.Anything, violation).Return(nil)
+			service := &PenaltyService{
+				log:           logger.NewLogger(),
+				db:            databasetest.NewTxDB(t),
+				cfg:           tt.cfg,
+				violationRepo: violationRepo,
+			}
+
+			penalty, err := service.Record(context.Background(), -100, &tele.User{ID: 42, Username: "alice"})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, *penalty)
+			assert.Equal(t, tt.expected.Strikes, violation.Strikes)
+			assert.Equal(t, "alice", violation.Username)
+			assert.False(t, violation.LastViolationAt.Before(now))
+			violationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPenaltyService_Mute(t *testing.T) {
+	service := &PenaltyService{}
+	penalty := &Penalty{Action: common.PenaltyWarn, Strikes: 2}
+
+	assert.Equal(t, &Penalty{Action: common.PenaltyMute, Duration: defaultMuteDuration, Strikes: 2}, service.Mute(penalty, 0))
+	assert.Equal(t, &Penalty{Action: common.PenaltyMute, Duration: 45 * time.Second, Strikes: 2}, service.Mute(penalty, 45*time.Second))
+	assert.Equal(t, &Penalty{Action: common.PenaltyMute, Duration: 30 * time.Second, Strikes: 2}, service.Mute(penalty, 10*time.Second))
+}
+
+func TestPenaltyService_RecordError(t *testing.T) {
+	violationRepo := new(MockUserViolationRepository)
+	violationRepo.On("FindForUpdate", mock.Anything, mock.Anything, int64(-100), int64(42)).Return(nil, databasetest.ErrNoDatabase)
+	service := &PenaltyService{
+		log:           logger.NewLogger(),
+		db:            databasetest.NewTxDB(t),
+		cfg:           &config.PenaltyConfig{},
+		violationRepo: violationRepo,
+	}
+
+	penalty, err := service.Record(context.Background(), -100, &tele.User{ID: 42})
+
+	assert.ErrorIs(t, err, databasetest.ErrNoDatabase)
+	assert.Nil(t, penalty)
+	violationRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+}
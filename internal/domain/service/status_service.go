@@ -2,25 +2,68 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"gorm.io/gorm"
+	"math/big"
 	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/exchange"
 	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
-	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
 	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"slices"
 )
 
 type StatusService struct {
 	log                logger.Logger
+	cfg                *config.TelegramConfig
 	db                 *gorm.DB
 	tradingBindingRepo repository.CustomerTradingBindingRepository
+	membershipRepo     repository.GroupMembershipRepository
+	volume             *VolumeService
 }
 
-func NewStatusService(cfg *config.Config, log logger.Logger) *StatusService {
-	db, _ := database.NewMySqlClient(&cfg.Database, log)
+// StatusReport the binding, compliance and membership of a uid as seen by the sender of /status
+type StatusReport struct {
+	UID string
+	// BoundToSender the uid is bound to the account the command was sent from, the other fields are only set when it is
+	BoundToSender bool
+	IsActive      bool
+	Status        common.Status
+	MemberStatus  common.MemberStatus
+	// Volume the trading volume from the 1st of this month, nil when the exchange could not be queried
+	Volume *big.Float
+	// VolumeThreshold the monthly volume needed to stay in the groups, 0 when there is none
+	VolumeThreshold float64
+	// Groups the memberships of the customer in the monitored groups it is currently in
+	Groups []*model.GroupMembership
+}
+
+// MeetsThreshold reports whether the volume reaches the threshold, a report without volume never does
+func (r *StatusReport) MeetsThreshold() bool {
+	if r.Volume == nil {
+		return false
+	}
+	return r.Volume.Cmp(big.NewFloat(r.VolumeThreshold)) >= 0
+}
+
+func NewStatusService(cfg *config.Config, client *exchange.Client, db *gorm.DB, log logger.Logger) *StatusService {
 	tradingBindingRepo := repository.NewCustomerTradingRepository(db, log)
-	return &StatusService{log, db, tradingBindingRepo}
+	return &StatusService{
+		log:                log,
+		cfg:                &cfg.Telegram,
+		db:                 db,
+		tradingBindingRepo: tradingBindingRepo,
+		membershipRepo:     repository.NewGroupMembershipRepository(db, log),
+		volume: &VolumeService{
+			client:                 client,
+			db:                     db,
+			customerTradingBinding: tradingBindingRepo,
+			tradingHistory:         repository.NewTradingHistoryRepository(db, log),
+			log:                    log,
+		},
+	}
 }
 
 func (cs *StatusService) Check(ctx context.Context, uid string) (common.MemberStatus, error) {
@@ -31,3 +74,77 @@ func (cs *StatusService) Check(ctx context.Context, uid string) (common.MemberSt
 
 	return status, nil
 }
+
+// Report builds the status report of the uid for the user with userId on platform, only an unknown uid or a
+// database failure fails the report, the volume and the groups are left out when they cannot be read. The report of a
+// uid bound to another account only tells so, the account of its owner is private
+func (cs *StatusService) Report(ctx context.Context, uid string, platform common.SocialPlatformType, userId string) (*StatusReport, error) {
+	info, err := cs.tradingBindingRepo.FindTradingBindingByUid(ctx, cs.db, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("building status report failed with uid=%s, error=%w", uid, err)
+	}
+
+	social := info.SocialAccountInfo
+	if social.SocialType != platform.Name() || social.UserID != userId {
+		return &StatusReport{UID: uid}, nil
+	}
+	report := &StatusReport{
+		UID:             uid,
+		BoundToSender:   true,
+		IsActive:        social.IsActive,
+		Status:          common.Status(social.Status),
+		MemberStatus:    common.GetMemberStatusFromString(social.MemberStatus),
+		VolumeThreshold: cs.cfg.VolumeThreshold,
+	}
+	report.Volume = cs.monthlyVolume(ctx, uid)
+	report.Groups = cs.monitoredMemberships(ctx, info.Customer.ID)
+	return report, nil
+}
+
+// monthlyVolume the volume from the 1st of this month, the exchange lists no volume of a bound uid which has not
+// traded this month
+func (cs *StatusService) monthlyVolume(ctx context.Context, uid string) *big.Float {
+	volume, err := cs.volume.HandleVolumeCheck(ctx, uid)
+	if errors.Is(err, repository.ErrUIDNotFound) {
+		return new(big.Float)
+	}
+	if err != nil {
+		cs.log.Warn("failed to get the monthly volume of the status report",
+			logger.String("uid", uid),
+			logger.Error(err))
+		return nil
+	}
+	return volume
+}
+
+// monitoredMemberships the memberships of the customer in the monitored and invite groups it is currently in
+func (cs *StatusService) monitoredMemberships(ctx context.Context, customerId string) []*model.GroupMembership {
+	memberships, err := cs.membershipRepo.FindByCustomerId(ctx, cs.db, customerId)
+	if err != nil {
+		cs.log.Error("failed to get the group memberships of the status report",
+			logger.String("customer_id", customerId),
+			logger.Error(err))
+		return nil
+	}
+
+	groupIds, err := cs.cfg.GroupIds()
+	if err != nil {
+		cs.log.Error("failed to parse group ids", logger.Error(err))
+	}
+	groupIds = append(groupIds, cs.cfg.MonitoredGroups...)
+
+	groups := make([]*model.GroupMembership, 0, len(memberships))
+	for _, membership := range memberships {
+		if !slices.Contains(groupIds, membership.GroupID) {
+			continue
+		}
+		switch membership.MemberStatus {
+		case common.Creator, common.Administrator, common.Member, common.Restricted:
+			groups = append(groups, membership)
+		}
+	}
+	return groups
+}
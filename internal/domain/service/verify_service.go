@@ -25,8 +25,7 @@ type VerifyService struct {
 	log                      logger.Logger
 }
 
-func NewVerifyService(cfg *config.Config, client *exchange.Client, log logger.Logger) *VerifyService {
-	db, _ := database.NewMySqlClient(&cfg.Database, log)
+func NewVerifyService(cfg *config.Config, client *exchange.Client, db *gorm.DB, log logger.Logger) *VerifyService {
 	customerRepo := repository.NewCustomerRepository(db, log)
 	customerSocialRepo := repository.NewCustomerSocialRepository(db, log)
 	customerTradingRepo := repository.NewCustomerTradingRepository(db, log)
@@ -120,10 +119,10 @@ func (v *VerifyService) getValidResult(response string, uid string) (*bitget.Cus
 	return &result.Data[0], nil
 }
 
-func buildTelegramSocialPlatform() *model.SocialPlatform {
+func buildSocialPlatform(platform common.SocialPlatformType) *model.SocialPlatform {
 	return &model.SocialPlatform{
-		Id:       common.Telegram.Value(),
-		Name:     common.Telegram.Name(),
+		Id:       platform.Value(),
+		Name:     platform.Name(),
 		IsActive: true,
 	}
 }
@@ -138,7 +137,7 @@ func buildBitgetTradingPlatform() *model.TradingPlatform {
 func buildSocialBinding(userInfo *common.UserInfo, customer *model.Customer) *model.CustomerSocialBinding {
 	return &model.CustomerSocialBinding{
 		CustomerID:   customer.Id,
-		SocialID:     common.Telegram.Value(),
+		SocialID:     userInfo.SocialPlatform.Value(),
 		UserID:       userInfo.UserId,
 		Username:     userInfo.Username,
 		Firstname:    userInfo.Firstname,
@@ -147,7 +146,7 @@ func buildSocialBinding(userInfo *common.UserInfo, customer *model.Customer) *mo
 		MemberStatus: userInfo.MemberStatus,
 		Status:       common.Normal,
 		Customer:     customer,
-		Platform:     buildTelegramSocialPlatform(),
+		Platform:     buildSocialPlatform(userInfo.SocialPlatform),
 	}
 }
 
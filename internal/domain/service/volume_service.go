@@ -7,8 +7,6 @@ import (
 	"math/big"
 	"ohmycontrolcenter.tech/omcc/internal/domain/service/exchange"
 	"ohmycontrolcenter.tech/omcc/internal/domain/service/exchange/bitget"
-	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
-	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
 	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
 	"ohmycontrolcenter.tech/omcc/util"
@@ -22,8 +20,7 @@ type VolumeService struct {
 	log                    logger.Logger
 }
 
-func NewVolumeService(cfg *config.DatabaseConfig, client *exchange.Client, log logger.Logger) *VolumeService {
-	db, _ := database.NewMySqlClient(cfg, log)
+func NewVolumeService(client *exchange.Client, db *gorm.DB, log logger.Logger) *VolumeService {
 	return &VolumeService{
 		client:                 client,
 		db:                     db,
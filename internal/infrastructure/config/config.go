@@ -4,18 +4,26 @@ import (
 	"fmt"
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
+	"net/url"
 	"ohmycontrolcenter.tech/omcc/internal/common"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
 	App        AppConfig      `mapstructure:"app"`
 	Telegram   TelegramConfig `mapstructure:"telegram"`
+	Line       LineConfig     `mapstructure:"line"`
 	Server     ServerConfig   `mapstructure:"server"`
 	Exchange   Exchange       `mapstructure:"exchange"`
 	Database   DatabaseConfig `mapstructure:"database"`
 	TimeFormat TimeFormatConfig
+	// Tenants communities served by the process, each with its own bot, broker account and data
+	Tenants []TenantConfig `mapstructure:"tenants"`
+	// Tenant the tenant this config belongs to, set by TenantConfigs
+	Tenant TenantConfig `mapstructure:"-"`
 	// TODO redis
 }
 
@@ -50,6 +58,179 @@ type TelegramConfig struct {
 	SendWarning     bool          `mapstructure:"send_warning"`
 	WarningDuration int           `mapstructure:"warning_duration"`
 	Port            string        `mapstructure:"port"`
+	DefaultLanguage string        `mapstructure:"default_language"`
+	// TemplateReloadInterval how often message templates are reloaded from database
+	TemplateReloadInterval time.Duration `mapstructure:"template_reload_interval"`
+	// InviteLinkTTL unused invite links are revoked after this duration
+	InviteLinkTTL time.Duration `mapstructure:"invite_link_ttl"`
+	// AdminIds telegram user ids allowed to use the admin commands
+	AdminIds []int64 `mapstructure:"admin_ids"`
+	// AdminChatId chat notified about the moderation incidents, the admin ids are notified when unset
+	AdminChatId int64 `mapstructure:"admin_chat_id"`
+	// CaptchaEnabled restricts the new members of the monitored groups until they pass the captcha
+	CaptchaEnabled bool `mapstructure:"captcha_enabled"`
+	// CaptchaMode button to accept the rules or math to solve a sum
+	CaptchaMode string `mapstructure:"captcha_mode"`
+	// CaptchaTimeout new members not passing the captcha within this duration are kicked
+	CaptchaTimeout time.Duration `mapstructure:"captcha_timeout"`
+	// RateLimit limits how often a user can run the commands in private chat
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	// Penalties escalation of the moderation violations in the monitored groups
+	Penalties PenaltyConfig `mapstructure:"penalties"`
+	// ModerationReloadInterval how often the moderation rules and trusted users are reloaded from database
+	ModerationReloadInterval time.Duration `mapstructure:"moderation_reload_interval"`
+	// ModerationShadow only records what every rule would do, no message is deleted and no user warned
+	ModerationShadow bool `mapstructure:"moderation_shadow"`
+	// Mode webhook or polling, polling needs no public url
+	Mode string `mapstructure:"mode"`
+	// WebhookSecret sent by telegram in the X-Telegram-Bot-Api-Secret-Token header of every webhook request
+	WebhookSecret string `mapstructure:"webhookSecret" env:"TELEGRAM_WEBHOOK_SECRET"`
+	// WebhookListener server mounts the webhook on the admin http server, standalone listens on Port
+	WebhookListener string `mapstructure:"webhook_listener"`
+	// BroadcastRate messages per second sent by the admin broadcasts, telegram allows about 30 to different users
+	BroadcastRate int `mapstructure:"broadcast_rate"`
+	// SendQueue limits of the outbound messages shared by the replies, announcements and broadcasts
+	SendQueue SendQueueConfig `mapstructure:"send_queue"`
+	// VolumeThreshold monthly trading volume in usdt a customer needs to stay in the groups
+	VolumeThreshold float64 `mapstructure:"volume_threshold"`
+}
+
+// PollerMode returns the configured mode, webhook by default
+func (t *TelegramConfig) PollerMode() string {
+	if t.Mode == common.BotModePolling {
+		return common.BotModePolling
+	}
+	return common.BotModeWebhook
+}
+
+// WebhookOnServer reports whether the webhook is served by the admin http server, standalone by default
+func (t *TelegramConfig) WebhookOnServer() bool {
+	return t.WebhookListener == common.WebhookListenerServer
+}
+
+// WebhookPath route of the webhook on the admin http server, the path of the webhook url
+func (t *TelegramConfig) WebhookPath() string {
+	u, err := url.Parse(t.WebhookURL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+// LineConfig the LINE Messaging API channel running the verify, volume, status and account commands
+type LineConfig struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	ChannelSecret      string `mapstructure:"channelSecret" env:"LINE_CHANNEL_SECRET"`
+	ChannelAccessToken string `mapstructure:"channelAccessToken" env:"LINE_CHANNEL_ACCESS_TOKEN"`
+	// BaseUrl of the messaging api, a stub server in the tests
+	BaseUrl string `mapstructure:"baseUrl"`
+	// WebhookPath route of the LINE webhook on the admin http server
+	WebhookPath string `mapstructure:"webhook_path"`
+}
+
+type SendQueueConfig struct {
+	// GlobalRate messages per second over all the chats
+	GlobalRate int `mapstructure:"global_rate"`
+	// ChatRate messages per second to a private chat
+	ChatRate int `mapstructure:"chat_rate"`
+	// GroupRate messages per minute to a group or channel
+	GroupRate int `mapstructure:"group_rate"`
+	// FloodRetries times a message answered with 429 is sent again after its retry_after
+	FloodRetries int `mapstructure:"flood_retries"`
+}
+
+// PenaltyStep action taken once a user reaches Strikes, Duration applies to mute
+type PenaltyStep struct {
+	Strikes  int           `mapstructure:"strikes"`
+	Action   string        `mapstructure:"action"`
+	Duration time.Duration `mapstructure:"duration"`
+}
+
+type PenaltyConfig struct {
+	// Decay one strike is forgiven for every Decay without a violation, strikes never decay when unset
+	Decay time.Duration `mapstructure:"decay"`
+	Steps []PenaltyStep `mapstructure:"steps"`
+}
+
+// Step returns the step with the most strikes reached, a warning when none is reached
+func (p *PenaltyConfig) Step(strikes int) PenaltyStep {
+	step := PenaltyStep{Strikes: 1, Action: "warn"}
+	for _, s := range p.Steps {
+		if s.Strikes <= strikes && s.Strikes >= step.Strikes {
+			step = s
+		}
+	}
+	return step
+}
+
+// Decayed returns the strikes left at now, one strike is forgiven for every Decay since the last violation
+func (p *PenaltyConfig) Decayed(strikes int, last time.Time, now time.Time) int {
+	if p.Decay <= 0 || strikes == 0 {
+		return strikes
+	}
+	forgiven := int(now.Sub(last) / p.Decay)
+	return max(0, strikes-forgiven)
+}
+
+// RateLimitRule token bucket of Burst tokens refilled one every Refill, with at least Cooldown between two calls
+type RateLimitRule struct {
+	Burst    int           `mapstructure:"burst"`
+	Refill   time.Duration `mapstructure:"refill"`
+	Cooldown time.Duration `mapstructure:"cooldown"`
+}
+
+type RateLimitConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	RateLimitRule `mapstructure:",squash"`
+	// Commands overrides the default rule per command name without the leading slash
+	Commands map[string]RateLimitRule `mapstructure:"commands"`
+	// MaxViolations throttled calls in a row before the user is blocked for BlockDuration
+	MaxViolations int           `mapstructure:"max_violations"`
+	BlockDuration time.Duration `mapstructure:"block_duration"`
+}
+
+// Rule returns the rule of the command, fields left empty fall back to the default rule
+func (r *RateLimitConfig) Rule(command string) RateLimitRule {
+	rule := r.RateLimitRule
+	override, ok := r.Commands[command]
+	if !ok {
+		return rule
+	}
+	if override.Burst > 0 {
+		rule.Burst = override.Burst
+	}
+	if override.Refill > 0 {
+		rule.Refill = override.Refill
+	}
+	if override.Cooldown > 0 {
+		rule.Cooldown = override.Cooldown
+	}
+	return rule
+}
+
+// AdminChats returns the chats notified about the moderation incidents
+func (t *TelegramConfig) AdminChats() []int64 {
+	if t.AdminChatId != 0 {
+		return []int64{t.AdminChatId}
+	}
+	return t.AdminIds
+}
+
+// GroupIds parses the comma separated group ids the bot invites verified customers to
+func (t *TelegramConfig) GroupIds() ([]int64, error) {
+	var ids []int64
+	for _, groupId := range strings.Split(t.Group, ",") {
+		groupId = strings.TrimSpace(groupId)
+		if groupId == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(groupId, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid telegram group id %q: %w", groupId, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
 }
 
 type Exchange struct {
@@ -74,6 +255,147 @@ type DatabaseConfig struct {
 	MaxIdleConnections int           `mapstructure:"max_idle_connections"`
 	MaxOpenConnections int           `mapstructure:"max_open_connections"`
 	MaxLifetime        time.Duration `mapstructure:"max_lifetime"`
+	// TenantID scopes the clients to the data of one tenant, the clients without one take it from the context
+	TenantID string `mapstructure:"-"`
+}
+
+// TenantConfig a community with its own bot and broker account, the fields left empty keep the base config
+type TenantConfig struct {
+	ID   string `mapstructure:"id"`
+	Name string `mapstructure:"name"`
+	// AdminToken authenticates the admin api requests of the tenant in the X-Tenant-Token header
+	AdminToken string               `mapstructure:"admin_token" env:"TENANT_<ID>_ADMIN_TOKEN"`
+	Telegram   TenantTelegramConfig `mapstructure:"telegram"`
+	Line       TenantLineConfig     `mapstructure:"line"`
+	Bitget     TenantBitgetConfig   `mapstructure:"bitget"`
+}
+
+type TenantTelegramConfig struct {
+	Token           string  `mapstructure:"token" env:"TENANT_<ID>_TELEGRAM_BOT_TOKEN"`
+	WebhookURL      string  `mapstructure:"webhookUrl" env:"TENANT_<ID>_TELEGRAM_WEBHOOK_URL"`
+	WebhookSecret   string  `mapstructure:"webhookSecret" env:"TENANT_<ID>_TELEGRAM_WEBHOOK_SECRET"`
+	Port            string  `mapstructure:"port"`
+	Group           string  `mapstructure:"group"`
+	MonitoredGroups []int64 `mapstructure:"monitored_groups"`
+	MonitoredTopics []int   `mapstructure:"monitored_topics"`
+	DefaultLanguage string  `mapstructure:"default_language"`
+	AdminIds        []int64 `mapstructure:"admin_ids"`
+	AdminChatId     int64   `mapstructure:"admin_chat_id"`
+	VolumeThreshold float64 `mapstructure:"volume_threshold"`
+}
+
+type TenantLineConfig struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	ChannelSecret      string `mapstructure:"channelSecret" env:"TENANT_<ID>_LINE_CHANNEL_SECRET"`
+	ChannelAccessToken string `mapstructure:"channelAccessToken" env:"TENANT_<ID>_LINE_CHANNEL_ACCESS_TOKEN"`
+	WebhookPath        string `mapstructure:"webhook_path"`
+}
+
+type TenantBitgetConfig struct {
+	ApiKey     string `mapstructure:"apiKey" env:"TENANT_<ID>_BITGET_API_KEY"`
+	SecretKey  string `mapstructure:"secretKey" env:"TENANT_<ID>_BITGET_SECRET_KEY"`
+	Passphrase string `mapstructure:"passphrase" env:"TENANT_<ID>_BITGET_PASSPHRASE"`
+}
+
+// TenantConfigs returns the config of every tenant, the base config is the default tenant when none is configured
+func (c *Config) TenantConfigs() ([]*Config, error) {
+	if len(c.Tenants) == 0 {
+		cfg := *c
+		cfg.Tenant = TenantConfig{ID: common.DefaultTenantID}
+		cfg.Database.TenantID = common.DefaultTenantID
+		return []*Config{&cfg}, nil
+	}
+
+	ids := make(map[string]bool, len(c.Tenants))
+	tokens := make(map[string]string, len(c.Tenants))
+	adminTokens := make(map[string]string, len(c.Tenants))
+	webhooks := make(map[string]string, len(c.Tenants))
+	configs := make([]*Config, 0, len(c.Tenants))
+	for _, tenant := range c.Tenants {
+		if tenant.ID == "" {
+			return nil, fmt.Errorf("tenant %q has no id", tenant.Name)
+		}
+		if ids[tenant.ID] {
+			return nil, fmt.Errorf("tenant %q configured twice", tenant.ID)
+		}
+		ids[tenant.ID] = true
+		if len(c.Tenants) > 1 && tenant.AdminToken == "" {
+			return nil, fmt.Errorf("tenant %q has no admin token", tenant.ID)
+		}
+		if other, ok := adminTokens[tenant.AdminToken]; ok && tenant.AdminToken != "" {
+			return nil, fmt.Errorf("tenants %q and %q share the admin token", other, tenant.ID)
+		}
+		adminTokens[tenant.AdminToken] = tenant.ID
+
+		cfg := c.withTenant(tenant)
+		if other, ok := tokens[cfg.Telegram.Token]; ok {
+			return nil, fmt.Errorf("tenants %q and %q share the telegram bot token", other, tenant.ID)
+		}
+		tokens[cfg.Telegram.Token] = tenant.ID
+		var paths []string
+		if cfg.Telegram.PollerMode() == common.BotModeWebhook && cfg.Telegram.WebhookOnServer() {
+			paths = append(paths, cfg.Telegram.WebhookPath())
+		}
+		if cfg.Line.Enabled {
+			paths = append(paths, cfg.Line.WebhookPath)
+		}
+		for _, path := range paths {
+			if other, ok := webhooks[path]; ok {
+				return nil, fmt.Errorf("tenants %q and %q share the webhook path %s", other, tenant.ID, path)
+			}
+			webhooks[path] = tenant.ID
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+// withTenant returns a copy of the base config with the overrides of the tenant
+func (c *Config) withTenant(tenant TenantConfig) *Config {
+	cfg := *c
+	cfg.Tenants = nil
+	cfg.Tenant = tenant
+	cfg.Database.TenantID = tenant.ID
+
+	t := tenant.Telegram
+	overrideString(&cfg.Telegram.Token, t.Token)
+	overrideString(&cfg.Telegram.WebhookURL, t.WebhookURL)
+	overrideString(&cfg.Telegram.WebhookSecret, t.WebhookSecret)
+	overrideString(&cfg.Telegram.Port, t.Port)
+	overrideString(&cfg.Telegram.Group, t.Group)
+	overrideString(&cfg.Telegram.DefaultLanguage, t.DefaultLanguage)
+	if t.MonitoredGroups != nil {
+		cfg.Telegram.MonitoredGroups = t.MonitoredGroups
+	}
+	if t.MonitoredTopics != nil {
+		cfg.Telegram.MonitoredTopics = t.MonitoredTopics
+	}
+	if t.AdminIds != nil {
+		cfg.Telegram.AdminIds = t.AdminIds
+	}
+	if t.AdminChatId != 0 {
+		cfg.Telegram.AdminChatId = t.AdminChatId
+	}
+	if t.VolumeThreshold > 0 {
+		cfg.Telegram.VolumeThreshold = t.VolumeThreshold
+	}
+
+	l := tenant.Line
+	cfg.Line.Enabled = cfg.Line.Enabled || l.Enabled
+	overrideString(&cfg.Line.ChannelSecret, l.ChannelSecret)
+	overrideString(&cfg.Line.ChannelAccessToken, l.ChannelAccessToken)
+	overrideString(&cfg.Line.WebhookPath, l.WebhookPath)
+
+	overrideString(&cfg.Exchange.ApiKey, tenant.Bitget.ApiKey)
+	overrideString(&cfg.Exchange.SecretKey, tenant.Bitget.SecretKey)
+	overrideString(&cfg.Exchange.Passphrase, tenant.Bitget.Passphrase)
+	return &cfg
+}
+
+func overrideString(value *string, override string) {
+	if override != "" {
+		*value = override
+	}
 }
 
 type TimeFormatConfig struct {
@@ -108,6 +430,7 @@ func NewConfig(configPath string) (*Config, error) {
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, err
 	}
+	loadTenantSensitiveConfig(cfg.Tenants)
 
 	return &cfg, nil
 }
@@ -117,14 +440,36 @@ func loadSensitiveConfig() {
 	// Telegram config
 	viper.Set("telegram.token", os.Getenv("TELEGRAM_BOT_TOKEN"))
 	viper.Set("telegram.webhookUrl", os.Getenv("TELEGRAM_WEBHOOK_URL"))
+	viper.Set("telegram.webhookSecret", os.Getenv("TELEGRAM_WEBHOOK_SECRET"))
 	viper.Set("telegram.botName", os.Getenv("TELEGRAM_BOT_NAME"))
 
+	// LINE config
+	viper.Set("line.channelSecret", os.Getenv("LINE_CHANNEL_SECRET"))
+	viper.Set("line.channelAccessToken", os.Getenv("LINE_CHANNEL_ACCESS_TOKEN"))
+
 	// Bitget config
 	viper.Set(common.BitgetApiKeyEnvPath, os.Getenv("BITGET_API_KEY"))
 	viper.Set(common.BitgetApiSecretKeyEnvPath, os.Getenv("BITGET_SECRET_KEY"))
 	viper.Set(common.BitgetApiPassphraseEnvPath, os.Getenv("BITGET_PASSPHRASE"))
 }
 
+// loadTenantSensitiveConfig env variables for the sensitive data of the tenants, prefixed with TENANT_<ID>_
+func loadTenantSensitiveConfig(tenants []TenantConfig) {
+	for i := range tenants {
+		t := &tenants[i]
+		prefix := "TENANT_" + strings.ToUpper(strings.ReplaceAll(t.ID, "-", "_")) + "_"
+		overrideString(&t.AdminToken, os.Getenv(prefix+"ADMIN_TOKEN"))
+		overrideString(&t.Telegram.Token, os.Getenv(prefix+"TELEGRAM_BOT_TOKEN"))
+		overrideString(&t.Telegram.WebhookURL, os.Getenv(prefix+"TELEGRAM_WEBHOOK_URL"))
+		overrideString(&t.Telegram.WebhookSecret, os.Getenv(prefix+"TELEGRAM_WEBHOOK_SECRET"))
+		overrideString(&t.Line.ChannelSecret, os.Getenv(prefix+"LINE_CHANNEL_SECRET"))
+		overrideString(&t.Line.ChannelAccessToken, os.Getenv(prefix+"LINE_CHANNEL_ACCESS_TOKEN"))
+		overrideString(&t.Bitget.ApiKey, os.Getenv(prefix+"BITGET_API_KEY"))
+		overrideString(&t.Bitget.SecretKey, os.Getenv(prefix+"BITGET_SECRET_KEY"))
+		overrideString(&t.Bitget.Passphrase, os.Getenv(prefix+"BITGET_PASSPHRASE"))
+	}
+}
+
 func loadDatabaseSensitiveConfig() {
 	// Database config
 	viper.Set(common.DatabaseHostEnvPath, os.Getenv("POLAR_DATABASE_HOST"))
@@ -0,0 +1,54 @@
+// Package databasetest database of the service tests whose statements run on mocked repositories
+package databasetest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"testing"
+)
+
+// ErrNoDatabase returned by every statement run on the database of NewTxDB
+var ErrNoDatabase = errors.New("no database in the test")
+
+// txConnPool lets the service open its transactions, the statements run on the mocked repositories
+type txConnPool struct{}
+
+func (*txConnPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, ErrNoDatabase
+}
+
+func (*txConnPool) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, ErrNoDatabase
+}
+
+func (*txConnPool) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, ErrNoDatabase
+}
+
+func (*txConnPool) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (p *txConnPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return p, nil
+}
+
+func (*txConnPool) Commit() error {
+	return nil
+}
+
+func (*txConnPool) Rollback() error {
+	return nil
+}
+
+// NewTxDB database whose transactions begin and commit without a server
+func NewTxDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: &txConnPool{}, SkipInitializeWithVersion: true}),
+		&gorm.Config{DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+	return db
+}
@@ -63,6 +63,11 @@ func NewMySqlClient(cfg *config.DatabaseConfig, log log.Logger) (*gorm.DB, error
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// scope the tenant data, a client without tenant takes it from the context of every statement
+	if err := db.Use(NewTenantPlugin(cfg.TenantID)); err != nil {
+		return nil, fmt.Errorf("failed to register tenant plugin: %w", err)
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database instance: %w", err)
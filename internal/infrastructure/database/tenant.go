@@ -0,0 +1,254 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrMissingTenant  = errors.New("tenant is required to access tenant data")
+	ErrTenantMismatch = errors.New("tenant of the context does not match the tenant of the database client")
+)
+
+// tenantColumn column of the tenant owning a row
+const tenantColumn = "tenant_id"
+
+// tenantTables tables holding tenant data, the platform tables are shared by all tenants
+var tenantTables = map[string]bool{
+	"customers":                 true,
+	"customer_social_bindings":  true,
+	"customer_trading_bindings": true,
+	"trading_histories":         true,
+	"social_user_languages":     true,
+	"message_templates":         true,
+	"invite_links":              true,
+	"group_memberships":         true,
+	"member_status_histories":   true,
+	"link_sharing_incidents":    true,
+	"captcha_challenges":        true,
+	"user_violations":           true,
+	"moderation_rules":          true,
+	"trusted_users":             true,
+	"moderation_logs":           true,
+	"delayed_actions":           true,
+	"announcements":             true,
+	"announcement_runs":         true,
+	"broadcast_jobs":            true,
+	"broadcast_recipients":      true,
+}
+
+type tenantContextKey struct{}
+
+// WithTenant returns ctx carrying the tenant, the queries run with it only see the data of the tenant
+func WithTenant(ctx context.Context, tenantId string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantId)
+}
+
+// TenantFromContext returns the tenant carried by ctx
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantId, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenantId, ok && tenantId != ""
+}
+
+// TenantPlugin scopes every statement on the tenant tables to one tenant, the rows created are assigned to it.
+// A client bound to a tenant scopes its statements to that tenant, an unbound client takes the tenant from the
+// context of the statement and fails the statements without one
+type TenantPlugin struct {
+	tenantId string
+}
+
+func NewTenantPlugin(tenantId string) *TenantPlugin {
+	return &TenantPlugin{tenantId: tenantId}
+}
+
+func (p *TenantPlugin) Name() string {
+	return "tenant"
+}
+
+func (p *TenantPlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("tenant:create", p.assign); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("tenant:query", p.scope); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("tenant:update", p.update); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("tenant:delete", p.scope); err != nil {
+		return err
+	}
+	return db.Callback().Row().Before("gorm:row").Register("tenant:row", p.scope)
+}
+
+// tenant returns the tenant of the statement
+func (p *TenantPlugin) tenant(db *gorm.DB) (string, error) {
+	tenantId, ok := TenantFromContext(db.Statement.Context)
+	switch {
+	case p.tenantId == "" && !ok:
+		return "", ErrMissingTenant
+	case p.tenantId == "":
+		return tenantId, nil
+	case ok && tenantId != p.tenantId:
+		return "", ErrTenantMismatch
+	}
+	return p.tenantId, nil
+}
+
+// scope adds the tenant condition on the main table of the statement, the joined rows are reached from it. A statement
+// without tenant matches no row, the error of a subquery never reaches the statement embedding it
+func (p *TenantPlugin) scope(db *gorm.DB) {
+	propagate(db.Statement)
+	table, alias := statementTable(db.Statement)
+	if db.Error != nil || !tenantTables[table] {
+		return
+	}
+	condition := clause.Expression(clause.Expr{SQL: "1 = 0"})
+	tenantId, err := p.tenant(db)
+	if err != nil {
+		_ = db.AddError(err)
+	} else {
+		condition = clause.Eq{Column: clause.Column{Table: alias, Name: tenantColumn}, Value: tenantId}
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{condition}})
+}
+
+// update scopes the update and keeps the tenant of a saved row
+func (p *TenantPlugin) update(db *gorm.DB) {
+	p.scope(db)
+	table, _ := statementTable(db.Statement)
+	if db.Error != nil || !tenantTables[table] || db.Statement.Schema == nil {
+		return
+	}
+	tenantId, _ := p.tenant(db)
+	p.set(db, tenantId)
+}
+
+// assign sets the tenant of the rows created
+func (p *TenantPlugin) assign(db *gorm.DB) {
+	table, _ := statementTable(db.Statement)
+	if db.Error != nil || !tenantTables[table] {
+		return
+	}
+	if db.Statement.Schema == nil || db.Statement.Schema.LookUpField(tenantColumn) == nil {
+		_ = db.AddError(fmt.Errorf("%w: %s rows need a %s field", ErrMissingTenant, table, tenantColumn))
+		return
+	}
+	tenantId, err := p.tenant(db)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+	p.set(db, tenantId)
+}
+
+// set sets the tenant field of the rows of the statement
+func (p *TenantPlugin) set(db *gorm.DB, tenantId string) {
+	field := db.Statement.Schema.LookUpField(tenantColumn)
+	if field == nil {
+		return
+	}
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			row := reflect.Indirect(rv.Index(i))
+			if !row.CanAddr() {
+				continue
+			}
+			if err := field.Set(db.Statement.Context, row, tenantId); err != nil {
+				_ = db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if !rv.CanAddr() {
+			return
+		}
+		if err := field.Set(db.Statement.Context, rv, tenantId); err != nil {
+			_ = db.AddError(err)
+		}
+	}
+}
+
+// propagate hands the tenant of the statement context to the subqueries it embeds which were built without one, on an
+// unbound client a subquery only reaches the tenant through its own context. The subqueries of a subquery are reached
+// when it is scoped in turn
+func propagate(stmt *gorm.Statement) {
+	tenantId, ok := TenantFromContext(stmt.Context)
+	if !ok {
+		return
+	}
+
+	var visit func(value interface{})
+	visitAll := func(values []interface{}) {
+		for _, value := range values {
+			visit(value)
+		}
+	}
+	visitExprs := func(exprs []clause.Expression) {
+		for _, expr := range exprs {
+			visit(expr)
+		}
+	}
+	visit = func(value interface{}) {
+		switch v := value.(type) {
+		case *gorm.DB:
+			ctx := v.Statement.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if _, ok := TenantFromContext(ctx); !ok {
+				v.Statement.Context = WithTenant(ctx, tenantId)
+			}
+		case clause.Where:
+			visitExprs(v.Exprs)
+		case clause.AndConditions:
+			visitExprs(v.Exprs)
+		case clause.OrConditions:
+			visitExprs(v.Exprs)
+		case clause.NotConditions:
+			visitExprs(v.Exprs)
+		case clause.Expr:
+			visitAll(v.Vars)
+		case clause.NamedExpr:
+			visitAll(v.Vars)
+		case clause.IN:
+			visitAll(v.Values)
+		case clause.Eq:
+			visit(v.Value)
+		case clause.Neq:
+			visit(v.Value)
+		case []interface{}:
+			visitAll(v)
+		}
+	}
+
+	for _, c := range stmt.Clauses {
+		visit(c.Expression)
+	}
+	if stmt.TableExpr != nil {
+		visit(*stmt.TableExpr)
+	}
+	for _, join := range stmt.Joins {
+		visitAll(join.Conds)
+	}
+}
+
+// statementTable returns the table of the statement and the name it is referred to by, Table("customers c")
+// refers to customers as c
+func statementTable(stmt *gorm.Statement) (string, string) {
+	if stmt.TableExpr == nil {
+		return stmt.Table, stmt.Table
+	}
+	fields := strings.Fields(stmt.TableExpr.SQL)
+	if len(fields) == 0 {
+		return stmt.Table, stmt.Table
+	}
+	return strings.Trim(fields[0], "`"), stmt.Table
+}
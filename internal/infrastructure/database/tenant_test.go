@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"testing"
+)
+
+func newDryRunDB(t *testing.T, tenantId string) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "test:test@tcp(localhost:3306)/test", SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewTenantPlugin(tenantId)))
+	return db
+}
+
+func TestTenantPlugin_ScopesQueries(t *testing.T) {
+	db := newDryRunDB(t, "alpha")
+
+	stmt := db.Where("uid = ?", "1").Find(&[]model.CustomerTradingBinding{}).Statement
+	assert.Contains(t, stmt.SQL.String(), "`customer_trading_bindings`.`tenant_id` = ?")
+	assert.Contains(t, stmt.Vars, "alpha")
+
+	stmt = db.Table("customers c").Select("c.id").Joins("LEFT JOIN customer_social_bindings s ON c.id = s.customer_id").
+		Find(&[]model.Customer{}).Statement
+	assert.Contains(t, stmt.SQL.String(), "`c`.`tenant_id` = ?")
+
+	subQuery := db.Table("customer_trading_bindings").Select("customer_id").Where("uid = ?", "1")
+	stmt = db.Table("customer_social_bindings").Where("customer_id IN (?)", subQuery).Find(&[]model.CustomerSocialBinding{}).Statement
+	assert.Contains(t, stmt.SQL.String(), "`customer_social_bindings`.`tenant_id` = ?")
+	assert.Contains(t, stmt.SQL.String(), "`customer_trading_bindings`.`tenant_id` = ?")
+
+	stmt = db.Find(&[]model.SocialPlatform{}).Statement
+	assert.NotContains(t, stmt.SQL.String(), "tenant_id")
+}
+
+func TestTenantPlugin_ScopesWrites(t *testing.T) {
+	db := newDryRunDB(t, "alpha")
+
+	rule := &model.ModerationRule{Name: "links"}
+	require.NoError(t, db.Create(rule).Error)
+	assert.Equal(t, "alpha", rule.TenantID)
+
+	stmt := db.Model(&model.ModerationRule{ID: 1}).Update("enabled", false).Statement
+	assert.Contains(t, stmt.SQL.String(), "`moderation_rules`.`tenant_id` = ?")
+
+	stmt = db.Delete(&model.TrustedUser{}, 1).Statement
+	assert.Contains(t, stmt.SQL.String(), "`trusted_users`.`tenant_id` = ?")
+}
+
+func TestTenantPlugin_ContextTenant(t *testing.T) {
+	db := newDryRunDB(t, "")
+
+	err := db.Find(&[]model.Customer{}).Error
+	assert.ErrorIs(t, err, ErrMissingTenant)
+
+	stmt := db.WithContext(WithTenant(context.Background(), "beta")).Find(&[]model.Customer{}).Statement
+	assert.NoError(t, stmt.Error)
+	assert.Contains(t, stmt.Vars, "beta")
+
+	// the subquery built without the context gets the tenant of the statement embedding it
+	ctx := WithTenant(context.Background(), "beta")
+	subQuery := db.Table("customer_trading_bindings").Select("customer_id").Where("uid = ?", "1")
+	stmt = db.WithContext(ctx).Table("customer_social_bindings").Where("customer_id IN (?)", subQuery).
+		Find(&[]model.CustomerSocialBinding{}).Statement
+	assert.NoError(t, stmt.Error)
+	assert.Contains(t, stmt.SQL.String(), "customer_id IN (SELECT customer_id FROM `customer_trading_bindings` WHERE uid = ? AND `customer_trading_bindings`.`tenant_id` = ?)")
+	assert.Contains(t, stmt.SQL.String(), "`customer_social_bindings`.`tenant_id` = ?")
+	assert.Equal(t, []interface{}{"1", "beta", "beta"}, stmt.Vars)
+
+	nested := db.Table("customers").Select("id").Where("username = ?", "alice")
+	subQuery = db.Table("customer_trading_bindings").Select("customer_id").Where("customer_id IN (?)", nested)
+	stmt = db.WithContext(ctx).Table("customer_social_bindings").Where("customer_id IN (?)", subQuery).
+		Find(&[]model.CustomerSocialBinding{}).Statement
+	assert.NoError(t, stmt.Error)
+	assert.Contains(t, stmt.SQL.String(), "`customers`.`tenant_id` = ?")
+	assert.NotContains(t, stmt.SQL.String(), "IN ()")
+
+	fixed := newDryRunDB(t, "alpha")
+	err = fixed.WithContext(WithTenant(context.Background(), "beta")).Find(&[]model.Customer{}).Error
+	assert.ErrorIs(t, err, ErrTenantMismatch)
+}
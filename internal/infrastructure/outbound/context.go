@@ -0,0 +1,51 @@
+package outbound
+
+import (
+	"context"
+	tele "gopkg.in/telebot.v3"
+)
+
+// Middleware routes the messages the handlers send through their context to the queue as interactive messages
+func (q *Queue) Middleware(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		return next(&queuedContext{Context: c, queue: q})
+	}
+}
+
+type queuedContext struct {
+	tele.Context
+	queue *Queue
+}
+
+func (c *queuedContext) Send(what interface{}, opts ...interface{}) error {
+	_, err := c.queue.Send(context.Background(), Interactive, c.Recipient(), what, opts...)
+	return err
+}
+
+func (c *queuedContext) Reply(what interface{}, opts ...interface{}) error {
+	msg := c.Message()
+	if msg == nil {
+		return tele.ErrBadContext
+	}
+	_, err := c.queue.Send(context.Background(), Interactive, msg.Chat, what, replyOptions(msg, opts)...)
+	return err
+}
+
+// replyOptions sets the reply of the send options, added in front so the other options still apply to them
+func replyOptions(msg *tele.Message, opts []interface{}) []interface{} {
+	replied := make([]interface{}, 0, len(opts)+1)
+	found := false
+	for _, opt := range opts {
+		if sendOpts, ok := opt.(*tele.SendOptions); ok && sendOpts != nil {
+			copied := *sendOpts
+			copied.ReplyTo = msg
+			opt = &copied
+			found = true
+		}
+		replied = append(replied, opt)
+	}
+	if !found {
+		replied = append([]interface{}{&tele.SendOptions{ReplyTo: msg}}, replied...)
+	}
+	return replied
+}
@@ -0,0 +1,357 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	tele "gopkg.in/telebot.v3"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultGlobalRate   = 30
+	defaultChatRate     = 1
+	defaultGroupRate    = 20
+	defaultFloodRetries = 3
+	windowPruneInterval = 10 * time.Minute
+)
+
+var ErrQueueStopped = errors.New("send queue stopped")
+
+// Priority interactive messages are sent before any bulk message waiting
+type Priority int
+
+const (
+	// Interactive replies and notices triggered by the users
+	Interactive Priority = iota
+	// Bulk announcements and broadcasts
+	Bulk
+	priorities
+)
+
+// Stats depth of the queue and the counters since start
+type Stats struct {
+	Interactive int    `json:"interactive"`
+	Bulk        int    `json:"bulk"`
+	Sent        uint64 `json:"sent"`
+	Failed      uint64 `json:"failed"`
+	Throttled   uint64 `json:"throttled"`
+	PausedChats int    `json:"paused_chats"`
+}
+
+type ticket struct {
+	chat  string
+	ready chan struct{}
+}
+
+// Queue admits the outbound telegram messages within the global, per chat and per group limits of telegram,
+// a chat answering 429 is paused for its retry_after and the message sent again
+type Queue struct {
+	bot          *tele.Bot
+	log          logger.Logger
+	floodRetries int
+	globalRate   int
+	chatRate     int
+	groupRate    int
+
+	mu        sync.Mutex
+	lanes     [priorities][]*ticket
+	global    *window
+	chats     map[string]*window
+	pruned    time.Time
+	sent      uint64
+	failed    uint64
+	throttled uint64
+
+	wake chan struct{}
+	stop chan struct{}
+	once sync.Once
+	now  func() time.Time
+}
+
+func NewQueue(cfg *config.SendQueueConfig, bot *tele.Bot, log logger.Logger) *Queue {
+	q := &Queue{
+		bot:          bot,
+		log:          log,
+		floodRetries: orDefault(cfg.FloodRetries, defaultFloodRetries),
+		globalRate:   orDefault(cfg.GlobalRate, defaultGlobalRate),
+		chatRate:     orDefault(cfg.ChatRate, defaultChatRate),
+		groupRate:    orDefault(cfg.GroupRate, defaultGroupRate),
+		chats:        make(map[string]*window),
+		wake:         make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+		now:          time.Now,
+	}
+	q.global = newWindow(q.globalRate, time.Second)
+	return q
+}
+
+// Start runs the dispatcher until Stop
+func (q *Queue) Start() {
+	go q.run()
+}
+
+// Stop fails the messages still waiting with ErrQueueStopped
+func (q *Queue) Stop() {
+	q.once.Do(func() {
+		close(q.stop)
+	})
+}
+
+// Send sends the message once the limits of its chat allow it, ctx only bounds the wait in the queue
+func (q *Queue) Send(ctx context.Context, priority Priority, to tele.Recipient, what interface{}, opts ...interface{}) (*tele.Message, error) {
+	if to == nil {
+		return nil, tele.ErrBadRecipient
+	}
+	chat := to.Recipient()
+	for attempt := 1; ; attempt++ {
+		if err := q.acquire(ctx, priority, chat); err != nil {
+			return nil, err
+		}
+
+		msg, err := q.bot.Send(to, what, opts...)
+		var flood tele.FloodError
+		if !errors.As(err, &flood) || attempt > q.floodRetries {
+			q.count(err)
+			return msg, err
+		}
+		retryAfter := time.Duration(flood.RetryAfter) * time.Second
+		q.log.Warn("telegram flood limit reached, pausing chat",
+			logger.String("chat", chat),
+			logger.Duration("retry_after", retryAfter),
+			logger.Int("attempt", attempt))
+		q.pause(chat, retryAfter)
+	}
+}
+
+// Stats returns the messages waiting per priority and the counters since start
+func (q *Queue) Stats() Stats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	paused := 0
+	for _, w := range q.chats {
+		if w.pausedUntil.After(now) {
+			paused++
+		}
+	}
+	return Stats{
+		Interactive: len(q.lanes[Interactive]),
+		Bulk:        len(q.lanes[Bulk]),
+		Sent:        q.sent,
+		Failed:      q.failed,
+		Throttled:   q.throttled,
+		PausedChats: paused,
+	}
+}
+
+// acquire waits for the turn of the message
+func (q *Queue) acquire(ctx context.Context, priority Priority, chat string) error {
+	t := &ticket{chat: chat, ready: make(chan struct{})}
+	q.mu.Lock()
+	q.lanes[priority] = append(q.lanes[priority], t)
+	q.mu.Unlock()
+	q.signal()
+
+	select {
+	case <-t.ready:
+		return nil
+	case <-ctx.Done():
+		q.remove(priority, t)
+		return ctx.Err()
+	case <-q.stop:
+		return ErrQueueStopped
+	}
+}
+
+func (q *Queue) run() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		q.mu.Lock()
+		wait := q.dispatch(q.now())
+		q.mu.Unlock()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		var tick <-chan time.Time
+		if wait > 0 {
+			timer.Reset(wait)
+			tick = timer.C
+		}
+		select {
+		case <-q.stop:
+			return
+		case <-q.wake:
+		case <-tick:
+		}
+	}
+}
+
+// dispatch admits every message allowed at now, in priority then arrival order, a message held by the limit of its
+// chat does not hold the messages of the other chats, it returns how long until the next message may be admitted,
+// 0 when nothing waits
+func (q *Queue) dispatch(now time.Time) time.Duration {
+	q.prune(now)
+	for {
+		next := q.global.allowAt(now)
+		if next.After(now) {
+			if q.waiting() {
+				return next.Sub(now)
+			}
+			return 0
+		}
+
+		var earliest time.Time
+		granted := false
+		for p := range q.lanes {
+			for i, t := range q.lanes[p] {
+				w := q.window(t.chat)
+				at := w.allowAt(now)
+				if at.After(now) {
+					if earliest.IsZero() || at.Before(earliest) {
+						earliest = at
+					}
+					continue
+				}
+				q.lanes[p] = append(q.lanes[p][:i], q.lanes[p][i+1:]...)
+				w.record(now)
+				q.global.record(now)
+				close(t.ready)
+				granted = true
+				break
+			}
+			if granted {
+				break
+			}
+		}
+		if !granted {
+			if earliest.IsZero() {
+				return 0
+			}
+			return earliest.Sub(now)
+		}
+	}
+}
+
+func (q *Queue) waiting() bool {
+	for p := range q.lanes {
+		if len(q.lanes[p]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// window returns the limit of the chat, group and channel ids are negative or usernames
+func (q *Queue) window(chat string) *window {
+	w, ok := q.chats[chat]
+	if !ok {
+		if strings.HasPrefix(chat, "-") || strings.HasPrefix(chat, "@") {
+			w = newWindow(q.groupRate, time.Minute)
+		} else {
+			w = newWindow(q.chatRate, time.Second)
+		}
+		q.chats[chat] = w
+	}
+	return w
+}
+
+func (q *Queue) pause(chat string, d time.Duration) {
+	q.mu.Lock()
+	q.throttled++
+	q.window(chat).pausedUntil = q.now().Add(d)
+	q.mu.Unlock()
+	q.signal()
+}
+
+func (q *Queue) remove(priority Priority, t *ticket) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, waiting := range q.lanes[priority] {
+		if waiting == t {
+			q.lanes[priority] = append(q.lanes[priority][:i], q.lanes[priority][i+1:]...)
+			return
+		}
+	}
+}
+
+func (q *Queue) count(err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err != nil {
+		q.failed++
+		return
+	}
+	q.sent++
+}
+
+// prune drops the limits of the chats idle for a whole window
+func (q *Queue) prune(now time.Time) {
+	if now.Sub(q.pruned) < windowPruneInterval {
+		return
+	}
+	q.pruned = now
+	for chat, w := range q.chats {
+		if w.idle(now) {
+			delete(q.chats, chat)
+		}
+	}
+}
+
+func (q *Queue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func orDefault(value, fallback int) int {
+	if value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// window sliding window of at most limit messages per period
+type window struct {
+	limit       int
+	period      time.Duration
+	sent        []time.Time
+	pausedUntil time.Time
+}
+
+func newWindow(limit int, period time.Duration) *window {
+	return &window{limit: limit, period: period}
+}
+
+// allowAt returns when the window admits the next message
+func (w *window) allowAt(now time.Time) time.Time {
+	at := now
+	if len(w.sent) >= w.limit {
+		at = w.sent[len(w.sent)-w.limit].Add(w.period)
+	}
+	if w.pausedUntil.After(at) {
+		at = w.pausedUntil
+	}
+	return at
+}
+
+func (w *window) record(now time.Time) {
+	w.sent = append(w.sent, now)
+	if len(w.sent) > w.limit {
+		w.sent = w.sent[len(w.sent)-w.limit:]
+	}
+}
+
+func (w *window) idle(now time.Time) bool {
+	return !w.pausedUntil.After(now) && (len(w.sent) == 0 || now.Sub(w.sent[len(w.sent)-1]) >= w.period)
+}
@@ -0,0 +1,110 @@
+package outbound
+
+import (
+	"github.com/stretchr/testify/assert"
+	tele "gopkg.in/telebot.v3"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"testing"
+	"time"
+)
+
+func newTestQueue(cfg config.SendQueueConfig) *Queue {
+	q := NewQueue(&cfg, nil, nil)
+	q.pruned = time.Now()
+	return q
+}
+
+func enqueue(q *Queue, priority Priority, chat string) *ticket {
+	t := &ticket{chat: chat, ready: make(chan struct{})}
+	q.lanes[priority] = append(q.lanes[priority], t)
+	return t
+}
+
+func granted(t *ticket) bool {
+	select {
+	case <-t.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestQueue_InteractiveBeforeBulk(t *testing.T) {
+	q := newTestQueue(config.SendQueueConfig{GlobalRate: 1})
+	now := time.Now()
+
+	bulk := enqueue(q, Bulk, "100")
+	reply := enqueue(q, Interactive, "200")
+
+	wait := q.dispatch(now)
+	assert.True(t, granted(reply))
+	assert.False(t, granted(bulk))
+	assert.Equal(t, time.Second, wait)
+
+	q.dispatch(now.Add(time.Second))
+	assert.True(t, granted(bulk))
+}
+
+func TestQueue_ChatLimitDoesNotHoldOtherChats(t *testing.T) {
+	q := newTestQueue(config.SendQueueConfig{})
+	now := time.Now()
+
+	first := enqueue(q, Interactive, "100")
+	second := enqueue(q, Interactive, "100")
+	other := enqueue(q, Bulk, "200")
+
+	wait := q.dispatch(now)
+	assert.True(t, granted(first))
+	assert.False(t, granted(second))
+	assert.True(t, granted(other))
+	assert.Equal(t, time.Second, wait)
+
+	q.dispatch(now.Add(time.Second))
+	assert.True(t, granted(second))
+}
+
+func TestQueue_GroupLimitPerMinute(t *testing.T) {
+	q := newTestQueue(config.SendQueueConfig{GroupRate: 2})
+	now := time.Now()
+
+	tickets := []*ticket{
+		enqueue(q, Interactive, "-1001"),
+		enqueue(q, Interactive, "-1001"),
+		enqueue(q, Interactive, "-1001"),
+	}
+
+	wait := q.dispatch(now)
+	assert.True(t, granted(tickets[0]))
+	assert.True(t, granted(tickets[1]))
+	assert.False(t, granted(tickets[2]))
+	assert.Equal(t, time.Minute, wait)
+}
+
+func TestQueue_PausedChat(t *testing.T) {
+	q := newTestQueue(config.SendQueueConfig{})
+	q.now = func() time.Time { return time.Unix(1000, 0) }
+	q.pause("100", 5*time.Second)
+
+	waiting := enqueue(q, Interactive, "100")
+	assert.Equal(t, 5*time.Second, q.dispatch(q.now()))
+	assert.False(t, granted(waiting))
+	assert.Equal(t, 1, q.Stats().PausedChats)
+
+	q.dispatch(q.now().Add(5 * time.Second))
+	assert.True(t, granted(waiting))
+	assert.Equal(t, uint64(1), q.Stats().Throttled)
+}
+
+func TestReplyOptions(t *testing.T) {
+	msg := &tele.Message{ID: 1}
+
+	opts := replyOptions(msg, []interface{}{&tele.SendOptions{ParseMode: tele.ModeMarkdownV2}, tele.Silent})
+	sendOpts := opts[0].(*tele.SendOptions)
+	assert.Equal(t, msg, sendOpts.ReplyTo)
+	assert.Equal(t, tele.ModeMarkdownV2, sendOpts.ParseMode)
+	assert.Equal(t, tele.Silent, opts[1])
+
+	opts = replyOptions(msg, []interface{}{tele.NoPreview})
+	assert.Equal(t, msg, opts[0].(*tele.SendOptions).ReplyTo)
+	assert.Equal(t, tele.NoPreview, opts[1])
+}
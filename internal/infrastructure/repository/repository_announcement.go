@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+type AnnouncementRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewAnnouncementRepository(db *gorm.DB, log logger.Logger) AnnouncementRepository {
+	return &AnnouncementRepositoryImpl{db: db, log: log}
+}
+
+func (r *AnnouncementRepositoryImpl) Create(ctx context.Context, tx *gorm.DB, announcement *model.Announcement) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	if err := db.WithContext(ctx).Create(announcement).Error; err != nil {
+		return fmt.Errorf("failed to create announcement with group_id=%d: %w", announcement.GroupID, err)
+	}
+	return nil
+}
+
+func (r *AnnouncementRepositoryImpl) Update(ctx context.Context, tx *gorm.DB, announcement *model.Announcement) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).
+		Model(announcement).
+		Select("group_id", "thread_id", "text", "parse_mode", "image_url", "cron", "run_at", "next_run_at", "pin", "delete_after", "enabled").
+		Updates(announcement)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update announcement with id=%d: %w", announcement.ID, result.Error)
+	}
+	return nil
+}
+
+func (r *AnnouncementRepositoryImpl) Delete(ctx context.Context, tx *gorm.DB, id int64) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).Delete(&model.Announcement{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete announcement with id=%d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *AnnouncementRepositoryImpl) FindById(ctx context.Context, tx *gorm.DB, id int64) (*model.Announcement, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var announcement model.Announcement
+	result := db.WithContext(ctx).First(&announcement, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to find announcement with id=%d: %w", id, result.Error)
+	}
+	return &announcement, nil
+}
+
+// FindAll returns the announcements of the group, every announcement when groupId is 0
+func (r *AnnouncementRepositoryImpl) FindAll(ctx context.Context, tx *gorm.DB, groupId int64) ([]*model.Announcement, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	query := db.WithContext(ctx)
+	if groupId != 0 {
+		query = query.Where("group_id = ?", groupId)
+	}
+
+	var announcements []*model.Announcement
+	if err := query.Order("group_id, id").Find(&announcements).Error; err != nil {
+		return nil, fmt.Errorf("failed to find announcements: %w", err)
+	}
+	return announcements, nil
+}
+
+// FindDue locks the enabled announcements due at now, rows locked by another worker are skipped,
+// it has to be called inside a transaction
+func (r *AnnouncementRepositoryImpl) FindDue(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]*model.Announcement, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var announcements []*model.Announcement
+	err := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("enabled = ? AND next_run_at <= ?", true, now).
+		Order("next_run_at").
+		Limit(limit).
+		Find(&announcements).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due announcements: %w", err)
+	}
+	return announcements, nil
+}
+
+// Reschedule sets the next run of the announcement, nil stops it
+func (r *AnnouncementRepositoryImpl) Reschedule(ctx context.Context, tx *gorm.DB, id int64, nextRunAt *time.Time) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	err := db.WithContext(ctx).
+		Model(&model.Announcement{}).
+		Where("id = ?", id).
+		Update("next_run_at", nextRunAt).Error
+	if err != nil {
+		return fmt.Errorf("failed to reschedule announcement with id=%d: %w", id, err)
+	}
+	return nil
+}
+
+type AnnouncementRunRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewAnnouncementRunRepository(db *gorm.DB, log logger.Logger) AnnouncementRunRepository {
+	return &AnnouncementRunRepositoryImpl{db: db, log: log}
+}
+
+func (r *AnnouncementRunRepositoryImpl) Create(ctx context.Context, tx *gorm.DB, run *model.AnnouncementRun) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	if err := db.WithContext(ctx).Create(run).Error; err != nil {
+		return fmt.Errorf("failed to create announcement run with announcement_id=%d: %w", run.AnnouncementID, err)
+	}
+	return nil
+}
+
+// FindAll returns a page of the runs of the announcement, newest first, with the total count,
+// every announcement when announcementId is 0
+func (r *AnnouncementRunRepositoryImpl) FindAll(ctx context.Context, tx *gorm.DB, announcementId int64, page, limit int) ([]*model.AnnouncementRun, int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	query := db.WithContext(ctx).Model(&model.AnnouncementRun{})
+	if announcementId != 0 {
+		query = query.Where("announcement_id = ?", announcementId)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count announcement runs: %w", err)
+	}
+
+	var runs []*model.AnnouncementRun
+	err := query.
+		Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&runs).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find announcement runs: %w", err)
+	}
+	return runs, total, nil
+}
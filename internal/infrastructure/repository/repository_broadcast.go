@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+type BroadcastJobRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewBroadcastJobRepository(db *gorm.DB, log logger.Logger) BroadcastJobRepository {
+	return &BroadcastJobRepositoryImpl{db: db, log: log}
+}
+
+func (r *BroadcastJobRepositoryImpl) Create(ctx context.Context, tx *gorm.DB, job *model.BroadcastJob) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	if err := db.WithContext(ctx).Create(job).Error; err != nil {
+		return fmt.Errorf("failed to create broadcast job with audience=%s: %w", job.Audience, err)
+	}
+	return nil
+}
+
+func (r *BroadcastJobRepositoryImpl) FindById(ctx context.Context, tx *gorm.DB, id int64) (*model.BroadcastJob, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var job model.BroadcastJob
+	result := db.WithContext(ctx).First(&job, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to find broadcast job with id=%d: %w", id, result.Error)
+	}
+	return &job, nil
+}
+
+// FindForUpdate locks the job, it has to be called inside a transaction
+func (r *BroadcastJobRepositoryImpl) FindForUpdate(ctx context.Context, tx *gorm.DB, id int64) (*model.BroadcastJob, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var job model.BroadcastJob
+	result := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&job, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to find broadcast job with id=%d: %w", id, result.Error)
+	}
+	return &job, nil
+}
+
+// FindAll returns a page of the jobs, newest first, with the total count
+func (r *BroadcastJobRepositoryImpl) FindAll(ctx context.Context, tx *gorm.DB, page, limit int) ([]*model.BroadcastJob, int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var total int64
+	if err := db.WithContext(ctx).Model(&model.BroadcastJob{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count broadcast jobs: %w", err)
+	}
+
+	var jobs []*model.BroadcastJob
+	err := db.WithContext(ctx).
+		Order("id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find broadcast jobs: %w", err)
+	}
+	return jobs, total, nil
+}
+
+// FindActive returns the jobs with recipients left to send, oldest first
+func (r *BroadcastJobRepositoryImpl) FindActive(ctx context.Context, tx *gorm.DB) ([]*model.BroadcastJob, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var jobs []*model.BroadcastJob
+	err := db.WithContext(ctx).
+		Where("status IN ?", []common.BroadcastStatus{common.BroadcastPending, common.BroadcastRunning}).
+		Order("id").
+		Find(&jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active broadcast jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func (r *BroadcastJobRepositoryImpl) UpdateStatus(ctx context.Context, tx *gorm.DB, id int64, status common.BroadcastStatus, finishedAt *time.Time) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	err := db.WithContext(ctx).
+		Model(&model.BroadcastJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"finished_at": finishedAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update broadcast job status with id=%d: %w", id, err)
+	}
+	return nil
+}
+
+type BroadcastRecipientRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewBroadcastRecipientRepository(db *gorm.DB, log logger.Logger) BroadcastRecipientRepository {
+	return &BroadcastRecipientRepositoryImpl{db: db, log: log}
+}
+
+func (r *BroadcastRecipientRepositoryImpl) CreateInBatches(ctx context.Context, tx *gorm.DB, batchSize int, recipients []*model.BroadcastRecipient) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	if err := db.WithContext(ctx).CreateInBatches(recipients, batchSize).Error; err != nil {
+		return fmt.Errorf("failed to batch create broadcast recipients: %w", err)
+	}
+	return nil
+}
+
+// FindDue locks the pending recipients of the job whose next attempt is due at now, rows locked by another worker
+// are skipped, it has to be called inside a transaction
+func (r *BroadcastRecipientRepositoryImpl) FindDue(ctx context.Context, tx *gorm.DB, jobId int64, now time.Time, limit int) ([]*model.BroadcastRecipient, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var recipients []*model.BroadcastRecipient
+	err := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("job_id = ? AND status = ? AND next_attempt_at <= ?", jobId, common.RecipientPending, now).
+		Order("id").
+		Limit(limit).
+		Find(&recipients).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due broadcast recipients with job_id=%d: %w", jobId, err)
+	}
+	return recipients, nil
+}
+
+// Postpone moves the next attempt of the recipients to nextAttemptAt and counts an attempt
+func (r *BroadcastRecipientRepositoryImpl) Postpone(ctx context.Context, tx *gorm.DB, ids []int64, nextAttemptAt time.Time) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := db.WithContext(ctx).
+		Model(&model.BroadcastRecipient{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"next_attempt_at": nextAttemptAt,
+			"attempts":        gorm.Expr("attempts + 1"),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to postpone broadcast recipients with ids=%v: %w", ids, err)
+	}
+	return nil
+}
+
+// Finish sets the delivery result of a pending recipient, a recipient skipped by a cancellation meanwhile is left as is
+func (r *BroadcastRecipientRepositoryImpl) Finish(ctx context.Context, tx *gorm.DB, id int64, status common.BroadcastRecipientStatus, lastError string, sentAt *time.Time) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	err := db.WithContext(ctx).
+		Model(&model.BroadcastRecipient{}).
+		Where("id = ? AND status = ?", id, common.RecipientPending).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"last_error": lastError,
+			"sent_at":    sentAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to finish broadcast recipient with id=%d: %w", id, err)
+	}
+	return nil
+}
+
+// SkipPending gives up the recipients of the job not sent yet
+func (r *BroadcastRecipientRepositoryImpl) SkipPending(ctx context.Context, tx *gorm.DB, jobId int64) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	err := db.WithContext(ctx).
+		Model(&model.BroadcastRecipient{}).
+		Where("job_id = ? AND status = ?", jobId, common.RecipientPending).
+		Update("status", common.RecipientSkipped).Error
+	if err != nil {
+		return fmt.Errorf("failed to skip pending broadcast recipients with job_id=%d: %w", jobId, err)
+	}
+	return nil
+}
+
+func (r *BroadcastRecipientRepositoryImpl) CountByStatus(ctx context.Context, tx *gorm.DB, jobId int64) (map[common.BroadcastRecipientStatus]int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var rows []struct {
+		Status common.BroadcastRecipientStatus
+		Count  int64
+	}
+	err := db.WithContext(ctx).
+		Model(&model.BroadcastRecipient{}).
+		Select("status, COUNT(*) AS count").
+		Where("job_id = ?", jobId).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count broadcast recipients with job_id=%d: %w", jobId, err)
+	}
+
+	counts := make(map[common.BroadcastRecipientStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"testing"
+	"time"
+)
+
+// audienceMember a bound user of the audience fixture with its latest monthly volume
+type audienceMember struct {
+	tenant   string
+	userId   string
+	platform common.SocialPlatformType
+	active   bool
+	status   common.Status
+	volume   float64
+	tradedAt time.Time
+}
+
+// newAdminDB returns a client on the test database unbound to any tenant like the one of the admin api, the
+// statements take the tenant from their context
+func newAdminDB(t *testing.T) *gorm.DB {
+	sqlDB, err := testDB.DB()
+	require.NoError(t, err)
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(database.NewTenantPlugin("")))
+	return db
+}
+
+// seedAudience creates the members with their bindings and volume, it returns the customer id of every user id
+func seedAudience(t *testing.T, db *gorm.DB, members []audienceMember) map[string]string {
+	require.NoError(t, db.Create(&[]model.SocialPlatform{
+		{Id: int(common.Telegram), Name: common.Telegram.Name(), IsActive: true},
+		{Id: int(common.Line), Name: common.Line.Name(), IsActive: true},
+	}).Error)
+	require.NoError(t, db.Create(&model.TradingPlatform{Id: 1, Name: "BITGET", IsActive: true}).Error)
+
+	customers := make(map[string]string, len(members))
+	for _, member := range members {
+		customer := &model.Customer{TenantID: member.tenant, Username: member.userId}
+		require.NoError(t, db.Create(customer).Error)
+		customers[member.userId] = customer.Id
+
+		require.NoError(t, db.Create(&model.CustomerSocialBinding{
+			TenantID:     member.tenant,
+			CustomerID:   customer.Id,
+			SocialID:     int(member.platform),
+			UserID:       member.userId,
+			IsActive:     member.active,
+			MemberStatus: common.Member,
+			Status:       member.status,
+		}).Error)
+		// the default of is_active replaces a false value on create
+		require.NoError(t, db.Model(&model.CustomerSocialBinding{}).
+			Where("customer_id = ?", customer.Id).
+			Update("is_active", member.active).Error)
+
+		binding := &model.CustomerTradingBinding{
+			TenantID:     member.tenant,
+			CustomerID:   customer.Id,
+			TradingID:    1,
+			UID:          "uid-" + member.userId,
+			RegisterTime: time.Now().Format(time.RFC3339),
+		}
+		require.NoError(t, db.Create(binding).Error)
+		require.NoError(t, db.Create(&model.TradingHistory{
+			TenantID:    member.tenant,
+			BindingID:   binding.ID,
+			Volume:      member.volume,
+			TimePeriod:  common.MonthlyTrading,
+			TradingDate: member.tradedAt,
+		}).Error)
+	}
+	return customers
+}
+
+func TestCustomerSocialBindingRepository_FindAudience(t *testing.T) {
+	cleanup(t, testDB)
+	defer cleanup(t, testDB)
+
+	now := time.Now()
+	since := now.Add(-31 * 24 * time.Hour)
+	customers := seedAudience(t, testDB, []audienceMember{
+		{tenant: "alpha", userId: "101", platform: common.Telegram, active: true, status: common.Normal, volume: 5000, tradedAt: now},
+		{tenant: "alpha", userId: "102", platform: common.Telegram, active: true, status: common.Whitelisted, volume: 20000, tradedAt: now},
+		{tenant: "alpha", userId: "103", platform: common.Telegram, active: true, status: common.Blacklisted, volume: 50000, tradedAt: now},
+		{tenant: "alpha", userId: "104", platform: common.Telegram, active: false, status: common.Normal, volume: 8000, tradedAt: now},
+		{tenant: "alpha", userId: "U105", platform: common.Line, active: true, status: common.Normal, volume: 9000, tradedAt: now},
+		{tenant: "alpha", userId: "106", platform: common.Telegram, active: true, status: common.Normal, volume: 30000, tradedAt: now.AddDate(0, 0, -60)},
+		{tenant: "beta", userId: "201", platform: common.Telegram, active: true, status: common.Normal, volume: 10000, tradedAt: now},
+	})
+
+	tests := []struct {
+		name    string
+		filter  model.AudienceFilter
+		userIds []string
+	}{
+		{
+			name:    "all active skips the blacklisted, the deactivated, the other platforms and tenants",
+			filter:  model.AudienceFilter{Audience: string(common.BroadcastAllActive)},
+			userIds: []string{"101", "102", "106"},
+		},
+		{
+			name:    "deactivated",
+			filter:  model.AudienceFilter{Audience: string(common.BroadcastDeactivated)},
+			userIds: []string{"104"},
+		},
+		{
+			name:    "whitelisted",
+			filter:  model.AudienceFilter{Audience: string(common.BroadcastWhitelisted)},
+			userIds: []string{"102"},
+		},
+		{
+			name:    "tier with min volume only counts the volumes recorded since",
+			filter:  model.AudienceFilter{Audience: string(common.BroadcastTier), MinVolume: 1000},
+			userIds: []string{"101", "102"},
+		},
+		{
+			name:    "tier min volume is inclusive",
+			filter:  model.AudienceFilter{Audience: string(common.BroadcastTier), MinVolume: 20000},
+			userIds: []string{"102"},
+		},
+		{
+			name:    "tier max volume is exclusive",
+			filter:  model.AudienceFilter{Audience: string(common.BroadcastTier), MinVolume: 1000, MaxVolume: 20000},
+			userIds: []string{"101"},
+		},
+		{
+			name: "custom keeps the deactivated and skips the other platforms",
+			filter: model.AudienceFilter{
+				Audience:    string(common.BroadcastCustom),
+				CustomerIds: []string{customers["101"], customers["104"], customers["U105"], customers["201"]},
+			},
+			userIds: []string{"101", "104"},
+		},
+	}
+
+	// the admin api runs on a client unbound to any tenant, the subqueries have to be scoped as well
+	repo := NewCustomerSocialRepository(newAdminDB(t), logger.NewLogger())
+	ctx := database.WithTenant(context.Background(), "alpha")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bindings, err := repo.FindAudience(ctx, nil, &tt.filter, since)
+
+			require.NoError(t, err)
+			userIds := make([]string, 0, len(bindings))
+			for _, binding := range bindings {
+				userIds = append(userIds, binding.UserID)
+			}
+			assert.Equal(t, tt.userIds, userIds)
+		})
+	}
+
+	_, err := repo.FindAudience(ctx, nil, &model.AudienceFilter{Audience: "everyone"}, since)
+	assert.Error(t, err)
+}
+
+func TestBroadcastRecipientRepository_SkipPending(t *testing.T) {
+	cleanup(t, testDB)
+	defer cleanup(t, testDB)
+
+	db := newAdminDB(t)
+	ctx := database.WithTenant(context.Background(), "alpha")
+	jobRepo := NewBroadcastJobRepository(db, logger.NewLogger())
+	recipientRepo := NewBroadcastRecipientRepository(db, logger.NewLogger())
+
+	job := &model.BroadcastJob{Audience: common.BroadcastAllActive, Text: "hello", Status: common.BroadcastRunning, Total: 3}
+	require.NoError(t, jobRepo.Create(ctx, nil, job))
+	now := time.Now()
+	recipients := []*model.BroadcastRecipient{
+		{JobID: job.ID, UserID: "101", Status: common.RecipientPending, NextAttemptAt: now},
+		{JobID: job.ID, UserID: "102", Status: common.RecipientSent, NextAttemptAt: now, SentAt: &now},
+		{JobID: job.ID, UserID: "103", Status: common.RecipientFailed, NextAttemptAt: now},
+		{JobID: job.ID, UserID: "104", Status: common.RecipientPending, NextAttemptAt: now},
+	}
+	require.NoError(t, recipientRepo.CreateInBatches(ctx, nil, 10, recipients))
+
+	require.NoError(t, recipientRepo.SkipPending(ctx, nil, job.ID))
+	// a send finishing after the cancellation leaves the recipient skipped
+	require.NoError(t, recipientRepo.Finish(ctx, nil, recipients[0].ID, common.RecipientSent, "", &now))
+
+	counts, err := recipientRepo.CountByStatus(ctx, nil, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[common.BroadcastRecipientStatus]int64{
+		common.RecipientSkipped: 2,
+		common.RecipientSent:    1,
+		common.RecipientFailed:  1,
+	}, counts)
+
+	due, err := recipientRepo.FindDue(ctx, nil, job.ID, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	counts, err = recipientRepo.CountByStatus(database.WithTenant(context.Background(), "beta"), nil, job.ID)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
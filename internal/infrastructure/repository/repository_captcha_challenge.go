@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+type CaptchaChallengeRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewCaptchaChallengeRepository(db *gorm.DB, log logger.Logger) CaptchaChallengeRepository {
+	return &CaptchaChallengeRepositoryImpl{db: db, log: log}
+}
+
+// Upsert saves the challenge of the member, a member joining again replaces the previous challenge
+func (r *CaptchaChallengeRepositoryImpl) Upsert(ctx context.Context, tx *gorm.DB, challenge *model.CaptchaChallenge) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "group_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"message_id", "answer", "status", "expire_at", "updated_at"}),
+		}).
+		Create(challenge)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save captcha challenge with group_id=%d, user_id=%d, error=%w",
+			challenge.GroupID, challenge.UserID, result.Error)
+	}
+	return nil
+}
+
+func (r *CaptchaChallengeRepositoryImpl) FindPending(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) (*model.CaptchaChallenge, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var challenge model.CaptchaChallenge
+	result := db.WithContext(ctx).
+		Where("group_id = ? AND user_id = ? AND status = ?", groupId, userId, common.CaptchaPending).
+		First(&challenge)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to find captcha challenge: %w", result.Error)
+	}
+	return &challenge, nil
+}
+
+func (r *CaptchaChallengeRepositoryImpl) FindExpired(ctx context.Context, tx *gorm.DB, before time.Time, limit int) ([]*model.CaptchaChallenge, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var challenges []*model.CaptchaChallenge
+	result := db.WithContext(ctx).
+		Where("status = ? AND expire_at <= ?", common.CaptchaPending, before).
+		Order("expire_at").
+		Limit(limit).
+		Find(&challenges)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find expired captcha challenges: %w", result.Error)
+	}
+	return challenges, nil
+}
+
+// Resolve moves a pending challenge to status, ErrRecordNotFound is returned when it was resolved already
+func (r *CaptchaChallengeRepositoryImpl) Resolve(ctx context.Context, tx *gorm.DB, id int64, status common.CaptchaStatus) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).
+		Model(&model.CaptchaChallenge{}).
+		Where("id = ? AND status = ?", id, common.CaptchaPending).
+		Update("status", status)
+	if result.Error != nil {
+		return fmt.Errorf("failed to resolve captcha challenge with id=%d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
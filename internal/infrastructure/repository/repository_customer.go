@@ -42,6 +42,29 @@ func (r *CustomerRepositoryImpl) DeleteCustomer(ctx context.Context, tx *gorm.DB
 	return ids, nil
 }
 
+func (r *CustomerRepositoryImpl) CountCustomerStats(ctx context.Context, tx *gorm.DB) (*model.CustomerStats, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var stats model.CustomerStats
+	err := db.WithContext(ctx).
+		Model(&model.CustomerSocialBinding{}).
+		Select(`
+			COUNT(*) as total,
+			COALESCE(SUM(is_active = true), 0) as active,
+			COALESCE(SUM(is_active = false), 0) as inactive,
+			COALESCE(SUM(status = 'normal'), 0) as normal,
+			COALESCE(SUM(status = 'whitelisted'), 0) as whitelisted,
+			COALESCE(SUM(status = 'blacklisted'), 0) as blacklisted`).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count customer stats: %w", err)
+	}
+	return &stats, nil
+}
+
 func (r *CustomerRepositoryImpl) FindAllCustomers(ctx context.Context, tx *gorm.DB, page, limit int) ([]*model.CustomerWithBindings, int64, error) {
 	db := tx
 	if db == nil {
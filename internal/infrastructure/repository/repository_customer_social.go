@@ -39,6 +39,44 @@ func (r *CustomerSocialBindingRepositoryImpl) UpdateCustomerStatus(ctx context.C
 
 }
 
+func (r *CustomerSocialBindingRepositoryImpl) UpdateStatusByCustomerId(ctx context.Context, tx *gorm.DB, customerId string, updates map[string]interface{}) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	updates["updated_at"] = time.Now()
+	result := db.WithContext(ctx).
+		Model(&model.CustomerSocialBinding{}).
+		Where("customer_id = ?", customerId).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update customer social binding with customer_id=%s: %w", customerId, result.Error)
+	}
+	return nil
+}
+
+// FindByUserId returns the binding of a social user, the active binding wins when the user has several
+func (r *CustomerSocialBindingRepositoryImpl) FindByUserId(ctx context.Context, tx *gorm.DB, socialId int, userId string) (*model.CustomerSocialBinding, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var binding model.CustomerSocialBinding
+	result := db.WithContext(ctx).
+		Where("social_id = ? AND user_id = ?", socialId, userId).
+		Order("is_active DESC, updated_at DESC").
+		First(&binding)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to find social binding with user_id=%s: %w", userId, result.Error)
+	}
+	return &binding, nil
+}
+
 func (r *CustomerSocialBindingRepositoryImpl) FindSocialBindingByCustomerId(ctx context.Context, tx *gorm.DB, customerId string) (*model.CustomerSocialBinding, error) {
 	db := tx
 	if db == nil {
@@ -68,7 +106,7 @@ func (r *CustomerSocialBindingRepositoryImpl) FindStatusByUid(ctx context.Contex
 		IsActive bool `gorm:"column:is_active"`
 	}
 	var value Active
-	subQuery := db.Table("customer_trading_bindings").Select("customer_id").Where("uid = ?", uid)
+	subQuery := db.WithContext(ctx).Table("customer_trading_bindings").Select("customer_id").Where("uid = ?", uid)
 	result := db.WithContext(ctx).
 		Table("customer_social_bindings").
 		Select("is_active").
@@ -81,15 +119,15 @@ func (r *CustomerSocialBindingRepositoryImpl) FindStatusByUid(ctx context.Contex
 	return value.IsActive, nil
 }
 
-func (r *CustomerSocialBindingRepositoryImpl) UpdateUserByUid(ctx context.Context, tx *gorm.DB, uid string, userInfo map[string]interface{}) error {
+func (r *CustomerSocialBindingRepositoryImpl) UpdateUserByUid(ctx context.Context, tx *gorm.DB, uid string, platform common.SocialPlatformType, userInfo map[string]interface{}) error {
 	db := tx
 	if db == nil {
 		db = r.db
 	}
-	subQuery := db.Table("customer_trading_bindings").Select("customer_id").Where("uid = ?", uid)
+	subQuery := db.WithContext(ctx).Table("customer_trading_bindings").Select("customer_id").Where("uid = ?", uid)
 	result := db.WithContext(ctx).
 		Table("customer_social_bindings").
-		Where("customer_id IN (?)", subQuery).
+		Where("customer_id IN (?) AND social_id = ?", subQuery, platform.Value()).
 		Updates(userInfo)
 
 	if result.Error != nil {
@@ -117,6 +155,46 @@ func (r *CustomerSocialBindingRepositoryImpl) Create(ctx context.Context, tx *go
 	return binding, nil
 }
 
+// FindAudience returns the telegram bindings of the broadcast audience, the tier audience is matched against the
+// monthly trading volumes recorded since since
+func (r *CustomerSocialBindingRepositoryImpl) FindAudience(ctx context.Context, tx *gorm.DB, filter *model.AudienceFilter, since time.Time) ([]*model.CustomerSocialBinding, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	query := db.WithContext(ctx).
+		Omit("Customer", "Platform").
+		Where("social_id = ?", common.Telegram)
+	switch common.BroadcastAudience(filter.Audience) {
+	case common.BroadcastAllActive:
+		query = query.Where("is_active = ? AND status <> ?", true, common.Blacklisted)
+	case common.BroadcastDeactivated:
+		query = query.Where("is_active = ?", false)
+	case common.BroadcastWhitelisted:
+		query = query.Where("status = ?", common.Whitelisted)
+	case common.BroadcastTier:
+		volumes := db.WithContext(ctx).Table("customer_trading_bindings AS b").
+			Select("b.customer_id").
+			Joins("JOIN trading_histories AS h ON h.binding_id = b.id").
+			Where("h.time_period = ? AND h.trading_date >= ? AND h.volume >= ?", common.MonthlyTrading, since, filter.MinVolume)
+		if filter.MaxVolume > 0 {
+			volumes = volumes.Where("h.volume < ?", filter.MaxVolume)
+		}
+		query = query.Where("is_active = ? AND status <> ? AND customer_id IN (?)", true, common.Blacklisted, volumes)
+	case common.BroadcastCustom:
+		query = query.Where("customer_id IN ?", filter.CustomerIds)
+	default:
+		return nil, fmt.Errorf("unsupported broadcast audience %q", filter.Audience)
+	}
+
+	var bindings []*model.CustomerSocialBinding
+	if err := query.Order("id").Find(&bindings).Error; err != nil {
+		return nil, fmt.Errorf("failed to find %s audience: %w", filter.Audience, err)
+	}
+	return bindings, nil
+}
+
 func NewCustomerSocialRepository(db *gorm.DB, log logger.Logger) CustomerSocialBindingRepository {
 	return &CustomerSocialBindingRepositoryImpl{db: db, log: log}
 }
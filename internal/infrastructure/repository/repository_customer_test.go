@@ -84,6 +84,9 @@ func TestMain(m *testing.M) {
 		&model.Customer{},
 		&model.CustomerSocialBinding{},
 		&model.CustomerTradingBinding{},
+		&model.TradingHistory{},
+		&model.BroadcastJob{},
+		&model.BroadcastRecipient{},
 	)
 	if err != nil {
 		log.Fatalf("Could not migrate database: %s", err)
@@ -311,6 +314,9 @@ func cleanup(t *testing.T, db *gorm.DB) {
 
 	// 清理表（注意顺序）
 	tables := []string{
+		"broadcast_recipients",
+		"broadcast_jobs",
+		"trading_histories",
 		"customer_social_bindings",
 		"customer_trading_bindings",
 		"customers",
@@ -70,6 +70,34 @@ func (r *CustomerTradingBindingRepositoryImpl) FindTradingBindingByUid(
 		db = r.db
 	}
 
+	info, err := r.findCustomerInfo(ctx, db, "t.uid = ?", uid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find customer social binding with uid=%s, err=%w", uid, err)
+	}
+	return info, nil
+}
+
+func (r *CustomerTradingBindingRepositoryImpl) FindTradingBindingBySocialUsername(
+	ctx context.Context,
+	tx *gorm.DB, username string) (*model.CustomerInfoResponse, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	info, err := r.findCustomerInfo(ctx, db, "s.username = ?", username)
+	if err != nil {
+		if isRecordNotFound(err) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to find customer social binding with username=%s, err=%w", username, err)
+	}
+	return info, nil
+}
+
+func (r *CustomerTradingBindingRepositoryImpl) findCustomerInfo(
+	ctx context.Context,
+	db *gorm.DB, query string, args ...interface{}) (*model.CustomerInfoResponse, error) {
 	var result struct {
 		// Customer
 		CustomerID        string    `gorm:"column:customer_id"`
@@ -116,15 +144,11 @@ func (r *CustomerTradingBindingRepositoryImpl) FindTradingBindingByUid(
 		Joins(`JOIN customer_social_bindings s ON c.id = s.customer_id`).
 		Joins(`JOIN social_platforms sp ON s.social_id = sp.id`).
 		Joins(`JOIN trading_platforms tp ON t.trading_id = tp.id`).
-		Where("t.uid = ?", uid).
+		Where(query, args...).
 		First(&result)
 
-	//var binding *model.CustomerTradingBinding
-	//if err := db.WithContext(ctx).First(&binding, "uid = ?", uid).Error; err != nil {
-	//	return nil, fmt.Errorf("failed to find customer trading binding with uid=%s: %w", uid, err)
-	//}
 	if dbResult.Error != nil {
-		return nil, fmt.Errorf("failed to find customer social binding with uid=%s, err=%w", uid, dbResult.Error)
+		return nil, dbResult.Error
 	}
 	return &model.CustomerInfoResponse{
 		Customer: model.CustomerInfo{
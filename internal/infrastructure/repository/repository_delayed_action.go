@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+type DelayedActionRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewDelayedActionRepository(db *gorm.DB, log logger.Logger) DelayedActionRepository {
+	return &DelayedActionRepositoryImpl{db: db, log: log}
+}
+
+func (r *DelayedActionRepositoryImpl) Create(ctx context.Context, tx *gorm.DB, action *model.DelayedAction) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	if err := db.WithContext(ctx).Create(action).Error; err != nil {
+		return fmt.Errorf("failed to create delayed action with type=%s, chat_id=%d, error=%w", action.Type, action.ChatID, err)
+	}
+	return nil
+}
+
+// FindDue locks the pending actions due at now, rows locked by another worker are skipped,
+// it has to be called inside a transaction
+func (r *DelayedActionRepositoryImpl) FindDue(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]*model.DelayedAction, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var actions []*model.DelayedAction
+	err := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND run_at <= ?", common.DelayedActionPending, now).
+		Order("run_at").
+		Limit(limit).
+		Find(&actions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due delayed actions: %w", err)
+	}
+	return actions, nil
+}
+
+// Postpone moves the actions to runAt and counts an attempt
+func (r *DelayedActionRepositoryImpl) Postpone(ctx context.Context, tx *gorm.DB, ids []int64, runAt time.Time) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := db.WithContext(ctx).
+		Model(&model.DelayedAction{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"run_at":   runAt,
+			"attempts": gorm.Expr("attempts + 1"),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to postpone delayed actions with ids=%v: %w", ids, err)
+	}
+	return nil
+}
+
+func (r *DelayedActionRepositoryImpl) Finish(ctx context.Context, tx *gorm.DB, id int64, status common.DelayedActionStatus, lastError string) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	err := db.WithContext(ctx).
+		Model(&model.DelayedAction{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"last_error": lastError,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to finish delayed action with id=%d: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+type GroupMembershipRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewGroupMembershipRepository(db *gorm.DB, log logger.Logger) GroupMembershipRepository {
+	return &GroupMembershipRepositoryImpl{db: db, log: log}
+}
+
+func (r *GroupMembershipRepositoryImpl) Upsert(ctx context.Context, tx *gorm.DB, membership *model.GroupMembership) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "social_id"}, {Name: "user_id"}, {Name: "group_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"customer_id", "group_title", "member_status", "updated_at"}),
+		}).
+		Create(membership)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save group membership with user_id=%s, group_id=%d, error=%w",
+			membership.UserID, membership.GroupID, result.Error)
+	}
+	return nil
+}
+
+func (r *GroupMembershipRepositoryImpl) FindByCustomerId(ctx context.Context, tx *gorm.DB, customerId string) ([]*model.GroupMembership, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var memberships []*model.GroupMembership
+	result := db.WithContext(ctx).
+		Where("customer_id = ?", customerId).
+		Order("group_id").
+		Find(&memberships)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find group memberships with customer_id=%s: %w", customerId, result.Error)
+	}
+	return memberships, nil
+}
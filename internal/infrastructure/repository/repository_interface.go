@@ -5,6 +5,7 @@ import (
 	"gorm.io/gorm"
 	"ohmycontrolcenter.tech/omcc/internal/common"
 	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"time"
 )
 
 type CustomerRepository interface {
@@ -12,20 +13,25 @@ type CustomerRepository interface {
 	FindById(ctx context.Context, tx *gorm.DB, id string) (*model.Customer, error)
 	FindAllCustomers(ctx context.Context, tx *gorm.DB, page, limit int) ([]*model.CustomerWithBindings, int64, error)
 	DeleteCustomer(ctx context.Context, tx *gorm.DB, ids []string) ([]string, error)
+	CountCustomerStats(ctx context.Context, tx *gorm.DB) (*model.CustomerStats, error)
 }
 
 type CustomerSocialBindingRepository interface {
 	Create(ctx context.Context, tx *gorm.DB, binding *model.CustomerSocialBinding) (*model.CustomerSocialBinding, error)
-	UpdateUserByUid(ctx context.Context, tx *gorm.DB, uid string, userInfo map[string]interface{}) error
+	UpdateUserByUid(ctx context.Context, tx *gorm.DB, uid string, platform common.SocialPlatformType, userInfo map[string]interface{}) error
 	FindStatusByUid(ctx context.Context, tx *gorm.DB, uid string) (bool, error)
 	FindSocialBindingByCustomerId(ctx context.Context, tx *gorm.DB, customerId string) (*model.CustomerSocialBinding, error)
 	UpdateCustomerStatus(ctx context.Context, tx *gorm.DB, customerID string, socialID string, status string, memberStatus common.MemberStatus) error
+	UpdateStatusByCustomerId(ctx context.Context, tx *gorm.DB, customerId string, updates map[string]interface{}) error
+	FindByUserId(ctx context.Context, tx *gorm.DB, socialId int, userId string) (*model.CustomerSocialBinding, error)
+	FindAudience(ctx context.Context, tx *gorm.DB, filter *model.AudienceFilter, since time.Time) ([]*model.CustomerSocialBinding, error)
 }
 
 type CustomerTradingBindingRepository interface {
 	Create(ctx context.Context, tx *gorm.DB, binding *model.CustomerTradingBinding) (*model.CustomerTradingBinding, error)
 	CheckMemberStatus(ctx context.Context, tx *gorm.DB, uid string) (common.MemberStatus, error)
 	FindTradingBindingByUid(ctx context.Context, tx *gorm.DB, uid string) (*model.CustomerInfoResponse, error)
+	FindTradingBindingBySocialUsername(ctx context.Context, tx *gorm.DB, username string) (*model.CustomerInfoResponse, error)
 }
 
 type TradingHistoryRepository interface {
@@ -40,3 +46,115 @@ type TradingPlatformRepository interface {
 type SocialPlatformRepository interface {
 	FindById(ctx context.Context, tx *gorm.DB, id string) (*model.SocialPlatform, error)
 }
+
+type SocialUserLanguageRepository interface {
+	FindByUserId(ctx context.Context, tx *gorm.DB, socialId int, userId string) (*model.SocialUserLanguage, error)
+	Upsert(ctx context.Context, tx *gorm.DB, language *model.SocialUserLanguage) error
+}
+
+type MessageTemplateRepository interface {
+	FindActive(ctx context.Context, tx *gorm.DB) ([]*model.MessageTemplate, error)
+	FindVersions(ctx context.Context, tx *gorm.DB, key string, language string) ([]*model.MessageTemplate, error)
+	CreateVersion(ctx context.Context, tx *gorm.DB, template *model.MessageTemplate) (*model.MessageTemplate, error)
+}
+
+type InviteLinkRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, link *model.InviteLink) (*model.InviteLink, error)
+	FindOutstandingByCustomerId(ctx context.Context, tx *gorm.DB, customerId string) ([]*model.InviteLink, error)
+	FindExpired(ctx context.Context, tx *gorm.DB, before time.Time, limit int) ([]*model.InviteLink, error)
+	FindByLink(ctx context.Context, tx *gorm.DB, inviteLink string) (*model.InviteLink, error)
+	MarkUsed(ctx context.Context, tx *gorm.DB, inviteLink string, userId string) error
+	MarkRevoked(ctx context.Context, tx *gorm.DB, ids []int64) error
+}
+
+type GroupMembershipRepository interface {
+	Upsert(ctx context.Context, tx *gorm.DB, membership *model.GroupMembership) error
+	FindByCustomerId(ctx context.Context, tx *gorm.DB, customerId string) ([]*model.GroupMembership, error)
+}
+
+type MemberStatusHistoryRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, history *model.MemberStatusHistory) error
+}
+
+type LinkSharingIncidentRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, incident *model.LinkSharingIncident) error
+}
+
+type CaptchaChallengeRepository interface {
+	Upsert(ctx context.Context, tx *gorm.DB, challenge *model.CaptchaChallenge) error
+	FindPending(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) (*model.CaptchaChallenge, error)
+	FindExpired(ctx context.Context, tx *gorm.DB, before time.Time, limit int) ([]*model.CaptchaChallenge, error)
+	Resolve(ctx context.Context, tx *gorm.DB, id int64, status common.CaptchaStatus) error
+}
+
+type UserViolationRepository interface {
+	FindForUpdate(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) (*model.UserViolation, error)
+	Save(ctx context.Context, tx *gorm.DB, violation *model.UserViolation) error
+	FindAll(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) ([]*model.UserViolation, error)
+	Clear(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) error
+	Forgive(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) error
+}
+
+type ModerationRuleRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, rule *model.ModerationRule) error
+	Update(ctx context.Context, tx *gorm.DB, rule *model.ModerationRule) error
+	Delete(ctx context.Context, tx *gorm.DB, id int64) error
+	FindById(ctx context.Context, tx *gorm.DB, id int64) (*model.ModerationRule, error)
+	FindAll(ctx context.Context, tx *gorm.DB, groupId int64) ([]*model.ModerationRule, error)
+	FindEnabled(ctx context.Context, tx *gorm.DB) ([]*model.ModerationRule, error)
+}
+
+type TrustedUserRepository interface {
+	Upsert(ctx context.Context, tx *gorm.DB, user *model.TrustedUser) error
+	Delete(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) error
+	FindAll(ctx context.Context, tx *gorm.DB, groupId int64) ([]*model.TrustedUser, error)
+}
+
+type ModerationLogRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, entry *model.ModerationLog) error
+	FindForUpdate(ctx context.Context, tx *gorm.DB, id int64) (*model.ModerationLog, error)
+	FindAll(ctx context.Context, tx *gorm.DB, filter *model.ModerationLogFilter, page, limit int) ([]*model.ModerationLog, int64, error)
+	MarkFalsePositive(ctx context.Context, tx *gorm.DB, id int64, reviewedAt time.Time) error
+	CountByRule(ctx context.Context, tx *gorm.DB, groupId int64, shadow bool, from, to time.Time) ([]*model.RuleHitReport, error)
+	FindSamples(ctx context.Context, tx *gorm.DB, groupId int64, ruleId *int64, action common.RuleAction, shadow bool, from, to time.Time, limit int) ([]*model.ModerationLog, error)
+}
+
+type DelayedActionRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, action *model.DelayedAction) error
+	FindDue(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]*model.DelayedAction, error)
+	Postpone(ctx context.Context, tx *gorm.DB, ids []int64, runAt time.Time) error
+	Finish(ctx context.Context, tx *gorm.DB, id int64, status common.DelayedActionStatus, lastError string) error
+}
+
+type AnnouncementRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, announcement *model.Announcement) error
+	Update(ctx context.Context, tx *gorm.DB, announcement *model.Announcement) error
+	Delete(ctx context.Context, tx *gorm.DB, id int64) error
+	FindById(ctx context.Context, tx *gorm.DB, id int64) (*model.Announcement, error)
+	FindAll(ctx context.Context, tx *gorm.DB, groupId int64) ([]*model.Announcement, error)
+	FindDue(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]*model.Announcement, error)
+	Reschedule(ctx context.Context, tx *gorm.DB, id int64, nextRunAt *time.Time) error
+}
+
+type AnnouncementRunRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, run *model.AnnouncementRun) error
+	FindAll(ctx context.Context, tx *gorm.DB, announcementId int64, page, limit int) ([]*model.AnnouncementRun, int64, error)
+}
+
+type BroadcastJobRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, job *model.BroadcastJob) error
+	FindById(ctx context.Context, tx *gorm.DB, id int64) (*model.BroadcastJob, error)
+	FindForUpdate(ctx context.Context, tx *gorm.DB, id int64) (*model.BroadcastJob, error)
+	FindAll(ctx context.Context, tx *gorm.DB, page, limit int) ([]*model.BroadcastJob, int64, error)
+	FindActive(ctx context.Context, tx *gorm.DB) ([]*model.BroadcastJob, error)
+	UpdateStatus(ctx context.Context, tx *gorm.DB, id int64, status common.BroadcastStatus, finishedAt *time.Time) error
+}
+
+type BroadcastRecipientRepository interface {
+	CreateInBatches(ctx context.Context, tx *gorm.DB, batchSize int, recipients []*model.BroadcastRecipient) error
+	FindDue(ctx context.Context, tx *gorm.DB, jobId int64, now time.Time, limit int) ([]*model.BroadcastRecipient, error)
+	Postpone(ctx context.Context, tx *gorm.DB, ids []int64, nextAttemptAt time.Time) error
+	Finish(ctx context.Context, tx *gorm.DB, id int64, status common.BroadcastRecipientStatus, lastError string, sentAt *time.Time) error
+	SkipPending(ctx context.Context, tx *gorm.DB, jobId int64) error
+	CountByStatus(ctx context.Context, tx *gorm.DB, jobId int64) (map[common.BroadcastRecipientStatus]int64, error)
+}
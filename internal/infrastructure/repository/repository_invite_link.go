@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+type InviteLinkRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewInviteLinkRepository(db *gorm.DB, log logger.Logger) InviteLinkRepository {
+	return &InviteLinkRepositoryImpl{db: db, log: log}
+}
+
+func (r *InviteLinkRepositoryImpl) Create(ctx context.Context, tx *gorm.DB, link *model.InviteLink) (*model.InviteLink, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	if err := db.WithContext(ctx).Create(link).Error; err != nil {
+		return nil, fmt.Errorf("failed to create invite link: %w", err)
+	}
+	return link, nil
+}
+
+// FindOutstandingByCustomerId returns the unused links of a customer, newest first
+func (r *InviteLinkRepositoryImpl) FindOutstandingByCustomerId(ctx context.Context, tx *gorm.DB, customerId string) ([]*model.InviteLink, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var links []*model.InviteLink
+	result := db.WithContext(ctx).
+		Where("customer_id = ? AND status = ?", customerId, common.InviteLinkUnused).
+		Order("created_at DESC").
+		Find(&links)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find invite links with customer_id=%s: %w", customerId, result.Error)
+	}
+	return links, nil
+}
+
+// FindExpired returns at most limit unused links expired before the given time
+func (r *InviteLinkRepositoryImpl) FindExpired(ctx context.Context, tx *gorm.DB, before time.Time, limit int) ([]*model.InviteLink, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var links []*model.InviteLink
+	result := db.WithContext(ctx).
+		Where("status = ? AND expire_at <= ?", common.InviteLinkUnused, before).
+		Order("expire_at").
+		Limit(limit).
+		Find(&links)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find expired invite links: %w", result.Error)
+	}
+	return links, nil
+}
+
+func (r *InviteLinkRepositoryImpl) FindByLink(ctx context.Context, tx *gorm.DB, inviteLink string) (*model.InviteLink, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var link model.InviteLink
+	result := db.WithContext(ctx).
+		Where("invite_link = ?", inviteLink).
+		First(&link)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to find invite link: %w", result.Error)
+	}
+	return &link, nil
+}
+
+// MarkUsed marks an unused link as used by the social user, ErrRecordNotFound is returned for unknown links
+func (r *InviteLinkRepositoryImpl) MarkUsed(ctx context.Context, tx *gorm.DB, inviteLink string, userId string) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).
+		Model(&model.InviteLink{}).
+		Where("invite_link = ? AND status = ?", inviteLink, common.InviteLinkUnused).
+		Updates(map[string]interface{}{
+			"status":  common.InviteLinkUsed,
+			"used_by": userId,
+			"used_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark invite link used: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *InviteLinkRepositoryImpl) MarkRevoked(ctx context.Context, tx *gorm.DB, ids []int64) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	result := db.WithContext(ctx).
+		Model(&model.InviteLink{}).
+		Where("id IN ? AND status = ?", ids, common.InviteLinkUnused).
+		Updates(map[string]interface{}{
+			"status":     common.InviteLinkRevoked,
+			"revoked_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark invite links revoked: %w", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+type LinkSharingIncidentRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewLinkSharingIncidentRepository(db *gorm.DB, log logger.Logger) LinkSharingIncidentRepository {
+	return &LinkSharingIncidentRepositoryImpl{db: db, log: log}
+}
+
+func (r *LinkSharingIncidentRepositoryImpl) Create(ctx context.Context, tx *gorm.DB, incident *model.LinkSharingIncident) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	if err := db.WithContext(ctx).Create(incident).Error; err != nil {
+		return fmt.Errorf("failed to create link sharing incident with customer_id=%s: %w", incident.CustomerID, err)
+	}
+	return nil
+}
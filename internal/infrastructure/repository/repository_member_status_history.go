@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+type MemberStatusHistoryRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewMemberStatusHistoryRepository(db *gorm.DB, log logger.Logger) MemberStatusHistoryRepository {
+	return &MemberStatusHistoryRepositoryImpl{db: db, log: log}
+}
+
+func (r *MemberStatusHistoryRepositoryImpl) Create(ctx context.Context, tx *gorm.DB, history *model.MemberStatusHistory) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	if err := db.WithContext(ctx).Create(history).Error; err != nil {
+		return fmt.Errorf("failed to create member status history: %w", err)
+	}
+	return nil
+}
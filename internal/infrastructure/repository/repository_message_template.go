@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+type MessageTemplateRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewMessageTemplateRepository(db *gorm.DB, log logger.Logger) MessageTemplateRepository {
+	return &MessageTemplateRepositoryImpl{db: db, log: log}
+}
+
+// FindActive returns the latest version of every template key and language
+func (r *MessageTemplateRepositoryImpl) FindActive(ctx context.Context, tx *gorm.DB) ([]*model.MessageTemplate, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	latest := db.Table("message_templates").
+		Select("template_key, language, MAX(version) AS version").
+		Group("template_key, language")
+
+	var templates []*model.MessageTemplate
+	result := db.WithContext(ctx).
+		Table("message_templates t").
+		Select("t.*").
+		Joins("JOIN (?) l ON t.template_key = l.template_key AND t.language = l.language AND t.version = l.version", latest).
+		Order("t.template_key, t.language").
+		Find(&templates)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find active message templates: %w", result.Error)
+	}
+	return templates, nil
+}
+
+func (r *MessageTemplateRepositoryImpl) FindVersions(ctx context.Context, tx *gorm.DB, key string, language string) ([]*model.MessageTemplate, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var templates []*model.MessageTemplate
+	result := db.WithContext(ctx).
+		Where("template_key = ? AND language = ?", key, language).
+		Order("version DESC").
+		Find(&templates)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find message template versions with key=%s, language=%s, error=%w", key, language, result.Error)
+	}
+	return templates, nil
+}
+
+// CreateVersion stores template as the next version of its key and language
+func (r *MessageTemplateRepositoryImpl) CreateVersion(ctx context.Context, tx *gorm.DB, template *model.MessageTemplate) (*model.MessageTemplate, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var version int
+	result := db.WithContext(ctx).
+		Model(&model.MessageTemplate{}).
+		Select("COALESCE(MAX(version), 0)").
+		Where("template_key = ? AND language = ?", template.TemplateKey, template.Language).
+		Scan(&version)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find latest message template version: %w", result.Error)
+	}
+
+	template.Version = version + 1
+	if err := db.WithContext(ctx).Create(template).Error; err != nil {
+		if IsUniqueViolation(err) {
+			return nil, fmt.Errorf("message template version %d already exists: %w", template.Version, err)
+		}
+		return nil, fmt.Errorf("failed to create message template: %w", err)
+	}
+	return template, nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+type ModerationLogRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewModerationLogRepository(db *gorm.DB, log logger.Logger) ModerationLogRepository {
+	return &ModerationLogRepositoryImpl{db: db, log: log}
+}
+
+func (r *ModerationLogRepositoryImpl) Create(ctx context.Context, tx *gorm.DB, entry *model.ModerationLog) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	if err := db.WithContext(ctx).Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create moderation log with group_id=%d, user_id=%d, error=%w", entry.GroupID, entry.UserID, err)
+	}
+	return nil
+}
+
+// FindForUpdate locks the log entry, it has to be called inside a transaction
+func (r *ModerationLogRepositoryImpl) FindForUpdate(ctx context.Context, tx *gorm.DB, id int64) (*model.ModerationLog, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var entry model.ModerationLog
+	result := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&entry, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to find moderation log with id=%d: %w", id, result.Error)
+	}
+	return &entry, nil
+}
+
+// FindAll returns a page of the log entries matching the filter, newest first, with the total count
+func (r *ModerationLogRepositoryImpl) FindAll(ctx context.Context, tx *gorm.DB, filter *model.ModerationLogFilter, page, limit int) ([]*model.ModerationLog, int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	query := db.WithContext(ctx).Model(&model.ModerationLog{})
+	if filter.GroupId != 0 {
+		query = query.Where("group_id = ?", filter.GroupId)
+	}
+	if filter.TopicId != 0 {
+		query = query.Where("topic_id = ?", filter.TopicId)
+	}
+	if filter.UserId != 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+	if filter.RuleId != 0 {
+		query = query.Where("rule_id = ?", filter.RuleId)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Shadow != nil {
+		query = query.Where("shadow = ?", *filter.Shadow)
+	}
+	if filter.FalsePositive != nil {
+		query = query.Where("false_positive = ?", *filter.FalsePositive)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count moderation logs: %w", err)
+	}
+
+	var entries []*model.ModerationLog
+	err := query.
+		Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find moderation logs: %w", err)
+	}
+	return entries, total, nil
+}
+
+func (r *ModerationLogRepositoryImpl) MarkFalsePositive(ctx context.Context, tx *gorm.DB, id int64, reviewedAt time.Time) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).
+		Model(&model.ModerationLog{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"false_positive": true,
+			"reviewed_at":    reviewedAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark moderation log as false positive with id=%d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// CountByRule counts the log entries per rule within [from, to), most hit first, groupId 0 matches every group
+func (r *ModerationLogRepositoryImpl) CountByRule(ctx context.Context, tx *gorm.DB, groupId int64, shadow bool, from, to time.Time) ([]*model.RuleHitReport, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	query := db.WithContext(ctx).
+		Model(&model.ModerationLog{}).
+		Select("rule_id, rule_name, action, COUNT(*) AS hits").
+		Where("shadow = ? AND created_at >= ? AND created_at < ?", shadow, from, to)
+	if groupId != 0 {
+		query = query.Where("group_id = ?", groupId)
+	}
+
+	var reports []*model.RuleHitReport
+	err := query.
+		Group("rule_id, rule_name, action").
+		Order("hits DESC").
+		Scan(&reports).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count moderation logs by rule: %w", err)
+	}
+	return reports, nil
+}
+
+// FindSamples returns the latest log entries of the rule and action within [from, to), a nil rule id stands for
+// the command patterns
+func (r *ModerationLogRepositoryImpl) FindSamples(ctx context.Context, tx *gorm.DB, groupId int64, ruleId *int64, action common.RuleAction, shadow bool, from, to time.Time, limit int) ([]*model.ModerationLog, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	query := db.WithContext(ctx).
+		Where("shadow = ? AND action = ? AND created_at >= ? AND created_at < ?", shadow, action, from, to)
+	if ruleId == nil {
+		query = query.Where("rule_id IS NULL")
+	} else {
+		query = query.Where("rule_id = ?", *ruleId)
+	}
+	if groupId != 0 {
+		query = query.Where("group_id = ?", groupId)
+	}
+
+	var samples []*model.ModerationLog
+	err := query.
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&samples).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find moderation log samples: %w", err)
+	}
+	return samples, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+type ModerationRuleRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewModerationRuleRepository(db *gorm.DB, log logger.Logger) ModerationRuleRepository {
+	return &ModerationRuleRepositoryImpl{db: db, log: log}
+}
+
+func (r *ModerationRuleRepositoryImpl) Create(ctx context.Context, tx *gorm.DB, rule *model.ModerationRule) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	if err := db.WithContext(ctx).Create(rule).Error; err != nil {
+		return fmt.Errorf("failed to create moderation rule: %w", err)
+	}
+	return nil
+}
+
+func (r *ModerationRuleRepositoryImpl) Update(ctx context.Context, tx *gorm.DB, rule *model.ModerationRule) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).
+		Model(rule).
+		Select("group_id", "topic_id", "name", "match_type", "pattern", "action", "mute_duration", "priority", "enabled", "shadow").
+		Updates(rule)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update moderation rule with id=%d: %w", rule.ID, result.Error)
+	}
+	return nil
+}
+
+func (r *ModerationRuleRepositoryImpl) Delete(ctx context.Context, tx *gorm.DB, id int64) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).Delete(&model.ModerationRule{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete moderation rule with id=%d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *ModerationRuleRepositoryImpl) FindById(ctx context.Context, tx *gorm.DB, id int64) (*model.ModerationRule, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var rule model.ModerationRule
+	result := db.WithContext(ctx).First(&rule, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to find moderation rule with id=%d: %w", id, result.Error)
+	}
+	return &rule, nil
+}
+
+// FindAll returns the rules of the group, every rule when groupId is 0
+func (r *ModerationRuleRepositoryImpl) FindAll(ctx context.Context, tx *gorm.DB, groupId int64) ([]*model.ModerationRule, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	query := db.WithContext(ctx)
+	if groupId != 0 {
+		query = query.Where("group_id = ?", groupId)
+	}
+
+	var rules []*model.ModerationRule
+	if err := query.Order("group_id, topic_id, priority DESC, id").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to find moderation rules: %w", err)
+	}
+	return rules, nil
+}
+
+// FindEnabled returns the enabled rules ordered by evaluation priority
+func (r *ModerationRuleRepositoryImpl) FindEnabled(ctx context.Context, tx *gorm.DB) ([]*model.ModerationRule, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var rules []*model.ModerationRule
+	result := db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Order("priority DESC, id").
+		Find(&rules)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find enabled moderation rules: %w", result.Error)
+	}
+	return rules, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+type SocialUserLanguageRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewSocialUserLanguageRepository(db *gorm.DB, log logger.Logger) SocialUserLanguageRepository {
+	return &SocialUserLanguageRepositoryImpl{db: db, log: log}
+}
+
+func (r *SocialUserLanguageRepositoryImpl) FindByUserId(ctx context.Context, tx *gorm.DB, socialId int, userId string) (*model.SocialUserLanguage, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	var language model.SocialUserLanguage
+	result := db.WithContext(ctx).
+		Where("social_id = ? AND user_id = ?", socialId, userId).
+		First(&language)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to find user language with user_id=%s, error=%w", userId, result.Error)
+	}
+	return &language, nil
+}
+
+func (r *SocialUserLanguageRepositoryImpl) Upsert(ctx context.Context, tx *gorm.DB, language *model.SocialUserLanguage) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "social_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"language", "updated_at"}),
+		}).
+		Create(language)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save user language with user_id=%s, error=%w", language.UserID, result.Error)
+	}
+	return nil
+}
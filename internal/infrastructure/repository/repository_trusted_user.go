@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+)
+
+type TrustedUserRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewTrustedUserRepository(db *gorm.DB, log logger.Logger) TrustedUserRepository {
+	return &TrustedUserRepositoryImpl{db: db, log: log}
+}
+
+func (r *TrustedUserRepositoryImpl) Upsert(ctx context.Context, tx *gorm.DB, user *model.TrustedUser) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "group_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"note"}),
+		}).
+		Create(user)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save trusted user with group_id=%d, user_id=%d, error=%w", user.GroupID, user.UserID, result.Error)
+	}
+	return nil
+}
+
+func (r *TrustedUserRepositoryImpl) Delete(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).
+		Where("group_id = ? AND user_id = ?", groupId, userId).
+		Delete(&model.TrustedUser{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete trusted user with group_id=%d, user_id=%d, error=%w", groupId, userId, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindAll returns the trusted users of the group, every trusted user when groupId is 0
+func (r *TrustedUserRepositoryImpl) FindAll(ctx context.Context, tx *gorm.DB, groupId int64) ([]*model.TrustedUser, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	query := db.WithContext(ctx)
+	if groupId != 0 {
+		query = query.Where("group_id = ?", groupId)
+	}
+
+	var users []*model.TrustedUser
+	if err := query.Order("group_id, user_id").Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to find trusted users: %w", err)
+	}
+	return users, nil
+}
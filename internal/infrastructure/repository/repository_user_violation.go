@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ohmycontrolcenter.tech/omcc/internal/domain/model"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+type UserViolationRepositoryImpl struct {
+	db  *gorm.DB
+	log logger.Logger
+}
+
+func NewUserViolationRepository(db *gorm.DB, log logger.Logger) UserViolationRepository {
+	return &UserViolationRepositoryImpl{db: db, log: log}
+}
+
+// FindForUpdate locks the violation row of the user, creating it first when the user has none,
+// it has to be called inside a transaction
+func (r *UserViolationRepositoryImpl) FindForUpdate(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) (*model.UserViolation, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.UserViolation{GroupID: groupId, UserID: userId, LastViolationAt: time.Now()})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to create user violation with group_id=%d, user_id=%d, error=%w", groupId, userId, result.Error)
+	}
+
+	var violation model.UserViolation
+	result = db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("group_id = ? AND user_id = ?", groupId, userId).
+		First(&violation)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find user violation with group_id=%d, user_id=%d, error=%w", groupId, userId, result.Error)
+	}
+	return &violation, nil
+}
+
+func (r *UserViolationRepositoryImpl) Save(ctx context.Context, tx *gorm.DB, violation *model.UserViolation) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	if err := db.WithContext(ctx).Save(violation).Error; err != nil {
+		return fmt.Errorf("failed to save user violation with id=%d: %w", violation.ID, err)
+	}
+	return nil
+}
+
+// FindAll returns the violations filtered by group and user, zero values match everything.
+// The strikes are the ones stored at the last violation, before the decay
+func (r *UserViolationRepositoryImpl) FindAll(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) ([]*model.UserViolation, error) {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	query := db.WithContext(ctx).Where("strikes > 0")
+	if groupId != 0 {
+		query = query.Where("group_id = ?", groupId)
+	}
+	if userId != 0 {
+		query = query.Where("user_id = ?", userId)
+	}
+
+	var violations []*model.UserViolation
+	if err := query.Order("last_violation_at DESC").Find(&violations).Error; err != nil {
+		return nil, fmt.Errorf("failed to find user violations: %w", err)
+	}
+	return violations, nil
+}
+
+// Clear resets the strikes of the user in the group, ErrRecordNotFound is returned when the user has none
+func (r *UserViolationRepositoryImpl) Clear(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).
+		Model(&model.UserViolation{}).
+		Where("group_id = ? AND user_id = ? AND strikes > 0", groupId, userId).
+		Update("strikes", 0)
+	if result.Error != nil {
+		return fmt.Errorf("failed to clear user violations with group_id=%d, user_id=%d, error=%w", groupId, userId, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// Forgive removes one strike of the user in the group
+func (r *UserViolationRepositoryImpl) Forgive(ctx context.Context, tx *gorm.DB, groupId int64, userId int64) error {
+	db := tx
+	if db == nil {
+		db = r.db
+	}
+
+	result := db.WithContext(ctx).
+		Model(&model.UserViolation{}).
+		Where("group_id = ? AND user_id = ? AND strikes > 0", groupId, userId).
+		Update("strikes", gorm.Expr("strikes - 1"))
+	if result.Error != nil {
+		return fmt.Errorf("failed to forgive user violation with group_id=%d, user_id=%d, error=%w", groupId, userId, result.Error)
+	}
+	return nil
+}
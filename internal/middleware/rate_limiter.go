@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"sync"
+	"time"
+)
+
+const rateLimitPruneInterval = 10 * time.Minute
+
+// RateDecision result of a rate limited call
+type RateDecision struct {
+	Allowed bool
+	// RetryAfter how long the user has to wait before the next call is allowed
+	RetryAfter time.Duration
+	// Blocked the user is blocked, Notify is only set on the call that blocked them
+	Blocked bool
+	Notify  bool
+}
+
+type rateKey struct {
+	userId  int64
+	command string
+}
+
+type bucket struct {
+	tokens   float64
+	updated  time.Time
+	lastCall time.Time
+}
+
+type offender struct {
+	violations    int
+	lastViolation time.Time
+	blockedUntil  time.Time
+}
+
+// RateLimiter token bucket with cooldown per user and command, users throttled MaxViolations
+// times in a row are blocked for BlockDuration
+type RateLimiter struct {
+	mu        sync.Mutex
+	cfg       config.RateLimitConfig
+	buckets   map[rateKey]*bucket
+	offenders map[int64]*offender
+	pruned    time.Time
+	now       func() time.Time
+}
+
+func NewRateLimiter(cfg config.RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		cfg:       cfg,
+		buckets:   make(map[rateKey]*bucket),
+		offenders: make(map[int64]*offender),
+		now:       time.Now,
+	}
+}
+
+// Allow takes a token of the command for the user
+func (r *RateLimiter) Allow(userId int64, command string) RateDecision {
+	if !r.cfg.Enabled {
+		return RateDecision{Allowed: true}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.prune(now)
+
+	o := r.offenders[userId]
+	if o != nil && now.Before(o.blockedUntil) {
+		return RateDecision{RetryAfter: o.blockedUntil.Sub(now), Blocked: true}
+	}
+
+	rule := r.cfg.Rule(command)
+	key := rateKey{userId: userId, command: command}
+	b := r.buckets[key]
+	if b == nil {
+		b = &bucket{tokens: float64(rule.Burst), updated: now}
+		r.buckets[key] = b
+	}
+	b.refill(rule, now)
+
+	var retryAfter time.Duration
+	if !b.lastCall.IsZero() && now.Sub(b.lastCall) < rule.Cooldown {
+		retryAfter = rule.Cooldown - now.Sub(b.lastCall)
+	}
+	bucketed := rule.Burst > 0 && rule.Refill > 0
+	if bucketed && b.tokens < 1 {
+		retryAfter = max(retryAfter, time.Duration((1-b.tokens)*float64(rule.Refill)))
+	}
+	if retryAfter == 0 {
+		if bucketed {
+			b.tokens--
+		}
+		b.lastCall = now
+		if o != nil {
+			o.violations = 0
+		}
+		return RateDecision{Allowed: true}
+	}
+
+	return r.violate(userId, now, retryAfter)
+}
+
+func (r *RateLimiter) violate(userId int64, now time.Time, retryAfter time.Duration) RateDecision {
+	o := r.offenders[userId]
+	if o == nil {
+		o = &offender{}
+		r.offenders[userId] = o
+	}
+	o.violations++
+	o.lastViolation = now
+
+	if r.cfg.MaxViolations > 0 && o.violations >= r.cfg.MaxViolations && r.cfg.BlockDuration > 0 {
+		o.violations = 0
+		o.blockedUntil = now.Add(r.cfg.BlockDuration)
+		return RateDecision{RetryAfter: r.cfg.BlockDuration, Blocked: true, Notify: true}
+	}
+	return RateDecision{RetryAfter: retryAfter, Notify: true}
+}
+
+// prune drops the full buckets and the offenders neither blocked nor recently throttled
+func (r *RateLimiter) prune(now time.Time) {
+	if now.Sub(r.pruned) < rateLimitPruneInterval {
+		return
+	}
+	r.pruned = now
+	for key, b := range r.buckets {
+		rule := r.cfg.Rule(key.command)
+		b.refill(rule, now)
+		if b.tokens >= float64(rule.Burst) && now.Sub(b.lastCall) >= rule.Cooldown {
+			delete(r.buckets, key)
+		}
+	}
+	for userId, o := range r.offenders {
+		if now.After(o.blockedUntil) && now.Sub(o.lastViolation) >= rateLimitPruneInterval {
+			delete(r.offenders, userId)
+		}
+	}
+}
+
+func (b *bucket) refill(rule config.RateLimitRule, now time.Time) {
+	if rule.Refill > 0 {
+		b.tokens += float64(now.Sub(b.updated)) / float64(rule.Refill)
+	}
+	b.tokens = min(b.tokens, float64(rule.Burst))
+	b.updated = now
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"github.com/stretchr/testify/assert"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.now = f.now.Add(d)
+}
+
+func newTestLimiter(cfg config.RateLimitConfig) (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	limiter := NewRateLimiter(cfg)
+	limiter.now = clock.Now
+	return limiter, clock
+}
+
+func TestRateLimiter_TokenBucket(t *testing.T) {
+	limiter, clock := newTestLimiter(config.RateLimitConfig{
+		Enabled:       true,
+		RateLimitRule: config.RateLimitRule{Burst: 2, Refill: 10 * time.Second},
+	})
+
+	assert.True(t, limiter.Allow(1, "status").Allowed)
+	assert.True(t, limiter.Allow(1, "status").Allowed)
+
+	decision := limiter.Allow(1, "status")
+	assert.False(t, decision.Allowed)
+	assert.True(t, decision.Notify)
+	assert.Equal(t, 10*time.Second, decision.RetryAfter)
+
+	// buckets are kept per user and per command
+	assert.True(t, limiter.Allow(2, "status").Allowed)
+	assert.True(t, limiter.Allow(1, "help").Allowed)
+
+	clock.Advance(10 * time.Second)
+	assert.True(t, limiter.Allow(1, "status").Allowed)
+	assert.False(t, limiter.Allow(1, "status").Allowed)
+}
+
+func TestRateLimiter_CommandCooldown(t *testing.T) {
+	limiter, clock := newTestLimiter(config.RateLimitConfig{
+		Enabled:       true,
+		RateLimitRule: config.RateLimitRule{Burst: 5, Refill: time.Second},
+		Commands: map[string]config.RateLimitRule{
+			"verify": {Cooldown: 30 * time.Second},
+		},
+	})
+
+	assert.True(t, limiter.Allow(1, "verify").Allowed)
+	clock.Advance(10 * time.Second)
+
+	decision := limiter.Allow(1, "verify")
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 20*time.Second, decision.RetryAfter)
+
+	// commands without an override use the default rule
+	assert.True(t, limiter.Allow(1, "status").Allowed)
+	assert.True(t, limiter.Allow(1, "status").Allowed)
+
+	clock.Advance(20 * time.Second)
+	assert.True(t, limiter.Allow(1, "verify").Allowed)
+}
+
+func TestRateLimiter_BlocksRepeatOffenders(t *testing.T) {
+	limiter, clock := newTestLimiter(config.RateLimitConfig{
+		Enabled:       true,
+		RateLimitRule: config.RateLimitRule{Burst: 1, Refill: time.Minute},
+		MaxViolations: 3,
+		BlockDuration: 10 * time.Minute,
+	})
+
+	assert.True(t, limiter.Allow(1, "volume").Allowed)
+	assert.False(t, limiter.Allow(1, "volume").Blocked)
+	assert.False(t, limiter.Allow(1, "volume").Blocked)
+
+	decision := limiter.Allow(1, "volume")
+	assert.False(t, decision.Allowed)
+	assert.True(t, decision.Blocked)
+	assert.True(t, decision.Notify)
+	assert.Equal(t, 10*time.Minute, decision.RetryAfter)
+
+	// the block applies to every command and is only notified once
+	clock.Advance(5 * time.Minute)
+	decision = limiter.Allow(1, "help")
+	assert.False(t, decision.Allowed)
+	assert.True(t, decision.Blocked)
+	assert.False(t, decision.Notify)
+
+	clock.Advance(5 * time.Minute)
+	assert.True(t, limiter.Allow(1, "volume").Allowed)
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	limiter, _ := newTestLimiter(config.RateLimitConfig{
+		RateLimitRule: config.RateLimitRule{Burst: 1, Refill: time.Minute},
+	})
+
+	for i := 0; i < 10; i++ {
+		assert.True(t, limiter.Allow(1, "verify").Allowed)
+	}
+}
@@ -1,7 +1,11 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"github.com/gin-gonic/gin"
+	"net/http"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
 	"time"
 )
@@ -25,3 +29,39 @@ func LoggerMiddleware(log logger.Logger) gin.HandlerFunc {
 		)
 	}
 }
+
+// TenantMiddleware resolves the tenant of the admin api request from the admin token in its X-Tenant-Token header,
+// the queries of the request only see the data of that tenant. tokens maps the tenant ids to their admin tokens, a
+// single tenant without admin token serves the requests without header
+func TenantMiddleware(tokens map[string]string) gin.HandlerFunc {
+	open := ""
+	if len(tokens) == 1 {
+		for id, token := range tokens {
+			if token == "" {
+				open = id
+			}
+		}
+	}
+
+	return func(c *gin.Context) {
+		token := c.GetHeader(common.TenantTokenHeader)
+		tenantId := open
+		if token != "" {
+			tenantId = ""
+			for id, adminToken := range tokens {
+				if adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1 {
+					tenantId = id
+				}
+			}
+		}
+		if tenantId == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid tenant token",
+			})
+			return
+		}
+
+		c.Request = c.Request.WithContext(database.WithTenant(c.Request.Context(), tenantId))
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"testing"
+)
+
+func serveTenant(tokens map[string]string, token string) (int, string) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/", TenantMiddleware(tokens), func(c *gin.Context) {
+		tenantId, _ := database.TenantFromContext(c.Request.Context())
+		c.String(http.StatusOK, tenantId)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if token != "" {
+		req.Header.Set(common.TenantTokenHeader, token)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w.Code, w.Body.String()
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	tokens := map[string]string{"alpha": "token-a", "beta": "token-b"}
+
+	code, tenant := serveTenant(tokens, "token-b")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "beta", tenant)
+
+	code, _ = serveTenant(tokens, "token-c")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, _ = serveTenant(tokens, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestTenantMiddleware_SingleTenantWithoutToken(t *testing.T) {
+	tokens := map[string]string{common.DefaultTenantID: ""}
+
+	code, tenant := serveTenant(tokens, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, common.DefaultTenantID, tenant)
+
+	code, _ = serveTenant(tokens, "anything")
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
@@ -1,17 +1,31 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	tele "gopkg.in/telebot.v3"
+	"math"
 	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
 	"ohmycontrolcenter.tech/omcc/pkg/exception"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"strings"
 	"time"
 )
 
 type Manager struct {
-	log logger.Logger
+	log       logger.Logger
+	localizer *i18n.Localizer
+	languages LanguageResolver
+	admins    map[int64]struct{}
+	limiter   *RateLimiter
+}
+
+// LanguageResolver resolves the reply language of a social user
+type LanguageResolver interface {
+	Resolve(ctx context.Context, platform common.SocialPlatformType, userId string, languageCode string) i18n.Lang
 }
 
 type Handler struct {
@@ -20,9 +34,18 @@ type Handler struct {
 	DefaultHandler    tele.HandlerFunc
 }
 
-func NewManager(log logger.Logger) *Manager {
+func NewManager(log logger.Logger, localizer *i18n.Localizer, languages LanguageResolver, adminIds []int64,
+	rateLimit config.RateLimitConfig) *Manager {
+	admins := make(map[int64]struct{}, len(adminIds))
+	for _, id := range adminIds {
+		admins[id] = struct{}{}
+	}
 	return &Manager{
-		log: log,
+		log:       log,
+		localizer: localizer,
+		languages: languages,
+		admins:    admins,
+		limiter:   NewRateLimiter(rateLimit),
 	}
 }
 
@@ -54,6 +77,19 @@ func (m *Manager) getHandlerForChatType(handlers Handler, chatType tele.ChatType
 	}
 }
 
+// resolveLanguage 解析发送者语言并写入 context
+func (m *Manager) resolveLanguage(c tele.Context) i18n.Lang {
+	lang := m.localizer.Fallback()
+	if sender := c.Sender(); sender != nil {
+		userId := fmt.Sprintf("%d", sender.ID)
+		if resolved := m.languages.Resolve(context.TODO(), common.Telegram, userId, sender.LanguageCode); resolved != "" {
+			lang = resolved
+		}
+	}
+	c.Set(i18n.ContextKey, lang)
+	return lang
+}
+
 func (m *Manager) logReceived(msgInfo MessageInfo) {
 
 	m.log.Info(fmt.Sprintf("Received Telegram %s message", msgInfo.chatType), msgInfo.fields...)
@@ -82,11 +118,11 @@ func (m *Manager) handleCommandError(cmdErr *exception.CommandError, c tele.Cont
 	m.log.Info("command error",
 		append(msgInfo.fields,
 			logger.String("error_type", fmt.Sprintf("%d", cmdErr.Type)),
-			logger.String("error_message", cmdErr.Message),
+			logger.String("error_message", string(cmdErr.Key)),
 			logger.Duration("duration", duration),
 		)...,
 	)
-	return c.Send(cmdErr.Message)
+	return c.Send(m.localizer.T(i18n.FromContext(c), cmdErr.Key, cmdErr.Args...))
 }
 
 // logSuccess 记录成功日志
@@ -126,12 +162,20 @@ func (m *Manager) TelegramMiddleware(handlers Handler) tele.HandlerFunc {
 		// 记录收到的消息
 		m.logReceived(msgInfo)
 
+		// 解析回复语言
+		lang := m.resolveLanguage(c)
+
 		// 获取对应聊天类型的处理器
 		handler := m.getHandlerForChatType(handlers, c.Chat().Type)
 		if handler == nil {
 			return nil
 		}
 
+		// 私聊命令限流，管理员不受限制
+		if throttled, err := m.throttle(c, lang, msgInfo); throttled {
+			return err
+		}
+
 		start := time.Now()
 		var err error
 
@@ -140,7 +184,7 @@ func (m *Manager) TelegramMiddleware(handlers Handler) tele.HandlerFunc {
 			defer func() {
 				if r := recover(); r != nil {
 					m.logPanic(r, msgInfo, time.Since(start))
-					err = c.Send(common.InternalServerErrorMessage)
+					err = c.Send(m.localizer.T(lang, i18n.MsgInternalServerError))
 				}
 			}()
 			err = handler(c)
@@ -149,3 +193,85 @@ func (m *Manager) TelegramMiddleware(handlers Handler) tele.HandlerFunc {
 		return m.handleError(err, c, msgInfo, time.Since(start))
 	}
 }
+
+// throttle 按用户与命令限流，被限流时回复本地化提示，被封锁期间的请求直接忽略
+func (m *Manager) throttle(c tele.Context, lang i18n.Lang, msgInfo MessageInfo) (bool, error) {
+	command, ok := rateLimitCommand(c)
+	if !ok || m.IsAdmin(c.Sender()) {
+		return false, nil
+	}
+	decision := m.limiter.Allow(c.Sender().ID, command)
+	if decision.Allowed {
+		return false, nil
+	}
+
+	m.log.Warn("telegram command throttled",
+		append(msgInfo.fields,
+			logger.String("command", command),
+			logger.Duration("retry_after", decision.RetryAfter),
+			logger.Any("blocked", decision.Blocked),
+		)...,
+	)
+	if !decision.Notify {
+		if c.Callback() != nil {
+			return true, c.Respond()
+		}
+		return true, nil
+	}
+
+	var notice string
+	if decision.Blocked {
+		notice = m.localizer.T(lang, i18n.MsgRateLimitBlocked, max(1, int(math.Ceil(decision.RetryAfter.Minutes()))))
+	} else {
+		notice = m.localizer.T(lang, i18n.MsgRateLimited, max(1, int(math.Ceil(decision.RetryAfter.Seconds()))))
+	}
+	if c.Callback() != nil {
+		return true, c.Respond(&tele.CallbackResponse{Text: notice, ShowAlert: true})
+	}
+	return true, c.Send(notice)
+}
+
+// rateLimitCommand 私聊中的命令与按钮回调需要限流，群组消息交由群组处理器
+func rateLimitCommand(c tele.Context) (string, bool) {
+	if c.Sender() == nil || c.Chat() == nil || c.Chat().Type != tele.ChatPrivate {
+		return "", false
+	}
+	if callback := c.Callback(); callback != nil {
+		return callback.Unique, true
+	}
+	text := c.Text()
+	if !strings.HasPrefix(text, "/") {
+		return "", false
+	}
+	command, _, _ := strings.Cut(strings.Fields(text)[0], "@")
+	return strings.ToLower(strings.TrimPrefix(command, "/")), true
+}
+
+// IsAdmin 判断用户是否为管理员
+func (m *Manager) IsAdmin(user *tele.User) bool {
+	if user == nil {
+		return false
+	}
+	_, ok := m.admins[user.ID]
+	return ok
+}
+
+// AdminAuthorization 管理员权限校验，需在 TelegramMiddleware 内使用以便错误被本地化
+func (m *Manager) AdminAuthorization(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		if !m.IsAdmin(c.Sender()) {
+			m.log.Warn("unauthorized admin command",
+				logger.Any("Sender", c.Sender()),
+				logger.String("text", c.Text()),
+			)
+			if c.Callback() != nil {
+				_ = c.Respond()
+			}
+			return &exception.CommandError{
+				Key:  i18n.MsgAdminUnauthorized,
+				Type: exception.ErrUnauthorized,
+			}
+		}
+		return next(c)
+	}
+}
@@ -5,22 +5,47 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"net/http"
+	"ohmycontrolcenter.tech/omcc/internal/domain/command"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/template"
 	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
 	"ohmycontrolcenter.tech/omcc/internal/infrastructure/database"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/outbound"
 	"ohmycontrolcenter.tech/omcc/internal/middleware"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
 	"time"
 )
 
+// Tenant the parts of a tenant served by the admin http server
+type Tenant struct {
+	ID string
+	// AdminToken authenticates the admin api requests of the tenant
+	AdminToken      string
+	TemplateService *template.TemplateService
+	// Webhook telegram updates handler, nil unless the webhook is mounted on this server
+	Webhook     http.Handler
+	WebhookPath string
+	// LineWebhook LINE webhook handler, nil unless the LINE channel is enabled
+	LineWebhook     http.Handler
+	LineWebhookPath string
+	// Commands the commands of the bot, run through the admin api
+	Commands *command.Dispatcher
+	// SendQueue outbound telegram messages queue, reported by the metrics endpoint
+	SendQueue *outbound.Queue
+	// Penalties escalation of the moderation violations, the strikes listed by the admin api decay with it
+	Penalties *config.PenaltyConfig
+}
+
 type HTTPServer struct {
 	engine *gin.Engine
-	db     *gorm.DB
-	cfg    *config.Config
-	log    logger.Logger
-	srv    *http.Server
+	// db not bound to a tenant, the admin api queries run with the tenant of the request
+	db      *gorm.DB
+	cfg     *config.Config
+	log     logger.Logger
+	srv     *http.Server
+	tenants []*Tenant
 }
 
-func NewHTTPServer(cfg *config.Config, log logger.Logger) *HTTPServer {
+func NewHTTPServer(cfg *config.Config, log logger.Logger, tenants []*Tenant) *HTTPServer {
 	gin.SetMode(gin.ReleaseMode)
 	engine := gin.New()
 	db, _ := database.NewMySqlClient(&cfg.Database, log)
@@ -28,10 +53,11 @@ func NewHTTPServer(cfg *config.Config, log logger.Logger) *HTTPServer {
 	engine.Use(gin.Recovery(), middleware.LoggerMiddleware(log))
 
 	server := &HTTPServer{
-		engine: engine,
-		db:     db,
-		cfg:    cfg,
-		log:    log,
+		engine:  engine,
+		db:      db,
+		cfg:     cfg,
+		log:     log,
+		tenants: tenants,
 	}
 
 	// route register
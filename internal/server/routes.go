@@ -1,24 +1,100 @@
 package server
 
 import (
+	"github.com/gin-gonic/gin"
 	handler "ohmycontrolcenter.tech/omcc/internal/api/admin"
+	"ohmycontrolcenter.tech/omcc/internal/domain/command"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/announcement"
 	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/customer"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/invite"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/message"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/moderation"
+	"ohmycontrolcenter.tech/omcc/internal/domain/service/admin/template"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/outbound"
+	"ohmycontrolcenter.tech/omcc/internal/middleware"
 )
 
 func (s *HTTPServer) registerRoutes() {
+	adminTokens := make(map[string]string, len(s.tenants))
+	templateServices := make(template.TenantTemplateServices, len(s.tenants))
+	sendQueues := make(map[string]*outbound.Queue, len(s.tenants))
+	dispatchers := make(map[string]*command.Dispatcher, len(s.tenants))
+	penalties := make(map[string]*config.PenaltyConfig, len(s.tenants))
+	for _, tenant := range s.tenants {
+		adminTokens[tenant.ID] = tenant.AdminToken
+		templateServices[tenant.ID] = tenant.TemplateService
+		sendQueues[tenant.ID] = tenant.SendQueue
+		dispatchers[tenant.ID] = tenant.Commands
+		penalties[tenant.ID] = tenant.Penalties
+	}
 
 	customerService := customer.NewCustomerService(s.db, s.log)
 	customerHandler := handler.NewCustomerHandler(customerService, s.log)
+	templateHandler := handler.NewTemplateHandler(templateServices, s.log)
+	inviteLinkHandler := handler.NewInviteLinkHandler(invite.NewInviteLinkService(s.db, s.log), s.log)
+	moderationHandler := handler.NewModerationHandler(moderation.NewModerationService(s.db, penalties, s.log), s.log)
+	announcementHandler := handler.NewAnnouncementHandler(announcement.NewAnnouncementService(s.db, s.log), s.log)
+	broadcastHandler := handler.NewBroadcastHandler(message.NewBroadcastService(s.db, s.log), s.log)
+	metricsHandler := handler.NewMetricsHandler(sendQueues)
+	commandHandler := handler.NewCommandHandler(dispatchers, s.log)
 
 	// API version
 	v1 := s.engine.Group("/v1")
 	{
-		ad := v1.Group("/admin")
+		// every admin request is scoped to the tenant of its admin token
+		ad := v1.Group("/admin", middleware.TenantMiddleware(adminTokens))
 		{
 			ad.GET("/customer", customerHandler.SearchByUID)
 			ad.GET("/customers", customerHandler.GetAllCustomers)
 			ad.PUT("/customer/update", customerHandler.UpdateCustomerStatus)
 			ad.DELETE("/customer/delete", customerHandler.DeleteCustomer)
+
+			ad.GET("/templates", templateHandler.GetAllTemplates)
+			ad.GET("/template/versions", templateHandler.GetTemplateVersions)
+			ad.POST("/template/preview", templateHandler.PreviewTemplate)
+			ad.PUT("/template/update", templateHandler.UpdateTemplate)
+
+			ad.GET("/invite-links", inviteLinkHandler.GetOutstandingLinks)
+
+			ad.GET("/violations", moderationHandler.GetViolations)
+			ad.DELETE("/violations", moderationHandler.ClearViolations)
+
+			ad.GET("/moderation/rules", moderationHandler.GetRules)
+			ad.POST("/moderation/rule", moderationHandler.CreateRule)
+			ad.PUT("/moderation/rule/update", moderationHandler.UpdateRule)
+			ad.DELETE("/moderation/rule/delete", moderationHandler.DeleteRule)
+			ad.GET("/moderation/trusted-users", moderationHandler.GetTrustedUsers)
+			ad.POST("/moderation/trusted-user", moderationHandler.AddTrustedUser)
+			ad.DELETE("/moderation/trusted-user", moderationHandler.RemoveTrustedUser)
+			ad.GET("/moderation/logs", moderationHandler.GetLogs)
+			ad.PUT("/moderation/log/false-positive", moderationHandler.MarkFalsePositive)
+			ad.GET("/moderation/shadow-report", moderationHandler.GetShadowReport)
+
+			ad.GET("/announcements", announcementHandler.GetAnnouncements)
+			ad.POST("/announcement", announcementHandler.CreateAnnouncement)
+			ad.PUT("/announcement/update", announcementHandler.UpdateAnnouncement)
+			ad.DELETE("/announcement/delete", announcementHandler.DeleteAnnouncement)
+			ad.GET("/announcement/runs", announcementHandler.GetRuns)
+
+			ad.GET("/broadcasts", broadcastHandler.GetBroadcasts)
+			ad.POST("/broadcast", broadcastHandler.CreateBroadcast)
+			ad.GET("/broadcast/progress", broadcastHandler.GetProgress)
+			ad.PUT("/broadcast/cancel", broadcastHandler.CancelBroadcast)
+
+			ad.GET("/metrics/send-queue", metricsHandler.GetSendQueue)
+
+			ad.POST("/command", commandHandler.RunCommand)
+		}
+	}
+
+	// telegram and LINE webhooks of every tenant, sharing the middleware and the graceful shutdown of the admin api
+	for _, tenant := range s.tenants {
+		if tenant.Webhook != nil {
+			s.engine.POST(tenant.WebhookPath, gin.WrapH(tenant.Webhook))
+		}
+		if tenant.LineWebhook != nil {
+			s.engine.POST(tenant.LineWebhookPath, gin.WrapH(tenant.LineWebhook))
 		}
 	}
 
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/valyala/fasthttp"
+	"net/url"
+	"ohmycontrolcenter.tech/omcc/internal/infrastructure/config"
+	"ohmycontrolcenter.tech/omcc/pkg/logger"
+	"time"
+)
+
+const (
+	LineSignatureHeader = "X-Line-Signature"
+	LineDefaultBaseUrl  = "https://api.line.me"
+	LineReplyPath       = "/v2/bot/message/reply"
+	LinePushPath        = "/v2/bot/message/push"
+	LineProfilePath     = "/v2/bot/profile/"
+	// LineMaxMessages messages the reply and push apis accept per request
+	LineMaxMessages = 5
+)
+
+// LineWebhook body of the webhook requests of the LINE platform
+type LineWebhook struct {
+	Destination string      `json:"destination"`
+	Events      []LineEvent `json:"events"`
+}
+
+type LineEvent struct {
+	Type       string            `json:"type"`
+	ReplyToken string            `json:"replyToken"`
+	Timestamp  int64             `json:"timestamp"`
+	Source     LineSource        `json:"source"`
+	Message    *LineEventMessage `json:"message,omitempty"`
+}
+
+type LineSource struct {
+	// Type user, group or room
+	Type    string `json:"type"`
+	UserId  string `json:"userId"`
+	GroupId string `json:"groupId,omitempty"`
+	RoomId  string `json:"roomId,omitempty"`
+}
+
+type LineEventMessage struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type LineMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type LineReplyRequest struct {
+	ReplyToken string        `json:"replyToken"`
+	Messages   []LineMessage `json:"messages"`
+}
+
+type LinePushRequest struct {
+	To       string        `json:"to"`
+	Messages []LineMessage `json:"messages"`
+}
+
+type LineProfile struct {
+	UserId      string `json:"userId"`
+	DisplayName string `json:"displayName"`
+	Language    string `json:"language"`
+}
+
+type LineClient struct {
+	client *fasthttp.Client
+	log    logger.Logger
+	cfg    *config.LineConfig
+}
+
+func NewLineClient(cfg *config.LineConfig, log logger.Logger) *LineClient {
+	return &LineClient{
+		client: &fasthttp.Client{
+			MaxConnsPerHost:     100,
+			MaxIdleConnDuration: 30 * time.Second,
+			ReadTimeout:         5 * time.Second,
+			WriteTimeout:        5 * time.Second,
+		},
+		log: log,
+		cfg: cfg,
+	}
+}
+
+// ValidateSignature checks the X-Line-Signature of a webhook body, the base64 HMAC-SHA256 of the body keyed with the
+// channel secret
+func (l *LineClient) ValidateSignature(body []byte, signature string) bool {
+	expected, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || l.cfg.ChannelSecret == "" {
+		return false
+	}
+	return hmac.Equal(expected, SignLineBody(l.cfg.ChannelSecret, body))
+}
+
+// SignLineBody returns the HMAC-SHA256 of the body keyed with the channel secret
+func SignLineBody(secret string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// Reply answers the event of the reply token, a reply token is valid once and shortly after the event
+func (l *LineClient) Reply(replyToken string, messages ...string) error {
+	_, err := l.do(fasthttp.MethodPost, LineReplyPath, &LineReplyRequest{
+		ReplyToken: replyToken,
+		Messages:   textMessages(messages),
+	})
+	return err
+}
+
+// Push sends the messages to the user at any time
+func (l *LineClient) Push(to string, messages ...string) error {
+	_, err := l.do(fasthttp.MethodPost, LinePushPath, &LinePushRequest{
+		To:       to,
+		Messages: textMessages(messages),
+	})
+	return err
+}
+
+// GetProfile returns the display name and the language of the user
+func (l *LineClient) GetProfile(userId string) (*LineProfile, error) {
+	body, err := l.do(fasthttp.MethodGet, LineProfilePath+url.PathEscape(userId), nil)
+	if err != nil {
+		return nil, err
+	}
+	var profile LineProfile
+	if err := json.Unmarshal(body, &profile); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal line profile: %w", err)
+	}
+	return &profile, nil
+}
+
+func (l *LineClient) do(method string, path string, body interface{}) ([]byte, error) {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(l.baseUrl() + path)
+	req.Header.SetMethod(method)
+	req.Header.Set("Authorization", "Bearer "+l.cfg.ChannelAccessToken)
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal body: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.SetBody(jsonBody)
+	}
+
+	startTime := time.Now()
+	if err := l.client.Do(req, resp); err != nil {
+		l.log.Error("Error occurred while invoking line api",
+			logger.String("path", path),
+			logger.Error(err))
+		return nil, err
+	}
+	if resp.StatusCode() != fasthttp.StatusOK {
+		return nil, fmt.Errorf("line api %s answered %d: %s", path, resp.StatusCode(), resp.Body())
+	}
+
+	l.log.Debug("Successfully invoked line api",
+		logger.String("path", path),
+		logger.Duration("elapsedTime", time.Since(startTime)),
+	)
+	return append([]byte(nil), resp.Body()...), nil
+}
+
+func (l *LineClient) baseUrl() string {
+	if l.cfg.BaseUrl == "" {
+		return LineDefaultBaseUrl
+	}
+	return l.cfg.BaseUrl
+}
+
+func textMessages(texts []string) []LineMessage {
+	messages := make([]LineMessage, 0, len(texts))
+	for _, text := range texts {
+		messages = append(messages, LineMessage{Type: "text", Text: text})
+	}
+	return messages
+}
@@ -0,0 +1,145 @@
+// Package linetest stub of the LINE Messaging API to run the LINE bot locally and in tests
+package linetest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"ohmycontrolcenter.tech/omcc/pkg/client"
+	"strings"
+	"sync"
+)
+
+// Server records the replies and pushes it receives and serves the profiles it is given, point
+// line.baseUrl at its URL
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	replies   []client.LineReplyRequest
+	pushes    []client.LinePushRequest
+	profiles  map[string]client.LineProfile
+	failReply bool
+}
+
+func NewServer() *Server {
+	s := &Server{profiles: make(map[string]client.LineProfile)}
+	mux := http.NewServeMux()
+	mux.HandleFunc(client.LineReplyPath, s.reply)
+	mux.HandleFunc(client.LinePushPath, s.push)
+	mux.HandleFunc(client.LineProfilePath, s.profile)
+	s.Server = httptest.NewServer(s.authorized(mux))
+	return s
+}
+
+// SetProfile serves the profile of the user, the users without profile are not found
+func (s *Server) SetProfile(profile client.LineProfile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles[profile.UserId] = profile
+}
+
+// FailReplies answers the replies as with an expired reply token
+func (s *Server) FailReplies(fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failReply = fail
+}
+
+func (s *Server) Replies() []client.LineReplyRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]client.LineReplyRequest(nil), s.replies...)
+}
+
+func (s *Server) Pushes() []client.LinePushRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]client.LinePushRequest(nil), s.pushes...)
+}
+
+// Texts returns the texts replied and pushed, the replies first
+func (s *Server) Texts() []string {
+	var texts []string
+	for _, reply := range s.Replies() {
+		for _, msg := range reply.Messages {
+			texts = append(texts, msg.Text)
+		}
+	}
+	for _, push := range s.Pushes() {
+		for _, msg := range push.Messages {
+			texts = append(texts, msg.Text)
+		}
+	}
+	return texts
+}
+
+// NewWebhookRequest returns a webhook request of the events signed with the channel secret
+func NewWebhookRequest(secret string, target string, events ...client.LineEvent) *http.Request {
+	body, _ := json.Marshal(client.LineWebhook{Events: events})
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	req.Header.Set(client.LineSignatureHeader, base64.StdEncoding.EncodeToString(client.SignLineBody(secret, body)))
+	return req
+}
+
+// TextEvent returns the event of a text sent by the user in the chat with the bot
+func TextEvent(userId string, replyToken string, text string) client.LineEvent {
+	return client.LineEvent{
+		Type:       "message",
+		ReplyToken: replyToken,
+		Source:     client.LineSource{Type: "user", UserId: userId},
+		Message:    &client.LineEventMessage{Id: replyToken, Type: "text", Text: text},
+	}
+}
+
+func (s *Server) authorized(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			http.Error(w, `{"message":"Authentication failed"}`, http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) reply(w http.ResponseWriter, r *http.Request) {
+	var req client.LineReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Messages) > client.LineMaxMessages {
+		http.Error(w, `{"message":"The request body has 1 error(s)"}`, http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failReply {
+		http.Error(w, `{"message":"Invalid reply token"}`, http.StatusBadRequest)
+		return
+	}
+	s.replies = append(s.replies, req)
+	_, _ = w.Write([]byte(`{}`))
+}
+
+func (s *Server) push(w http.ResponseWriter, r *http.Request) {
+	var req client.LinePushRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Messages) > client.LineMaxMessages {
+		http.Error(w, `{"message":"The request body has 1 error(s)"}`, http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pushes = append(s.pushes, req)
+	_, _ = w.Write([]byte(`{}`))
+}
+
+func (s *Server) profile(w http.ResponseWriter, r *http.Request) {
+	userId := strings.TrimPrefix(r.URL.Path, client.LineProfilePath)
+	s.mu.Lock()
+	profile, ok := s.profiles[userId]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, `{"message":"Not found"}`, http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(profile)
+}
@@ -2,8 +2,7 @@ package exception
 
 import (
 	"errors"
-	"fmt"
-	"ohmycontrolcenter.tech/omcc/internal/common"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
 	"ohmycontrolcenter.tech/omcc/internal/infrastructure/repository"
 	"ohmycontrolcenter.tech/omcc/pkg/logger"
 )
@@ -21,43 +20,43 @@ func (h *ErrorHandler) HandleServiceError(err error, context map[string]interfac
 	switch {
 	case errors.Is(err, repository.ErrInvalidUID):
 		return &CommandError{
-			Message: fmt.Sprintf(common.InvalidUidVerifyReplyMessage),
-			Type:    ErrInvalidFormat,
+			Key:  i18n.MsgVerifyInvalidUID,
+			Type: ErrInvalidFormat,
 		}
 	case errors.Is(err, repository.ErrUIDNotFound):
 		return &CommandError{
-			Message: fmt.Sprintf(common.InvalidUidVerifyReplyMessage),
-			Type:    ErrInvalidFormat,
+			Key:  i18n.MsgVerifyInvalidUID,
+			Type: ErrInvalidFormat,
 		}
 	case errors.Is(err, repository.ErrServiceUnavailable):
 		return &CommandError{
-			Message: common.ServerErrorMessage,
-			Type:    ErrServiceUnavailable,
+			Key:  i18n.MsgServerError,
+			Type: ErrServiceUnavailable,
 		}
 	case errors.Is(err, repository.ErrCustomerExists):
 		return &CommandError{
-			Message: "Unknown Error",
-			Type:    ErrInvalidFormat,
+			Key:  i18n.MsgUnknownError,
+			Type: ErrInvalidFormat,
 		}
 	case errors.Is(err, repository.ErrSocialBindingExists):
 		return &CommandError{
-			Message: common.ExistsSocialUserIdVerifyReplyMessage,
-			Type:    ErrInvalidFormat,
+			Key:  i18n.MsgVerifyExistsSocialUser,
+			Type: ErrInvalidFormat,
 		}
 	case errors.Is(err, repository.ErrTradingBindingExists):
 		return &CommandError{
-			Message: common.ExistsUidVerifyReplyMessage,
-			Type:    ErrInvalidFormat,
+			Key:  i18n.MsgVerifyExistsUID,
+			Type: ErrInvalidFormat,
 		}
 	case errors.Is(err, repository.ErrRecordNotFound):
 		return &CommandError{
-			Message: fmt.Sprintf(common.InvalidUidVerifyReplyMessage),
-			Type:    ErrInvalidFormat,
+			Key:  i18n.MsgVerifyInvalidUID,
+			Type: ErrInvalidFormat,
 		}
 	case errors.Is(err, repository.ErrDuplicatedSocialUserError):
 		return &CommandError{
-			Message: fmt.Sprintf(common.DuplicatedUserReplyMessage),
-			Type:    ErrInvalidFormat,
+			Key:  i18n.MsgAccountDuplicatedUser,
+			Type: ErrInvalidFormat,
 		}
 	default:
 		h.log.Error("unexpected error during operation",
@@ -65,8 +64,8 @@ func (h *ErrorHandler) HandleServiceError(err error, context map[string]interfac
 			logger.Any("context", context),
 		)
 		return &CommandError{
-			Message: common.InternalServerErrorMessage,
-			Type:    ErrInternal,
+			Key:  i18n.MsgInternalServerError,
+			Type: ErrInternal,
 		}
 	}
 }
@@ -1,6 +1,9 @@
 package exception
 
-import "errors"
+import (
+	"errors"
+	"ohmycontrolcenter.tech/omcc/internal/common/i18n"
+)
 
 type ErrorType int
 
@@ -8,17 +11,22 @@ const (
 	ErrInvalidFormat ErrorType = iota
 	ErrServiceUnavailable
 	ErrInternal
+	ErrUnauthorized
 )
 
 var (
 	ErrSendingMessage = errors.New("failed to send message")
 )
 
+var defaultLocalizer = i18n.NewLocalizer(i18n.Default)
+
+// CommandError user facing error, Key and Args are localized by the telegram middleware
 type CommandError struct {
-	Message string
-	Type    ErrorType
+	Key  i18n.Key
+	Args []interface{}
+	Type ErrorType
 }
 
 func (e *CommandError) Error() string {
-	return e.Message
+	return defaultLocalizer.T(i18n.Default, e.Key, e.Args...)
 }
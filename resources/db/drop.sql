@@ -1,3 +1,19 @@
+DROP TABLE IF EXISTS broadcast_recipients;
+DROP TABLE IF EXISTS broadcast_jobs;
+DROP TABLE IF EXISTS announcement_runs;
+DROP TABLE IF EXISTS announcements;
+DROP TABLE IF EXISTS delayed_actions;
+DROP TABLE IF EXISTS moderation_logs;
+DROP TABLE IF EXISTS trusted_users;
+DROP TABLE IF EXISTS moderation_rules;
+DROP TABLE IF EXISTS user_violations;
+DROP TABLE IF EXISTS captcha_challenges;
+DROP TABLE IF EXISTS link_sharing_incidents;
+DROP TABLE IF EXISTS member_status_histories;
+DROP TABLE IF EXISTS group_memberships;
+DROP TABLE IF EXISTS invite_links;
+DROP TABLE IF EXISTS message_templates;
+DROP TABLE IF EXISTS social_user_languages;
 DROP TABLE IF EXISTS trading_histories;
 DROP TABLE IF EXISTS customer_trading_bindings;
 DROP TABLE IF EXISTS customer_social_bindings;
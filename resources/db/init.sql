@@ -3,9 +3,11 @@ USE omcc;
 -- 创建客户表
 CREATE TABLE IF NOT EXISTS customers (
     id VARCHAR(36) PRIMARY KEY NOT NULL,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
     username VARCHAR(50),
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_tenant (tenant_id)
 ) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
 --
 CREATE TABLE IF NOT EXISTS social_platforms (
@@ -32,6 +34,7 @@ VALUES ('BINGX');
 --
 CREATE TABLE IF NOT EXISTS customer_social_bindings (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
     customer_id VARCHAR(36) NOT NULL,
     social_id INT NOT NULL,
     user_id VARCHAR(50) NOT NULL,
@@ -44,6 +47,7 @@ CREATE TABLE IF NOT EXISTS customer_social_bindings (
     status ENUM('normal', 'whitelisted', 'blacklisted') NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_tenant (tenant_id),
     INDEX idx_customer (customer_id),
     INDEX idx_social_uid (social_id),
     INDEX idx_user_uid (user_id),
@@ -53,13 +57,14 @@ CREATE TABLE IF NOT EXISTS customer_social_bindings (
 --
 CREATE TABLE customer_trading_bindings (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
     customer_id VARCHAR(36) NOT NULL,
     trading_id INT NOT NULL,
     uid VARCHAR(50) NOT NULL,
     register_time TIMESTAMP,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    UNIQUE KEY uk_trading_uid (trading_id, uid),
+    UNIQUE KEY uk_trading_uid (tenant_id, trading_id, uid),
     INDEX idx_uid (uid),
     FOREIGN KEY (customer_id) REFERENCES customers (id),
     FOREIGN KEY (trading_id) REFERENCES trading_platforms (id)
@@ -67,9 +72,279 @@ CREATE TABLE customer_trading_bindings (
 --
 CREATE TABLE IF NOT EXISTS trading_histories (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
     binding_id BIGINT NOT NULL,
     volume DECIMAL(16, 2) NOT NULL,
     time_period ENUM('daily', 'weekly', 'monthly'),
     trading_date TIMESTAMP NOT NULL,
+    INDEX idx_tenant (tenant_id),
     FOREIGN KEY (binding_id) REFERENCES customer_trading_bindings (id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;;
+--
+CREATE TABLE IF NOT EXISTS social_user_languages (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    social_id INT NOT NULL,
+    user_id VARCHAR(50) NOT NULL,
+    language VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_social_user (tenant_id, social_id, user_id),
+    FOREIGN KEY (social_id) REFERENCES social_platforms (id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS message_templates (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    template_key VARCHAR(50) NOT NULL,
+    language VARCHAR(10) NOT NULL,
+    version INT NOT NULL,
+    content TEXT NOT NULL,
+    parse_mode VARCHAR(20) NOT NULL DEFAULT '',
+    updated_by VARCHAR(50),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_template_version (tenant_id, template_key, language, version)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS invite_links (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    customer_id VARCHAR(36) NOT NULL,
+    group_id BIGINT NOT NULL,
+    invite_link VARCHAR(255) NOT NULL,
+    status ENUM('unused', 'used', 'revoked') NOT NULL DEFAULT 'unused',
+    expire_at TIMESTAMP NOT NULL,
+    used_by VARCHAR(50),
+    used_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_tenant (tenant_id),
+    UNIQUE KEY uk_invite_link (invite_link),
+    INDEX idx_customer_status (customer_id, status),
+    INDEX idx_status_expire (status, expire_at),
+    FOREIGN KEY (customer_id) REFERENCES customers (id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS group_memberships (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    customer_id VARCHAR(36) NOT NULL,
+    social_id INT NOT NULL,
+    user_id VARCHAR(50) NOT NULL,
+    group_id BIGINT NOT NULL,
+    group_title VARCHAR(255),
+    member_status ENUM('creator', 'administrator', 'member', 'restricted', 'left', 'kicked') NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_group_member (tenant_id, social_id, user_id, group_id),
+    INDEX idx_customer (customer_id),
+    FOREIGN KEY (customer_id) REFERENCES customers (id),
+    FOREIGN KEY (social_id) REFERENCES social_platforms (id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS member_status_histories (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    customer_id VARCHAR(36) NOT NULL,
+    social_id INT NOT NULL,
+    user_id VARCHAR(50) NOT NULL,
+    group_id BIGINT NOT NULL,
+    old_status ENUM('creator', 'administrator', 'member', 'restricted', 'left', 'kicked'),
+    new_status ENUM('creator', 'administrator', 'member', 'restricted', 'left', 'kicked') NOT NULL,
+    invite_link VARCHAR(255),
+    changed_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_tenant (tenant_id),
+    INDEX idx_customer_changed (customer_id, changed_at),
+    INDEX idx_group_user (group_id, user_id),
+    FOREIGN KEY (customer_id) REFERENCES customers (id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS link_sharing_incidents (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    customer_id VARCHAR(36) NOT NULL,
+    invite_link_id BIGINT NOT NULL,
+    group_id BIGINT NOT NULL,
+    owner_user_id VARCHAR(50),
+    intruder_user_id VARCHAR(50) NOT NULL,
+    intruder_username VARCHAR(50),
+    removed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_tenant (tenant_id),
+    INDEX idx_customer (customer_id),
+    FOREIGN KEY (customer_id) REFERENCES customers (id),
+    FOREIGN KEY (invite_link_id) REFERENCES invite_links (id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS captcha_challenges (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    group_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    message_id INT,
+    answer INT NOT NULL DEFAULT 0,
+    status ENUM('pending', 'passed', 'failed') NOT NULL DEFAULT 'pending',
+    expire_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_captcha_member (tenant_id, group_id, user_id),
+    INDEX idx_status_expire (status, expire_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS user_violations (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    group_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    username VARCHAR(50),
+    strikes INT NOT NULL DEFAULT 0,
+    last_violation_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_violation_member (tenant_id, group_id, user_id),
+    INDEX idx_user (user_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS moderation_rules (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    group_id BIGINT NOT NULL,
+    topic_id INT NOT NULL DEFAULT 0,
+    name VARCHAR(100) NOT NULL,
+    match_type ENUM('regex', 'keyword', 'domain_allowlist', 'channel_forward') NOT NULL,
+    pattern TEXT NOT NULL,
+    action ENUM('delete', 'warn', 'mute', 'ignore') NOT NULL,
+    mute_duration INT NOT NULL DEFAULT 0,
+    priority INT NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    shadow BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_tenant (tenant_id),
+    INDEX idx_group_topic (group_id, topic_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS trusted_users (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    group_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    note VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_trusted_member (tenant_id, group_id, user_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS moderation_logs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    group_id BIGINT NOT NULL,
+    topic_id INT NOT NULL DEFAULT 0,
+    user_id BIGINT NOT NULL,
+    username VARCHAR(50),
+    message_id INT NOT NULL,
+    text TEXT,
+    rule_id BIGINT,
+    rule_name VARCHAR(100),
+    action ENUM('delete', 'warn', 'mute', 'ignore') NOT NULL,
+    penalty VARCHAR(10) NOT NULL DEFAULT '',
+    strikes INT NOT NULL DEFAULT 0,
+    shadow BOOLEAN NOT NULL DEFAULT FALSE,
+    false_positive BOOLEAN NOT NULL DEFAULT FALSE,
+    reviewed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_tenant (tenant_id),
+    INDEX idx_group_created (group_id, created_at),
+    INDEX idx_user (user_id),
+    INDEX idx_rule (rule_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS delayed_actions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    type ENUM('delete_message', 'unmute', 'revoke_invite_link') NOT NULL,
+    chat_id BIGINT NOT NULL,
+    message_id INT NOT NULL DEFAULT 0,
+    user_id BIGINT NOT NULL DEFAULT 0,
+    payload VARCHAR(255) NOT NULL DEFAULT '',
+    run_at TIMESTAMP NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    status ENUM('pending', 'done', 'failed') NOT NULL DEFAULT 'pending',
+    last_error VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_tenant (tenant_id),
+    INDEX idx_status_run (status, run_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS announcements (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    group_id BIGINT NOT NULL,
+    thread_id INT NOT NULL DEFAULT 0,
+    text TEXT NOT NULL,
+    parse_mode VARCHAR(20) NOT NULL DEFAULT '',
+    image_url VARCHAR(512) NOT NULL DEFAULT '',
+    cron VARCHAR(100) NOT NULL DEFAULT '',
+    run_at TIMESTAMP NULL,
+    next_run_at TIMESTAMP NULL,
+    pin BOOLEAN NOT NULL DEFAULT FALSE,
+    delete_after INT NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_tenant (tenant_id),
+    INDEX idx_enabled_next_run (enabled, next_run_at),
+    INDEX idx_group (group_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS announcement_runs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    announcement_id BIGINT NOT NULL,
+    group_id BIGINT NOT NULL,
+    thread_id INT NOT NULL DEFAULT 0,
+    message_id INT NOT NULL DEFAULT 0,
+    success BOOLEAN NOT NULL DEFAULT FALSE,
+    pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    error VARCHAR(255),
+    scheduled_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_tenant (tenant_id),
+    INDEX idx_announcement_created (announcement_id, created_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS broadcast_jobs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    audience ENUM('all_active', 'deactivated', 'whitelisted', 'tier', 'custom') NOT NULL,
+    filter TEXT,
+    text TEXT NOT NULL,
+    parse_mode VARCHAR(20) NOT NULL DEFAULT '',
+    status ENUM('pending', 'running', 'completed', 'cancelled') NOT NULL DEFAULT 'pending',
+    total INT NOT NULL DEFAULT 0,
+    created_by VARCHAR(50),
+    finished_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_tenant (tenant_id),
+    INDEX idx_status (status)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+--
+CREATE TABLE IF NOT EXISTS broadcast_recipients (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    job_id BIGINT NOT NULL,
+    customer_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(50) NOT NULL,
+    status ENUM('pending', 'sent', 'failed', 'skipped') NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error VARCHAR(255),
+    sent_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_tenant (tenant_id),
+    INDEX idx_job_status_attempt (job_id, status, next_attempt_at)
 ) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
\ No newline at end of file
@@ -0,0 +1,89 @@
+-- adds the tenants to a database created before them, the existing rows belong to the default tenant
+USE omcc;
+--
+ALTER TABLE customers
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tenant (tenant_id);
+--
+ALTER TABLE customer_social_bindings
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tenant (tenant_id);
+--
+ALTER TABLE customer_trading_bindings
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX uk_trading_uid,
+    ADD UNIQUE KEY uk_trading_uid (tenant_id, trading_id, uid);
+--
+ALTER TABLE trading_histories
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tenant (tenant_id);
+--
+ALTER TABLE social_user_languages
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX uk_social_user,
+    ADD UNIQUE KEY uk_social_user (tenant_id, social_id, user_id);
+--
+ALTER TABLE message_templates
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX uk_template_version,
+    ADD UNIQUE KEY uk_template_version (tenant_id, template_key, language, version);
+--
+ALTER TABLE invite_links
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tenant (tenant_id);
+--
+ALTER TABLE group_memberships
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX uk_group_member,
+    ADD UNIQUE KEY uk_group_member (tenant_id, social_id, user_id, group_id);
+--
+ALTER TABLE member_status_histories
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tenant (tenant_id);
+--
+ALTER TABLE link_sharing_incidents
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tenant (tenant_id);
+--
+ALTER TABLE captcha_challenges
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX uk_captcha_member,
+    ADD UNIQUE KEY uk_captcha_member (tenant_id, group_id, user_id);
+--
+ALTER TABLE user_violations
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX uk_violation_member,
+    ADD UNIQUE KEY uk_violation_member (tenant_id, group_id, user_id);
+--
+ALTER TABLE moderation_rules
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tenant (tenant_id);
+--
+ALTER TABLE trusted_users
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX uk_trusted_member,
+    ADD UNIQUE KEY uk_trusted_member (tenant_id, group_id, user_id);
+--
+ALTER TABLE moderation_logs
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tenant (tenant_id);
+--
+ALTER TABLE delayed_actions
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tenant (tenant_id);
+--
+ALTER TABLE announcements
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tenant (tenant_id);
+--
+ALTER TABLE announcement_runs
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tenant (tenant_id);
+--
+ALTER TABLE broadcast_jobs
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tenant (tenant_id);
+--
+ALTER TABLE broadcast_recipients
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tenant (tenant_id);
\ No newline at end of file
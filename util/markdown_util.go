@@ -0,0 +1,225 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markdownV2Reserved characters which must be escaped outside of entities in telegram MarkdownV2
+const markdownV2Reserved = "_*[]()~`>#+-=|{}.!"
+
+// EscapeMarkdownV2 escape all reserved characters, used for plain values inserted into MarkdownV2 text
+func EscapeMarkdownV2(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r == '\\' || strings.ContainsRune(markdownV2Reserved, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ValidateMarkdownV2 check text against the telegram MarkdownV2 rules,
+// reserved characters have to be escaped and entities have to be closed in order
+func ValidateMarkdownV2(text string) error {
+	runes := []rune(text)
+	var open []string
+	top := func() string {
+		if len(open) == 0 {
+			return ""
+		}
+		return open[len(open)-1]
+	}
+	toggle := func(entity string, pos int) error {
+		if top() == entity {
+			open = open[:len(open)-1]
+			return nil
+		}
+		for _, e := range open {
+			if e == entity {
+				return fmt.Errorf("entity %q closed out of order at position %d", entity, pos)
+			}
+		}
+		open = append(open, entity)
+		return nil
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\\' {
+			if i+1 >= len(runes) {
+				return fmt.Errorf("dangling escape character at position %d", i)
+			}
+			i++
+			continue
+		}
+
+		// inside code only ` and \ are special
+		if entity := top(); entity == "```" || entity == "`" {
+			if r != '`' {
+				continue
+			}
+			if entity == "```" && !hasPrefixAt(runes, i, "```") {
+				return fmt.Errorf("character '`' must be escaped inside pre block at position %d", i)
+			}
+			i += len(entity) - 1
+			open = open[:len(open)-1]
+			continue
+		}
+
+		switch r {
+		case '`':
+			if hasPrefixAt(runes, i, "```") {
+				open = append(open, "```")
+				i += 2
+			} else {
+				open = append(open, "`")
+			}
+		case '*', '~':
+			if err := toggle(string(r), i); err != nil {
+				return err
+			}
+		case '_':
+			entity := "_"
+			if hasPrefixAt(runes, i, "__") {
+				entity = "__"
+				i++
+			}
+			if err := toggle(entity, i); err != nil {
+				return err
+			}
+		case '|':
+			if !hasPrefixAt(runes, i, "||") {
+				return fmt.Errorf("character '|' must be escaped at position %d", i)
+			}
+			if err := toggle("||", i); err != nil {
+				return err
+			}
+			i++
+		case '[':
+			open = append(open, "[")
+		case ']':
+			if top() != "[" {
+				return fmt.Errorf("character ']' must be escaped at position %d", i)
+			}
+			open = open[:len(open)-1]
+			if i+1 >= len(runes) || runes[i+1] != '(' {
+				return fmt.Errorf("link at position %d has no url", i)
+			}
+			end, err := linkEnd(runes, i+2)
+			if err != nil {
+				return err
+			}
+			i = end
+		case '>':
+			if i != 0 && runes[i-1] != '\n' {
+				return fmt.Errorf("character '>' must be escaped at position %d", i)
+			}
+		default:
+			if strings.ContainsRune(markdownV2Reserved, r) {
+				return fmt.Errorf("character '%c' must be escaped at position %d", r, i)
+			}
+		}
+	}
+
+	if len(open) > 0 {
+		return fmt.Errorf("entity %q is not closed", top())
+	}
+	return nil
+}
+
+// StripMarkdownV2 plain text of a MarkdownV2 text for the platforms without telegram formatting,
+// the entities are dropped, the escaped characters unescaped and the links written as "text (url)"
+func StripMarkdownV2(text string) string {
+	runes := []rune(text)
+	var b strings.Builder
+	var code string
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\\' && i+1 < len(runes) {
+			i++
+			b.WriteRune(runes[i])
+			continue
+		}
+
+		if code != "" {
+			if r != '`' {
+				b.WriteRune(r)
+				continue
+			}
+			i += len(code) - 1
+			code = ""
+			continue
+		}
+
+		switch {
+		case hasPrefixAt(runes, i, "```"):
+			code = "```"
+			i += 2
+			// the first line of a pre block is its language
+			for j := i + 1; j < len(runes) && runes[j] != '`'; j++ {
+				if runes[j] == '\n' {
+					i = j
+					break
+				}
+			}
+		case r == '`':
+			code = "`"
+		case r == '*' || r == '_' || r == '~' || r == '[':
+			// entity markers
+		case hasPrefixAt(runes, i, "||"):
+			i++
+		case r == '>' && (i == 0 || runes[i-1] == '\n'):
+			// blockquote
+		case r == ']' && i+1 < len(runes) && runes[i+1] == '(':
+			end, err := linkEnd(runes, i+2)
+			if err != nil {
+				b.WriteRune(r)
+				continue
+			}
+			// only ')' and '\\' are escaped in the url
+			b.WriteString(" (")
+			for j := i + 2; j < end; j++ {
+				if runes[j] == '\\' {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			b.WriteRune(')')
+			i = end
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// linkEnd returns the position of ')' closing the link url starting at start
+func linkEnd(runes []rune, start int) (int, error) {
+	for i := start; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			i++
+		case ')':
+			if i == start {
+				return 0, fmt.Errorf("empty link url at position %d", i)
+			}
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("link url at position %d is not closed", start)
+}
+
+func hasPrefixAt(runes []rune, i int, prefix string) bool {
+	p := []rune(prefix)
+	if i+len(p) > len(runes) {
+		return false
+	}
+	for j := range p {
+		if runes[i+j] != p[j] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"ohmycontrolcenter.tech/omcc/internal/common"
+	"testing"
+)
+
+func TestValidateMarkdownV2(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{"plain text", "hello world", false},
+		{"escaped reserved", `price 10\.5\! \(ok\)`, false},
+		{"bold and italic", "*bold* _italic_ __underline__ ~strike~ ||spoiler||", false},
+		{"nested entities", "*bold _italic bold_*", false},
+		{"inline code keeps reserved", "`a.b-c!`", false},
+		{"pre block", "```\n/start - 開始.使用!\n```", false},
+		{"link", `[site](https://example.com/a\)b)`, false},
+		{"blockquote at line start", "line\n>quote", false},
+		{"default help message", common.HelpMessage, false},
+		{"unescaped dot", "hello.", true},
+		{"unclosed bold", "*bold", true},
+		{"bad nesting", "*bold _italic* end_", true},
+		{"single pipe", "a | b", true},
+		{"link without url", "[site]", true},
+		{"unclosed link url", "[site](https://example.com", true},
+		{"dangling escape", `abc\`, true},
+		{"unclosed pre", "```\ncode", true},
+		{"greater than mid line", "a > b", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMarkdownV2(tt.text)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEscapeMarkdownV2(t *testing.T) {
+	escaped := EscapeMarkdownV2(`user_name.1 (vip)! a\b`)
+	assert.Equal(t, `user\_name\.1 \(vip\)\! a\\b`, escaped)
+	assert.NoError(t, ValidateMarkdownV2(escaped))
+}
+
+func TestStripMarkdownV2(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"plain text", "hello world", "hello world"},
+		{"escaped reserved", `price 10\.5\! \(ok\)`, "price 10.5! (ok)"},
+		{"entities", "*bold* _italic_ __underline__ ~strike~ ||spoiler||", "bold italic underline strike spoiler"},
+		{"inline code keeps reserved", "`a_b*c`", "a_b*c"},
+		{"pre block with language", "```go\nfmt.Println(\"a_b\")\n```", "fmt.Println(\"a_b\")\n"},
+		{"link", `[site\_a](https://example.com/a_b\)c)`, "site_a (https://example.com/a_b)c)"},
+		{"blockquote", "line\n>quote", "line\nquote"},
+		{"escaped value", EscapeMarkdownV2(`user_name.1 (vip)! a\b`), `user_name.1 (vip)! a\b`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, StripMarkdownV2(tt.text))
+		})
+	}
+}